@@ -0,0 +1,50 @@
+// Command hash-password prints a password hash for the users in the config or
+// the api_user table. The password is read from stdin so that it stays out of
+// the shell history:
+//
+//	read -s pw && printf %s "$pw" | go run ./cmd/hash-password -algorithm argon2id
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/josephlbailey/alert-service/internal/pkg/password"
+)
+
+func main() {
+	algorithm := flag.String("algorithm", password.Bcrypt, "hash algorithm, bcrypt or argon2id")
+	cost := flag.Int("cost", bcrypt.DefaultCost, "bcrypt cost")
+	flag.Parse()
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("unable to read password: %v", err)
+	}
+
+	pw := strings.TrimRight(string(input), "\r\n")
+	if pw == "" {
+		log.Fatal("password must not be empty")
+	}
+
+	var hash string
+	switch *algorithm {
+	case password.Bcrypt:
+		hash, err = password.HashBcrypt(pw, *cost)
+	case password.Argon2id:
+		hash, err = password.HashArgon2id(pw, password.DefaultArgon2Params)
+	default:
+		log.Fatalf("unknown algorithm %q", *algorithm)
+	}
+	if err != nil {
+		log.Fatalf("unable to hash password: %v", err)
+	}
+
+	fmt.Println(hash)
+}
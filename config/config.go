@@ -1,11 +1,22 @@
 package config
 
+import "time"
+
 type Config struct {
 	Environment string `mapstructure:"environment"`
 	Port        string `mapstructure:"port"`
 
-	DB    DBConfig    `mapstructure:"db"`
-	Users []BasicUser `mapstructure:"users"`
+	DB          DBConfig          `mapstructure:"db"`
+	Users       []BasicUser       `mapstructure:"users"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Outbox      OutboxConfig      `mapstructure:"outbox"`
+	Webhooks    WebhookConfig     `mapstructure:"webhooks"`
+	Escalation  EscalationConfig  `mapstructure:"escalation"`
+	Incidents   IncidentConfig    `mapstructure:"incidents"`
+	Purge       PurgeConfig       `mapstructure:"purge"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+
+	InhibitRules []InhibitRuleConfig `mapstructure:"inhibit_rules"`
 }
 
 type DBConfig struct {
@@ -21,7 +32,98 @@ type DBConfig struct {
 	Url string
 }
 
+// BasicUser is an API user kept in the config. PasswordHash is a bcrypt or
+// argon2id hash, as printed by cmd/hash-password. Role is one of viewer,
+// responder and admin, and is required. Org is the slug of the
+// organization of the user, and defaults to the default organization.
 type BasicUser struct {
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	Username     string `mapstructure:"username"`
+	PasswordHash string `mapstructure:"password_hash"`
+	Role         string `mapstructure:"role"`
+	Org          string `mapstructure:"org"`
+}
+
+// AuthConfig lists where API users are looked up, tried in order: "config"
+// for Users and "postgres" for the api_user table. It defaults to config.
+// DisableBasic turns Basic credentials off altogether, as when JWTs replace
+// them. AnonymousRead lets anyone read alerts and incidents
+// without credentials; requests that do send credentials still need the read
+// scope.
+type AuthConfig struct {
+	Backends      []string  `mapstructure:"backends"`
+	DisableBasic  bool      `mapstructure:"disable_basic"`
+	AnonymousRead bool      `mapstructure:"anonymous_read"`
+	JWT           JWTConfig `mapstructure:"jwt"`
+}
+
+// JWTConfig turns on bearer JWTs issued by single sign-on. Tokens must be
+// signed with RS256 or ES256 by a key of the JWKS at JWKSURL or in JWKSFile.
+// The username is read from UsernameClaim, the roles from RolesClaim and the
+// slug of the organization from OrgClaim; each may name a nested claim as in
+// realm_access.roles. The viewer, responder and admin roles are known;
+// RoleScopes maps further roles to API scopes. Role names are not case
+// sensitive.
+type JWTConfig struct {
+	Enabled         bool                `mapstructure:"enabled"`
+	JWKSURL         string              `mapstructure:"jwks_url"`
+	JWKSFile        string              `mapstructure:"jwks_file"`
+	RefreshInterval time.Duration       `mapstructure:"refresh_interval"`
+	Issuer          string              `mapstructure:"issuer"`
+	Audience        string              `mapstructure:"audience"`
+	Leeway          time.Duration       `mapstructure:"leeway"`
+	UsernameClaim   string              `mapstructure:"username_claim"`
+	RolesClaim      string              `mapstructure:"roles_claim"`
+	OrgClaim        string              `mapstructure:"org_claim"`
+	RoleScopes      map[string][]string `mapstructure:"role_scopes"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	BatchSize    int32         `mapstructure:"batch_size"`
+}
+
+type WebhookConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	BatchSize      int32         `mapstructure:"batch_size"`
+	MaxAttempts    int32         `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+}
+
+type EscalationConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	BatchSize    int32         `mapstructure:"batch_size"`
+}
+
+// IncidentConfig controls how new alerts are grouped into incidents. Alerts
+// that share the values of the GroupBy labels join the same open incident as
+// long as it received an alert within GroupWindow. An empty GroupBy turns
+// grouping off.
+type IncidentConfig struct {
+	GroupBy     []string      `mapstructure:"group_by"`
+	GroupWindow time.Duration `mapstructure:"group_window"`
+}
+
+// PurgeConfig controls the permanent removal of deleted alerts once they have
+// been deleted for longer than Retention.
+type PurgeConfig struct {
+	Retention    time.Duration `mapstructure:"retention"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	BatchSize    int32         `mapstructure:"batch_size"`
+}
+
+// IdempotencyConfig controls how long the response to a request made with an
+// Idempotency-Key is kept for replay.
+type IdempotencyConfig struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// InhibitRuleConfig holds back alerts matching TargetMatchers while an
+// unresolved alert matching SourceMatchers has the same values for the Equal
+// labels. Matchers use the same syntax as the label filters of the API.
+type InhibitRuleConfig struct {
+	SourceMatchers []string `mapstructure:"source_matchers"`
+	TargetMatchers []string `mapstructure:"target_matchers"`
+	Equal          []string `mapstructure:"equal"`
 }
@@ -2,7 +2,9 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid/v5"
@@ -11,8 +13,11 @@ import (
 	"github.com/josephlbailey/alert-service/internal/api/models"
 	"github.com/josephlbailey/alert-service/internal/db"
 	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
 )
 
+var errIncludeDeletedForbidden = errors.New("only admins may include deleted alerts")
+
 func (s *Server) CreateAlert(c *gin.Context) {
 	var (
 		req models.CreateAlertReq
@@ -25,28 +30,100 @@ func (s *Server) CreateAlert(c *gin.Context) {
 		return
 	}
 
+	p.OrgID = org(c)
+
 	s.logger.Info("creating alert...")
-	alert, err := s.store.CreateAlertTX(c, p)
+	alert, err := s.store.CreateAlertTX(c, p, actor(c))
 	if err != nil {
 		s.logger.Error("error creating alert entity", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, NewError(err))
 		return
 	}
 
+	// a repeat of an unresolved alert is folded into the existing one
+	if alert.Occurrences > 1 {
+		s.logger.Info("deduplicated alert.", zap.String("externalId", alert.ExternalID.String()), zap.Int32("occurrences", alert.Occurrences))
+		setAlertETag(c, alert)
+		c.JSON(http.StatusOK, models.NewAlertResponse(alert))
+		return
+	}
+
 	s.logger.Info("created alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
 	c.JSON(http.StatusCreated, models.NewAlertResponse(alert))
 }
 
+func (s *Server) ListAlerts(c *gin.Context) {
+	var (
+		req models.ListAlertsReq
+		p   domain.ListAlertsParams
+		ms  labels.Matchers
+	)
+
+	err := req.Bind(c, &p, &ms)
+
+	if err != nil {
+		return
+	}
+
+	if p.IncludeDeleted && !s.admin(c) {
+		s.logger.Warn("deleted alerts requested by non-admin, returning 403")
+		c.JSON(http.StatusForbidden, NewError(errIncludeDeletedForbidden))
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("listing alerts...")
+	alerts, scanned, err := s.store.ListAlertsMatching(c, p, ms)
+	if err != nil {
+		s.logger.Error("error listing alerts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing alerts")))
+		return
+	}
+
+	resp := models.NewListAlertsResponse(alerts, scanned, p)
+	s.logger.Info("returning alerts.", zap.Int("count", len(resp.Alerts)))
+	c.JSON(http.StatusOK, resp)
+}
+
 func (s *Server) GetAlertByExternalID(c *gin.Context) {
-	var externalID uuid.UUID
+	var (
+		externalID uuid.UUID
+		req        models.GetAlertReq
+	)
 	err := externalID.Parse(c.Param("externalID"))
 	if err != nil {
 		s.logger.Warn("invalid identifier format, returning 400")
 		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
 		return
 	}
+
+	err = req.Bind(c)
+
+	if err != nil {
+		return
+	}
+
+	if req.IncludeDeleted && !s.admin(c) {
+		s.logger.Warn("deleted alerts requested by non-admin, returning 403")
+		c.JSON(http.StatusForbidden, NewError(errIncludeDeletedForbidden))
+		return
+	}
+
 	s.logger.Info("getting alert...", zap.String("externalId", externalID.String()))
-	alert, err := s.store.GetAlertByExternalID(c, externalID)
+	var alert *domain.Alert
+	if req.IncludeDeleted {
+		alert, err = s.store.GetAlertByExternalIDIncludeDeleted(c, domain.GetAlertByExternalIDIncludeDeletedParams{
+			OrgID:      org(c),
+			ExternalID: externalID,
+		})
+	} else {
+		alert, err = s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+			OrgID:      org(c),
+			ExternalID: externalID,
+		})
+	}
 	if err != nil {
 
 		if errors.Is(err, db.ErrAlertNotExists) {
@@ -61,6 +138,7 @@ func (s *Server) GetAlertByExternalID(c *gin.Context) {
 	}
 
 	s.logger.Info("returning alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
 	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
 }
 
@@ -84,7 +162,10 @@ func (s *Server) UpdateAlertByExternalID(c *gin.Context) {
 		return
 	}
 
-	alert, err := s.store.GetAlertByExternalID(c, externalID)
+	alert, err := s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
 	if err != nil {
 
 		if errors.Is(err, db.ErrAlertNotExists) {
@@ -98,22 +179,109 @@ func (s *Server) UpdateAlertByExternalID(c *gin.Context) {
 		return
 	}
 
+	version, ok := s.ifMatch(c, alert)
+	if !ok {
+		return
+	}
+
 	p.ID = alert.ID
+	p.Version = version
+
+	s.updateAlert(c, externalID, p)
+
+}
+
+// PatchAlertByExternalID applies a JSON Merge Patch or JSON Patch to an alert.
+// The patched alert is checked and saved as a full update would be.
+func (s *Server) PatchAlertByExternalID(c *gin.Context) {
+	var (
+		externalID uuid.UUID
+		req        models.UpdateAlertReq
+		p          domain.UpdateAlertByIDParams
+	)
 
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	alert, err := s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrAlertNotExists) {
+			s.logger.Warn("alert not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("alert not found")))
+			return
+		}
+
+		s.logger.Error("error getting alert entity to patch", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	version, ok := s.ifMatch(c, alert)
+	if !ok {
+		return
+	}
+
+	err = req.BindPatch(c, alert, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.ID = alert.ID
+	p.Version = version
+
+	s.updateAlert(c, externalID, p)
+}
+
+// updateAlert saves an update of the alert with ID p.ID and responds with the
+// result.
+func (s *Server) updateAlert(c *gin.Context, externalID uuid.UUID, p domain.UpdateAlertByIDParams) {
 	s.logger.Info("updating alert...", zap.String("externalID", externalID.String()))
-	alert, err = s.store.UpdateAlertByIDTX(c, p)
+	alert, err := s.store.UpdateAlertByIDTX(c, p, actor(c))
 
 	if err != nil {
+		if errors.Is(err, db.ErrAlertVersionStale) {
+			s.logger.Warn("stale alert update, returning 412", zap.Error(err))
+			c.JSON(http.StatusPreconditionFailed, NewError(errPreconditionFailed))
+			return
+		}
+
+		if errors.Is(err, db.ErrInvalidStatusTransition) || errors.Is(err, db.ErrDuplicateOpenAlert) {
+			s.logger.Warn("conflicting alert update, returning 409", zap.Error(err))
+			c.JSON(http.StatusConflict, NewError(statusUpdateError(externalID, err)))
+			return
+		}
+
 		s.logger.Error("error updating alert entity", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, NewError(err))
 		return
 	}
 
 	s.logger.Info("updated alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
 	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
 
 }
 
+// statusUpdateError points a client that tried to acknowledge or resolve an
+// alert with an update at the action endpoints instead.
+func statusUpdateError(externalID uuid.UUID, err error) error {
+	if !errors.Is(err, db.ErrStatusRequiresAction) {
+		return err
+	}
+	return fmt.Errorf("%w: use POST /alert/%s/ack or POST /alert/%s/resolve", err, externalID, externalID)
+}
+
+// DeleteAlertByExternalID soft deletes an alert. It can be brought back with
+// RestoreAlertByExternalID until the purge removes it.
 func (s *Server) DeleteAlertByExternalID(c *gin.Context) {
 	var externalID uuid.UUID
 
@@ -124,7 +292,10 @@ func (s *Server) DeleteAlertByExternalID(c *gin.Context) {
 		return
 	}
 
-	alert, err := s.store.GetAlertByExternalID(c, externalID)
+	alert, err := s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
 	if err != nil {
 
 		if errors.Is(err, db.ErrAlertNotExists) {
@@ -138,10 +309,26 @@ func (s *Server) DeleteAlertByExternalID(c *gin.Context) {
 		return
 	}
 
+	version, ok := s.ifMatch(c, alert)
+	if !ok {
+		return
+	}
+
 	s.logger.Info("deleting alert...", zap.String("externalID", externalID.String()))
-	err = s.store.DeleteAlertByIDTX(c, alert.ID)
+	alert, err = s.store.DeleteAlertByIDTX(c, domain.DeleteAlertByIDParams{
+		DeletedAt: time.Now(),
+		DeletedBy: actor(c),
+		ID:        alert.ID,
+		Version:   version,
+	})
 
 	if err != nil {
+		if errors.Is(err, db.ErrAlertVersionStale) {
+			s.logger.Warn("stale alert delete, returning 412", zap.Error(err))
+			c.JSON(http.StatusPreconditionFailed, NewError(errPreconditionFailed))
+			return
+		}
+
 		s.logger.Error("error deleting alert entity", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, NewError(err))
 		return
@@ -151,3 +338,223 @@ func (s *Server) DeleteAlertByExternalID(c *gin.Context) {
 	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
 
 }
+
+func (s *Server) RestoreAlertByExternalID(c *gin.Context) {
+	var externalID uuid.UUID
+
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	alert, err := s.store.GetAlertByExternalIDIncludeDeleted(c, domain.GetAlertByExternalIDIncludeDeletedParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrAlertNotExists) {
+			s.logger.Warn("alert not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("alert not found")))
+			return
+		}
+
+		s.logger.Error("error getting alert entity to restore", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("restoring alert...", zap.String("externalID", externalID.String()))
+	alert, err = s.store.RestoreAlertByIDTX(c, alert.ID, actor(c))
+
+	if err != nil {
+		if errors.Is(err, db.ErrAlertNotDeleted) || errors.Is(err, db.ErrDuplicateOpenAlert) {
+			s.logger.Warn("conflicting alert restore, returning 409", zap.Error(err))
+			c.JSON(http.StatusConflict, NewError(err))
+			return
+		}
+
+		s.logger.Error("error restoring alert entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("restored alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
+	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
+}
+
+// GetAlertHistoryByExternalID returns the changes made to an alert, oldest
+// first. The history of a deleted alert remains available.
+func (s *Server) GetAlertHistoryByExternalID(c *gin.Context) {
+	var externalID uuid.UUID
+
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	s.logger.Info("listing alert history...", zap.String("externalID", externalID.String()))
+	entries, err := s.store.ListAlertHistoryByExternalID(c, domain.ListAlertHistoryByExternalIDParams{
+		OrgID:           org(c),
+		AlertExternalID: externalID,
+	})
+	if err != nil {
+		s.logger.Error("error listing alert history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing alert history")))
+		return
+	}
+
+	// alerts raised before history was kept have none
+	if len(entries) == 0 {
+		_, err = s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+			OrgID:      org(c),
+			ExternalID: externalID,
+		})
+		if err != nil {
+
+			if errors.Is(err, db.ErrAlertNotExists) {
+				s.logger.Warn("alert not found, returning 404")
+				c.JSON(http.StatusNotFound, NewError(errors.New("alert not found")))
+				return
+			}
+
+			s.logger.Error("error getting alert entity", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, NewError(err))
+			return
+		}
+	}
+
+	resp, err := models.NewAlertHistoryListResponse(entries)
+	if err != nil {
+		s.logger.Error("error decoding alert history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing alert history")))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) AcknowledgeAlertByExternalID(c *gin.Context) {
+	var (
+		externalID uuid.UUID
+		req        models.AcknowledgeAlertReq
+		p          domain.AcknowledgeAlertByIDParams
+	)
+
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	err = req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	alert, err := s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrAlertNotExists) {
+			s.logger.Warn("alert not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("alert not found")))
+			return
+		}
+
+		s.logger.Error("error getting alert entity to acknowledge", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	p.ID = alert.ID
+	p.AcknowledgedBy = actor(c)
+
+	s.logger.Info("acknowledging alert...", zap.String("externalID", externalID.String()), zap.String("actor", p.AcknowledgedBy))
+	alert, err = s.store.AcknowledgeAlertByIDTX(c, p)
+
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			s.logger.Warn("illegal status transition, returning 409", zap.Error(err))
+			c.JSON(http.StatusConflict, NewError(err))
+			return
+		}
+
+		s.logger.Error("error acknowledging alert entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("acknowledged alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
+	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
+}
+
+func (s *Server) ResolveAlertByExternalID(c *gin.Context) {
+	var (
+		externalID uuid.UUID
+		req        models.ResolveAlertReq
+		p          domain.ResolveAlertByIDParams
+	)
+
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	err = req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	alert, err := s.store.GetAlertByExternalID(c, domain.GetAlertByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrAlertNotExists) {
+			s.logger.Warn("alert not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("alert not found")))
+			return
+		}
+
+		s.logger.Error("error getting alert entity to resolve", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	p.ID = alert.ID
+	p.ResolvedBy = actor(c)
+
+	s.logger.Info("resolving alert...", zap.String("externalID", externalID.String()), zap.String("actor", p.ResolvedBy))
+	alert, err = s.store.ResolveAlertByIDTX(c, p)
+
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			s.logger.Warn("illegal status transition, returning 409", zap.Error(err))
+			c.JSON(http.StatusConflict, NewError(err))
+			return
+		}
+
+		s.logger.Error("error resolving alert entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("resolved alert.", zap.String("externalId", alert.ExternalID.String()))
+	setAlertETag(c, alert)
+	c.JSON(http.StatusOK, models.NewAlertResponse(alert))
+}
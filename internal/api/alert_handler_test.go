@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
@@ -20,11 +24,15 @@ import (
 	"github.com/josephlbailey/alert-service/internal/db"
 	"github.com/josephlbailey/alert-service/internal/db/domain"
 	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
 )
 
 type testCase struct {
 	name          string
 	externalID    string
+	query         string
+	anonymous     bool
+	ifMatch       string
 	body          gin.H
 	buildStubs    func(store *mockdb.MockStore)
 	checkResponse func(recorder *httptest.ResponseRecorder)
@@ -43,6 +51,7 @@ func TestCreateAlert(t *testing.T) {
 				store.EXPECT().
 					CreateAlertTX(gomock.Any(), gomock.All(
 						gomock.Cond(func(x any) bool { return x.(domain.CreateAlertParams).Message == message })),
+						gomock.Eq("integrationUser"),
 					).
 					Times(1).
 					Return(alert, nil)
@@ -50,9 +59,155 @@ func TestCreateAlert(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, `"1"`, recorder.Header().Get("ETag"))
 				requireBodyMatchAlert(t, alert, recorder.Body)
 			},
 		},
+		{
+			name: "create alert computes fingerprint from source and message",
+			body: gin.H{
+				"message": message,
+				"source":  "disk-monitor",
+				"labels":  gin.H{"host": "db-1"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateAlertParams)
+						return p.Source == "disk-monitor" && p.Fingerprint == db.Fingerprint("disk-monitor", map[string]string{"host": "db-1"}, message)
+					}), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create duplicate alert returns existing alert",
+			body: gin.H{
+				"message":     message,
+				"fingerprint": "client-fingerprint",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				duplicate := *alert
+				duplicate.Occurrences = 2
+
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.CreateAlertParams).Fingerprint == "client-fingerprint" }), gomock.Any()).
+					Times(1).
+					Return(&duplicate, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, int32(2), got.Occurrences)
+			},
+		},
+		{
+			name: "create alert with labels and annotations",
+			body: gin.H{
+				"message":     message,
+				"labels":      gin.H{"env": "prod"},
+				"annotations": gin.H{"runbook": "https://runbooks.example.com/hello"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateAlertParams)
+						return string(p.Labels) == `{"env":"prod"}` && string(p.Annotations) == `{"runbook":"https://runbooks.example.com/hello"}`
+					}), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchAlert(t, alert, recorder.Body)
+			},
+		},
+		{
+			name: "create alert without labels stores empty objects",
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateAlertParams)
+						return string(p.Labels) == "{}" && string(p.Annotations) == "{}"
+					}), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create alert with invalid label name",
+			body: gin.H{
+				"message": message,
+				"labels":  gin.H{"not-a-label": "x"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid label name")
+			},
+		},
+		{
+			name: "create alert defaults severity to warning",
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.CreateAlertParams).Severity == db.SeverityWarning }), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create alert with explicit severity",
+			body: gin.H{
+				"message":  message,
+				"severity": db.SeverityCritical,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.CreateAlertParams).Severity == db.SeverityCritical }), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create alert with invalid severity",
+			body: gin.H{
+				"message":  message,
+				"severity": "apocalyptic",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "severity")
+			},
+		},
 		{
 			name: "create alert with invalid body",
 			body: gin.H{
@@ -60,14 +215,1154 @@ func TestCreateAlert(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAlertTX(gomock.Any(), gomock.Any()).
-					Times(0)
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			url := "/alert"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			// Add basic auth
+			auth := "integrationUser:integrationUserPassword"
+			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+
+		})
+	}
+
+}
+
+func TestGetAlertByExternalID(t *testing.T) {
+	alert, _ := randomAlert()
+
+	testCases := []testCase{
+		{
+			name:       "get existing alert by valid external ID",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+				requireBodyMatchAlert(t, alert, recorder.Body)
+			},
+		},
+		{
+			name:       "get silenced alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				silenced := *alert
+				silenced.SilenceID = pgtype.Int4{Int32: 1, Valid: true}
+				silenced.SilencedUntil = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&silenced, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.True(t, got.Silenced)
+				require.NotNil(t, got.SilencedUntil)
+			},
+		},
+		{
+			name:       "get inhibited alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				source := uuid.Must(uuid.NewV4())
+				inhibited := *alert
+				inhibited.InhibitedBy = pgtype.UUID{Bytes: source, Valid: true}
+
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&inhibited, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.NotNil(t, got.InhibitedBy)
+				require.NotEqual(t, uuid.Nil, *got.InhibitedBy)
+			},
+		},
+		{
+			name:       "get alert whose silence has expired",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := *alert
+				expired.SilenceID = pgtype.Int4{Int32: 1, Valid: true}
+				expired.SilencedUntil = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&expired, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.False(t, got.Silenced)
+				require.Nil(t, got.SilencedUntil)
+			},
+		},
+		{
+			name:       "get non-existing alert by valid external ID",
+			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479"))})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "alert not found")
+			},
+		},
+		{
+			name:       "get alert with invalid external ID format",
+			externalID: "invalidUUID",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/alert/%s", testCase.externalID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			// Add basic auth
+			auth := "integrationUser:integrationUserPassword"
+			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListAlerts(t *testing.T) {
+	alerts := randomAlerts(3)
+
+	testCases := []testCase{
+		{
+			name: "list alerts with default parameters",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListAlertsParams)
+						return p.PageSize == models.DefaultListLimit+1 && p.SortBy == "created_at" && !p.SortDesc && !p.CursorID.Valid
+					}), gomock.Any()).
+					Times(1).
+					Return(alerts, nil, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.ListAlertsRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Alerts, len(alerts))
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name:  "list alerts returns a cursor when more alerts exist",
+			query: "limit=2&sort=updatedAt&order=desc&message=50%25_off",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListAlertsParams)
+						return p.PageSize == 3 && p.SortBy == "updated_at" && p.SortDesc && p.Message.String == `50\%\_off`
+					}), gomock.Any()).
+					Times(1).
+					Return(alerts, nil, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.ListAlertsRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Alerts, 2)
+				require.NotEmpty(t, got.NextCursor)
+			},
+		},
+		{
+			name:  "list alerts by label matchers",
+			query: "label=env%3Dprod&label=service%3D~api-.*&label=team%3D",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListAlertsParams)
+						return string(p.LabelsContain) == `{"env":"prod"}` &&
+							reflect.DeepEqual(p.MatcherNames, []string{"team"}) &&
+							reflect.DeepEqual(p.MatcherTypes, []string{"="}) &&
+							reflect.DeepEqual(p.MatcherValues, []string{""})
+					}), gomock.Cond(func(x any) bool {
+						// regular expressions are left to the store
+						ms := x.(labels.Matchers)
+						return len(ms) == 1 && ms[0].String() == `service=~"api-.*"`
+					})).
+					Times(1).
+					Return(alerts, nil, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "list alerts returns a cursor when the scan stops short of a page",
+			query: "label=service%3D~api-.*",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(alerts[:1], alerts[2], nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.ListAlertsRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Alerts, 1)
+				require.NotEmpty(t, got.NextCursor)
+			},
+		},
+		{
+			name:  "list alerts with invalid label matcher",
+			query: "label=service%3D~%28",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid matcher")
+			},
+		},
+		{
+			name:  "list alerts with invalid sort",
+			query: "sort=message",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "list alerts with invalid cursor",
+			query: "cursor=not-a-cursor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid cursor")
+			},
+		},
+		{
+			name: "list alerts with store error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/alert?%s", testCase.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateAlertByExternalID(t *testing.T) {
+	alert, message := randomAlert()
+	param := randomUpdateAlertParams()
+	exID := uuid.Must(uuid.NewV4())
+	alert.ExternalID = exID
+	upd := time.Now()
+	param.UpdatedAt = upd
+
+	updated := *alert
+	updated.Version = 2
+
+	testCases := []testCase{
+		{
+			name:       "update existing alert by valid external ID",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.UpdateAlertByIDParams)
+						return p.ID == alert.ID && p.Version == pgtype.Int4{Int32: 1, Valid: true}
+					}), gomock.Any()).
+					Times(1).
+					Return(&updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+				requireBodyMatchAlert(t, &updated, recorder.Body)
+			},
+		},
+		{
+			name:       "update alert with wildcard If-Match",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    "*",
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool { return !x.(domain.UpdateAlertByIDParams).Version.Valid }), gomock.Any()).
+					Times(1).
+					Return(&updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "update alert without If-Match",
+			externalID: alert.ExternalID.String(),
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:       "update alert with stale If-Match",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"0", W/"1"`,
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:       "update alert changed concurrently",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAlertVersionStale)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:       "update alert with illegal status transition",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+				"status":  db.StatusAcknowledged,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.UpdateAlertByIDParams).Status.String == db.StatusAcknowledged }), gomock.Any()).
+					Times(1).
+					Return(nil, db.CheckStatusTransition(db.StatusResolved, db.StatusAcknowledged))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid status transition from resolved to acknowledged")
+			},
+		},
+		{
+			name:       "resolve alert with an update",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+				"status":  db.StatusResolved,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrStatusRequiresAction)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "POST /alert/"+alert.ExternalID.String()+"/resolve")
+			},
+		},
+		{
+			name:       "reopen alert while a duplicate is unresolved",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+				"status":  db.StatusOpen,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrDuplicateOpenAlert)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:       "update alert with unknown status",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+				"status":  "snoozed",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "update non-existing alert by valid external ID",
+			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479"))})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "alert not found")
+			},
+		},
+		{
+			name:       "update alert with invalid external ID format",
+			externalID: "invalidUUID",
+			ifMatch:    `"1"`,
+			body: gin.H{
+				"message": message,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/alert/%s", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
+
+			// Add basic auth
+			auth := "integrationUser:integrationUserPassword"
+			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestPatchAlertByExternalID(t *testing.T) {
+	alert, message := randomAlert()
+	alert.ExternalID = uuid.Must(uuid.NewV4())
+
+	updated := *alert
+	updated.Version = 2
+
+	testCases := []struct {
+		name          string
+		contentType   string
+		ifMatch       string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "merge patch",
+			contentType: models.MergePatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"severity":"critical","labels":{"env":null,"team":"db"}}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.UpdateAlertByIDParams)
+						return p.ID == alert.ID && p.Version.Int32 == 1 &&
+							p.Message == message && p.Severity.String == db.SeverityCritical &&
+							p.Status.String == db.StatusOpen && string(p.Labels) == `{"team":"db"}`
+					}), gomock.Eq("integrationUser")).
+					Times(1).
+					Return(&updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+				requireBodyMatchAlert(t, &updated, recorder.Body)
+			},
+		},
+		{
+			name:        "json patch",
+			contentType: models.JSONPatchContentType,
+			ifMatch:     `"1"`,
+			body:        `[{"op":"test","path":"/status","value":"open"},{"op":"replace","path":"/status","value":"acknowledged"},{"op":"add","path":"/annotations/summary","value":"disk full"}]`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.UpdateAlertByIDParams)
+						var annotations map[string]string
+						_ = json.Unmarshal(p.Annotations, &annotations)
+						return p.Message == message && p.Status.String == db.StatusAcknowledged &&
+							annotations["summary"] == "disk full" && len(annotations) == 2
+					}), gomock.Any()).
+					Times(1).
+					Return(&updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "patched alert fails validation",
+			contentType: models.MergePatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"message":null,"severity":"urgent"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"field":"message"`)
+				require.Contains(t, recorder.Body.String(), `"field":"severity"`)
+			},
+		},
+		{
+			name:        "patch of a field that cannot be updated",
+			contentType: models.MergePatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"occurrences":3}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "json patch test fails",
+			contentType: models.JSONPatchContentType,
+			ifMatch:     `"1"`,
+			body:        `[{"op":"test","path":"/status","value":"resolved"}]`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "malformed json patch",
+			contentType: models.JSONPatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"op":"remove","path":"/labels"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "application/json",
+			ifMatch:     `"1"`,
+			body:        `{"severity":"critical"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Accept-Patch"), models.MergePatchContentType)
+			},
+		},
+		{
+			name:        "patch without If-Match",
+			contentType: models.MergePatchContentType,
+			body:        `{"severity":"critical"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:        "patch of a stale alert",
+			contentType: models.MergePatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"severity":"critical"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAlertVersionStale)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:        "patch of a missing alert",
+			contentType: models.MergePatchContentType,
+			ifMatch:     `"1"`,
+			body:        `{"severity":"critical"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+
+				store.EXPECT().
+					UpdateAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/alert/%s", alert.ExternalID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+
+			request.Header.Set("Content-Type", testCase.contentType)
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteAlertByExternalID(t *testing.T) {
+	alert, _ := randomAlert()
+
+	testCases := []testCase{
+		{
+			name:       "delete existing alert by valid external ID",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				deleted := *alert
+				deleted.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				deleted.DeletedBy = pgtype.Text{String: "adminServiceUser", Valid: true}
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.DeleteAlertByIDParams)
+						return p.ID == alert.ID && p.DeletedBy == "adminServiceUser" && p.Version == pgtype.Int4{Int32: 1, Valid: true}
+					})).
+					Times(1).
+					Return(&deleted, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.NotNil(t, got.Deletion)
+				require.Equal(t, "adminServiceUser", got.Deletion.By)
+			},
+		},
+		{
+			name:       "delete alert without If-Match",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:       "delete alert with stale If-Match",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"7"`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:       "delete alert changed concurrently",
+			externalID: alert.ExternalID.String(),
+			ifMatch:    `"1"`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAlertVersionStale)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:       "delete non-existing alert by valid external ID",
+			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
+			ifMatch:    `"1"`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479"))})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "alert not found")
+			},
+		},
+		{
+			name:       "delete alert with invalid external ID format",
+			externalID: "invalidUUID",
+			ifMatch:    `"1"`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/alert/%s", testCase.externalID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
+
+			addAdminAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRestoreAlertByExternalID(t *testing.T) {
+	alert, _ := randomAlert()
+
+	deleted := *alert
+	deleted.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	deleted.DeletedBy = pgtype.Text{String: "adminServiceUser", Valid: true}
+
+	testCases := []testCase{
+		{
+			name:       "restore deleted alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDIncludeDeletedParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&deleted, nil)
+
+				store.EXPECT().
+					RestoreAlertByIDTX(gomock.Any(), gomock.Eq(alert.ID), gomock.Eq("adminServiceUser")).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAlert(t, alert, recorder.Body)
+			},
+		},
+		{
+			name:       "restore alert that is not deleted",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					RestoreAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAlertNotDeleted)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), db.ErrAlertNotDeleted.Error())
+			},
+		},
+		{
+			name:       "restore alert whose fingerprint is open again",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&deleted, nil)
+
+				store.EXPECT().
+					RestoreAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrDuplicateOpenAlert)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:       "restore non-existing alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
 
+				store.EXPECT().
+					RestoreAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "restore alert with invalid external ID format",
+			externalID: "invalidUUID",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name:       "restore alert unauthorized",
+			externalID: alert.ExternalID.String(),
+			anonymous:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RestoreAlertByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -81,69 +1376,244 @@ func TestCreateAlert(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			data, err := json.Marshal(testCase.body)
+			url := fmt.Sprintf("/alert/%s/restore", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
 			require.NoError(t, err)
 
-			url := "/alert"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			if !testCase.anonymous {
+				addAdminAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestIncludeDeletedAlerts(t *testing.T) {
+	alert, _ := randomAlert()
+	alert.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	alert.DeletedBy = pgtype.Text{String: "integrationUser", Valid: true}
+
+	testCases := []struct {
+		name          string
+		url           string
+		auth          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "admin lists deleted alerts",
+			url:  "/alert?include_deleted=true",
+			auth: "adminServiceUser:adminServicePassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, p domain.ListAlertsParams, _ labels.Matchers) ([]*domain.Alert, *domain.Alert, error) {
+						require.True(t, p.IncludeDeleted)
+						return []*domain.Alert{alert}, nil, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.ListAlertsRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Alerts, 1)
+				require.NotNil(t, got.Alerts[0].Deletion)
+			},
+		},
+		{
+			name: "non-admin lists deleted alerts",
+			url:  "/alert?include_deleted=true",
+			auth: "integrationUser:integrationUserPassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "admin with wrong password lists deleted alerts",
+			url:  "/alert?include_deleted=true",
+			auth: "adminServiceUser:wrong",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "anonymous lists deleted alerts",
+			url:  "/alert?include_deleted=true",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAlertsMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "admin gets deleted alert",
+			url:  fmt.Sprintf("/alert/%s?include_deleted=true", alert.ExternalID),
+			auth: "adminServiceUser:adminServicePassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDIncludeDeletedParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(alert, nil)
+				store.EXPECT().GetAlertByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.NotNil(t, got.Deletion)
+				require.Equal(t, "integrationUser", got.Deletion.By)
+			},
+		},
+		{
+			name: "non-admin gets deleted alert",
+			url:  fmt.Sprintf("/alert/%s?include_deleted=true", alert.ExternalID),
+			auth: "integrationUser:integrationUserPassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlertByExternalIDIncludeDeleted(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, testCase.url, nil)
 			require.NoError(t, err)
 
-			// Add basic auth
-			auth := "integrationUser:integrationUserPassword"
-			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			if testCase.auth != "" {
+				encodedAuth := base64.StdEncoding.EncodeToString([]byte(testCase.auth))
+				request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(recorder)
-
 		})
 	}
-
 }
 
-func TestGetAlertByExternalID(t *testing.T) {
+func TestGetAlertHistoryByExternalID(t *testing.T) {
 	alert, _ := randomAlert()
 
+	updated := *alert
+	updated.Message = "updated message"
+
+	before, err := json.Marshal(alert)
+	require.NoError(t, err)
+	after, err := json.Marshal(&updated)
+	require.NoError(t, err)
+
+	history := []*domain.AlertHistory{
+		{
+			ID:              1,
+			AlertExternalID: alert.ExternalID,
+			CreatedAt:       alert.CreatedAt,
+			Actor:           "integrationUser",
+			Action:          db.ActionCreate,
+			After:           before,
+		},
+		{
+			ID:              2,
+			AlertExternalID: alert.ExternalID,
+			CreatedAt:       time.Now(),
+			Actor:           "adminServiceUser",
+			Action:          db.ActionUpdate,
+			Before:          before,
+			After:           after,
+		},
+	}
+
 	testCases := []testCase{
 		{
-			name:       "get existing alert by valid external ID",
+			name:       "get history of alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertHistoryByExternalID(gomock.Any(), gomock.Eq(domain.ListAlertHistoryByExternalIDParams{OrgID: db.DefaultOrgID, AlertExternalID: alert.ExternalID})).
+					Times(1).
+					Return(history, nil)
+				store.EXPECT().GetAlertByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []*models.AlertHistoryRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got, 2)
+				require.Equal(t, db.ActionCreate, got[0].Action)
+				require.Nil(t, got[0].Before)
+				require.Equal(t, alert.Message, got[0].After.Message)
+				require.Equal(t, "adminServiceUser", got[1].Actor)
+				require.Equal(t, alert.Message, got[1].Before.Message)
+				require.Equal(t, "updated message", got[1].After.Message)
+			},
+		},
+		{
+			name:       "get history of alert without any",
 			externalID: alert.ExternalID.String(),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), alert.ExternalID).
+					ListAlertHistoryByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.AlertHistory{}, nil)
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
 					Times(1).
 					Return(alert, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchAlert(t, alert, recorder.Body)
+				require.JSONEq(t, "[]", recorder.Body.String())
 			},
 		},
 		{
-			name:       "get non-existing alert by valid external ID",
-			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
+			name:       "get history of unknown alert",
+			externalID: alert.ExternalID.String(),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), gomock.Eq(uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479")))).
+					ListAlertHistoryByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.AlertHistory{}, nil)
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil, db.ErrAlertNotExists)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "alert not found")
 			},
 		},
 		{
-			name:       "get alert with invalid external ID format",
-			externalID: "invalidUUID",
+			name:       "get history unauthorized",
+			externalID: alert.ExternalID.String(),
+			anonymous:  true,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), gomock.Any()).
-					Times(0)
+				store.EXPECT().ListAlertHistoryByExternalID(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
 	}
@@ -159,14 +1629,13 @@ func TestGetAlertByExternalID(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/alert/%s", testCase.externalID)
+			url := fmt.Sprintf("/alert/%s/history", testCase.externalID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
-			// Add basic auth
-			auth := "integrationUser:integrationUserPassword"
-			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			if !testCase.anonymous {
+				addBasicAuth(request)
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(recorder)
@@ -174,76 +1643,101 @@ func TestGetAlertByExternalID(t *testing.T) {
 	}
 }
 
-func TestUpdateAlertByExternalID(t *testing.T) {
-	alert, message := randomAlert()
-	param := randomUpdateAlertParams()
-	exID := uuid.Must(uuid.NewV4())
-	alert.ExternalID = exID
-	upd := time.Now()
-	param.UpdatedAt = upd
+func TestAcknowledgeAlertByExternalID(t *testing.T) {
+	alert, _ := randomAlert()
+	alert.ExternalID = uuid.Must(uuid.NewV4())
+
+	acknowledged := *alert
+	acknowledged.Status = db.StatusAcknowledged
+	acknowledged.AcknowledgedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	acknowledged.AcknowledgedBy = pgtype.Text{String: "integrationUser", Valid: true}
+	acknowledged.AcknowledgedNote = pgtype.Text{String: "looking into it", Valid: true}
 
 	testCases := []testCase{
 		{
-			name:       "update existing alert by valid external ID",
+			name:       "acknowledge open alert with note",
 			externalID: alert.ExternalID.String(),
 			body: gin.H{
-				"message": message,
+				"note": "looking into it",
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), alert.ExternalID).
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
 					Times(1).
 					Return(alert, nil)
 
 				store.EXPECT().
-					UpdateAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.UpdateAlertByIDParams).ID == alert.ID })).
+					AcknowledgeAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.AcknowledgeAlertByIDParams)
+						return p.ID == alert.ID && p.AcknowledgedBy == "integrationUser" && p.AcknowledgedNote.String == "looking into it"
+					})).
 					Times(1).
-					Return(alert, nil)
+					Return(&acknowledged, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchAlert(t, alert, recorder.Body)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.StatusAcknowledged, got.Status)
+				require.NotNil(t, got.Acknowledgement)
+				require.Equal(t, "integrationUser", got.Acknowledgement.By)
+				require.Equal(t, "looking into it", got.Acknowledgement.Note)
+				require.Nil(t, got.Resolution)
 			},
 		},
 		{
-			name:       "update non-existing alert by valid external ID",
-			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
-			body: gin.H{
-				"message": message,
-			},
+			name:       "acknowledge alert without body",
+			externalID: alert.ExternalID.String(),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), gomock.Eq(uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479")))).
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
 					Times(1).
-					Return(nil, db.ErrAlertNotExists)
+					Return(alert, nil)
 
 				store.EXPECT().
-					UpdateAlertByIDTX(gomock.Any(), gomock.Any()).
-					Times(0)
+					AcknowledgeAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool { return !x.(domain.AcknowledgeAlertByIDParams).AcknowledgedNote.Valid })).
+					Times(1).
+					Return(&acknowledged, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "alert not found")
+				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
 		{
-			name:       "update alert with invalid external ID format",
-			externalID: "invalidUUID",
-			body: gin.H{
-				"message": message,
+			name:       "acknowledge already acknowledged alert",
+			externalID: alert.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&acknowledged, nil)
+
+				store.EXPECT().
+					AcknowledgeAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: alert is already acknowledged", db.ErrInvalidStatusTransition))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "already acknowledged")
 			},
+		},
+		{
+			name:       "acknowledge non-existing alert",
+			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAlertByExternalID(gomock.Any(), gomock.Any()).
-					Times(0)
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
 
 				store.EXPECT().
-					UpdateAlertByIDTX(gomock.Any(), gomock.Any()).
+					AcknowledgeAlertByIDTX(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
 	}
@@ -259,11 +1753,15 @@ func TestUpdateAlertByExternalID(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			data, err := json.Marshal(testCase.body)
-			require.NoError(t, err)
+			var body io.Reader = http.NoBody
+			if testCase.body != nil {
+				data, err := json.Marshal(testCase.body)
+				require.NoError(t, err)
+				body = bytes.NewBuffer(data)
+			}
 
-			url := fmt.Sprintf("/alert/%s", testCase.externalID)
-			request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(data))
+			url := fmt.Sprintf("/alert/%s/ack", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPost, url, body)
 			require.NoError(t, err)
 
 			// Add basic auth
@@ -277,61 +1775,90 @@ func TestUpdateAlertByExternalID(t *testing.T) {
 	}
 }
 
-func TestDeleteAlertByExternalID(t *testing.T) {
+func TestResolveAlertByExternalID(t *testing.T) {
 	alert, _ := randomAlert()
+	alert.ExternalID = uuid.Must(uuid.NewV4())
+
+	resolved := *alert
+	resolved.Status = db.StatusResolved
+	resolved.ResolvedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	resolved.ResolvedBy = pgtype.Text{String: "integrationUser", Valid: true}
 
 	testCases := []testCase{
 		{
-			name:       "delete existing alert by valid external ID",
+			name:       "resolve open alert",
 			externalID: alert.ExternalID.String(),
+			body: gin.H{
+				"note": "fixed upstream",
+			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), alert.ExternalID).
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
 					Times(1).
 					Return(alert, nil)
 
 				store.EXPECT().
-					DeleteAlertByIDTX(gomock.Any(), alert.ID).
+					ResolveAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ResolveAlertByIDParams)
+						return p.ID == alert.ID && p.ResolvedBy == "integrationUser" && p.ResolvedNote.String == "fixed upstream"
+					})).
 					Times(1).
-					Return(nil)
+					Return(&resolved, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.AlertRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.StatusResolved, got.Status)
+				require.NotNil(t, got.Resolution)
+				require.Equal(t, "integrationUser", got.Resolution.By)
 			},
 		},
 		{
-			name:       "delete non-existing alert by valid external ID",
-			externalID: "f47ac10b-58cc-0372-8567-0e02b2c3d479",
+			name:       "resolve with note that is too long",
+			externalID: alert.ExternalID.String(),
+			body: gin.H{
+				"note": strings.Repeat("x", 1025),
+			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), gomock.Eq(uuid.Must(uuid.FromString("f47ac10b-58cc-0372-8567-0e02b2c3d479")))).
-					Times(1).
-					Return(nil, db.ErrAlertNotExists)
-
-				store.EXPECT().
-					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					ResolveAlertByIDTX(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "alert not found")
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name:       "delete alert with invalid external ID format",
-			externalID: "invalidUUID",
+			name:       "resolve already resolved alert",
+			externalID: alert.ExternalID.String(),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAlertByExternalID(gomock.Any(), gomock.Any()).
-					Times(0)
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: alert.ExternalID})).
+					Times(1).
+					Return(&resolved, nil)
 
 				store.EXPECT().
-					DeleteAlertByIDTX(gomock.Any(), gomock.Any()).
+					ResolveAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: alert is already resolved", db.ErrInvalidStatusTransition))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:       "resolve without credentials",
+			externalID: alert.ExternalID.String(),
+			anonymous:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResolveAlertByIDTX(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-				require.Contains(t, recorder.Body.String(), "invalid identifier format")
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
 	}
@@ -347,14 +1874,23 @@ func TestDeleteAlertByExternalID(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/alert/%s", testCase.externalID)
-			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			var body io.Reader = http.NoBody
+			if testCase.body != nil {
+				data, err := json.Marshal(testCase.body)
+				require.NoError(t, err)
+				body = bytes.NewBuffer(data)
+			}
+
+			url := fmt.Sprintf("/alert/%s/resolve", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPost, url, body)
 			require.NoError(t, err)
 
-			// Add basic auth
-			auth := "integrationUser:integrationUserPassword"
-			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-			request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			if !testCase.anonymous {
+				// Add basic auth
+				auth := "integrationUser:integrationUserPassword"
+				encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+				request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(recorder)
@@ -367,9 +1903,27 @@ func randomAlert() (alert *domain.Alert, message string) {
 	message = "Hello there"
 
 	alert = &domain.Alert{
-		ID:        1,
-		CreatedAt: time.Now(),
-		Message:   message,
+		ID:          1,
+		CreatedAt:   time.Now(),
+		Message:     message,
+		Severity:    db.SeverityWarning,
+		Status:      db.StatusOpen,
+		Fingerprint: db.Fingerprint("", nil, message),
+		Labels:      []byte(`{"env":"prod"}`),
+		Annotations: []byte(`{"runbook":"https://runbooks.example.com/hello"}`),
+		Occurrences: 1,
+		Version:     1,
+		OrgID:       db.DefaultOrgID,
+	}
+	return
+}
+
+func randomAlerts(n int) (alerts []*domain.Alert) {
+	for i := 0; i < n; i++ {
+		alert, _ := randomAlert()
+		alert.ID = int32(i + 1)
+		alert.ExternalID = uuid.Must(uuid.NewV4())
+		alerts = append(alerts, alert)
 	}
 	return
 }
@@ -398,4 +1952,16 @@ func requireBodyMatchAlert(t *testing.T, alert *domain.Alert, body *bytes.Buffer
 	require.NotNilf(t, gotAlert.CreatedAt, "expected alert to contain CreatedAt")
 	require.NotNilf(t, gotAlert.UpdatedAt, "expected alert to contain UpdatedAt")
 	require.Equalf(t, alert.Message, gotAlert.Message, "want Message: %v, got Message: %v", alert.Message, gotAlert.Message)
+	require.Equalf(t, alert.Severity, gotAlert.Severity, "want Severity: %v, got Severity: %v", alert.Severity, gotAlert.Severity)
+	require.Equalf(t, decodeLabels(t, alert.Labels), gotAlert.Labels, "want Labels: %s, got Labels: %v", alert.Labels, gotAlert.Labels)
+	require.Equalf(t, decodeLabels(t, alert.Annotations), gotAlert.Annotations, "want Annotations: %s, got Annotations: %v", alert.Annotations, gotAlert.Annotations)
+	require.Equalf(t, alert.Status, gotAlert.Status, "want Status: %v, got Status: %v", alert.Status, gotAlert.Status)
+}
+
+func decodeLabels(t *testing.T, data []byte) map[string]string {
+	m := make(map[string]string)
+	if len(data) > 0 {
+		require.NoError(t, json.Unmarshal(data, &m))
+	}
+	return m
 }
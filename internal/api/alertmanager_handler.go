@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// ReceiveAlertmanagerWebhook accepts notifications from an Alertmanager
+// webhook_config. Firing alerts are created or deduplicated by fingerprint,
+// resolved alerts resolve the matching unresolved alert. Any failure returns
+// a 500 so that Alertmanager retries the whole notification.
+func (s *Server) ReceiveAlertmanagerWebhook(c *gin.Context) {
+	var (
+		req  models.AlertmanagerWebhookReq
+		resp models.AlertmanagerWebhookRes
+	)
+
+	err := req.Bind(c)
+
+	if err != nil {
+		return
+	}
+
+	s.logger.Info("receiving alertmanager notification...",
+		zap.String("groupKey", req.GroupKey),
+		zap.String("status", req.Status),
+		zap.Int("alerts", len(req.Alerts)),
+	)
+
+	for _, amAlert := range req.Alerts {
+		if amAlert.Status == models.AlertmanagerResolved {
+			resolved, err := s.resolveAlertmanagerAlert(c, amAlert)
+			if err != nil {
+				s.logger.Error("error resolving alertmanager alert", zap.String("fingerprint", amAlert.AlertFingerprint()), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while resolving alert")))
+				return
+			}
+			if resolved {
+				resp.Resolved++
+			} else {
+				resp.Ignored++
+			}
+			continue
+		}
+
+		var p domain.CreateAlertParams
+		amAlert.CreateParams(&p)
+		p.OrgID = org(c)
+
+		alert, err := s.store.CreateAlertTX(c, p, actor(c))
+		if err != nil {
+			s.logger.Error("error creating alertmanager alert", zap.String("fingerprint", p.Fingerprint), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while creating alert")))
+			return
+		}
+		if alert.Occurrences > 1 {
+			resp.Deduplicated++
+		} else {
+			resp.Created++
+		}
+	}
+
+	s.logger.Info("received alertmanager notification.",
+		zap.String("groupKey", req.GroupKey),
+		zap.Int("created", resp.Created),
+		zap.Int("deduplicated", resp.Deduplicated),
+		zap.Int("resolved", resp.Resolved),
+		zap.Int("ignored", resp.Ignored),
+	)
+	c.JSON(http.StatusOK, resp)
+}
+
+// resolveAlertmanagerAlert resolves the unresolved alert with the same
+// fingerprint. It reports false if there is nothing left to resolve.
+func (s *Server) resolveAlertmanagerAlert(c *gin.Context, amAlert models.AlertmanagerAlert) (bool, error) {
+	alert, err := s.store.GetUnresolvedAlertByFingerprint(c, domain.GetUnresolvedAlertByFingerprintParams{
+		OrgID:       org(c),
+		Fingerprint: amAlert.AlertFingerprint(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAlertNotExists) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var p domain.ResolveAlertByIDParams
+	amAlert.ResolveParams(&p)
+	p.ID = alert.ID
+
+	_, err = s.store.ResolveAlertByIDTX(c, p)
+	if err != nil {
+		// resolved concurrently by someone else
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
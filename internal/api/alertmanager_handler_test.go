@@ -0,0 +1,227 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestReceiveAlertmanagerWebhook(t *testing.T) {
+	alert, _ := randomAlert()
+	startsAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(time.Hour)
+
+	firing := gin.H{
+		"status": "firing",
+		"labels": gin.H{
+			"alertname": "DiskFull",
+			"severity":  "critical",
+			"instance":  "db-1",
+		},
+		"annotations": gin.H{
+			"summary": "disk is full on db-1",
+			"runbook": "https://runbooks.example.com/disk-full",
+		},
+		"startsAt":     startsAt,
+		"endsAt":       "0001-01-01T00:00:00Z",
+		"generatorURL": "http://prometheus:9090/graph",
+		"fingerprint":  "c3c2a9f1e5b7d6a8",
+	}
+	resolved := gin.H{
+		"status":      "resolved",
+		"labels":      gin.H{"alertname": "HighLatency"},
+		"annotations": gin.H{},
+		"startsAt":    startsAt,
+		"endsAt":      endsAt,
+		"fingerprint": "0f9e8d7c6b5a4321",
+	}
+	payload := func(alerts ...gin.H) gin.H {
+		return gin.H{
+			"version":  "4",
+			"groupKey": `{}:{alertname="DiskFull"}`,
+			"status":   "firing",
+			"receiver": "alert-service",
+			"alerts":   alerts,
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name: "firing alert is created",
+			body: payload(firing),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateAlertParams)
+						return p.Fingerprint == "c3c2a9f1e5b7d6a8" &&
+							p.Message == "disk is full on db-1" &&
+							p.Severity == db.SeverityCritical &&
+							p.Source == models.AlertmanagerSource &&
+							p.CreatedAt.Equal(startsAt) &&
+							bytes.Contains(p.Labels, []byte(`"instance":"db-1"`)) &&
+							bytes.Contains(p.Annotations, []byte(`"generatorURL":"http://prometheus:9090/graph"`))
+					}), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireWebhookCounts(t, recorder, models.AlertmanagerWebhookRes{Created: 1})
+			},
+		},
+		{
+			name: "repeated firing alert is deduplicated",
+			body: payload(firing),
+			buildStubs: func(store *mockdb.MockStore) {
+				duplicate := *alert
+				duplicate.Occurrences = 3
+
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&duplicate, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireWebhookCounts(t, recorder, models.AlertmanagerWebhookRes{Deduplicated: 1})
+			},
+		},
+		{
+			name: "resolved alert resolves the matching alert",
+			body: payload(resolved),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUnresolvedAlertByFingerprint(gomock.Any(), gomock.Eq(domain.GetUnresolvedAlertByFingerprintParams{OrgID: db.DefaultOrgID, Fingerprint: "0f9e8d7c6b5a4321"})).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					ResolveAlertByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ResolveAlertByIDParams)
+						return p.ID == alert.ID && p.ResolvedBy == models.AlertmanagerSource && p.ResolvedAt.Equal(endsAt)
+					})).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireWebhookCounts(t, recorder, models.AlertmanagerWebhookRes{Resolved: 1})
+			},
+		},
+		{
+			name: "resolved alert without a match is ignored",
+			body: payload(firing, resolved),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+
+				store.EXPECT().
+					GetUnresolvedAlertByFingerprint(gomock.Any(), gomock.Eq(domain.GetUnresolvedAlertByFingerprintParams{OrgID: db.DefaultOrgID, Fingerprint: "0f9e8d7c6b5a4321"})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+
+				store.EXPECT().
+					ResolveAlertByIDTX(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireWebhookCounts(t, recorder, models.AlertmanagerWebhookRes{Created: 1, Ignored: 1})
+			},
+		},
+		{
+			name: "unsupported payload version",
+			body: gin.H{
+				"version": "3",
+				"status":  "firing",
+				"alerts":  []gin.H{firing},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "store error asks alertmanager to retry",
+			body: payload(firing),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "webhook requires credentials",
+			body:      payload(firing),
+			anonymous: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/integrations/alertmanager", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				// Add basic auth
+				auth := "integrationUser:integrationUserPassword"
+				encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+				request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func requireWebhookCounts(t *testing.T, recorder *httptest.ResponseRecorder, want models.AlertmanagerWebhookRes) {
+	var got models.AlertmanagerWebhookRes
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, want, got)
+}
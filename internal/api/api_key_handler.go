@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/apikey"
+)
+
+// CreateAPIKey issues a new API key. The response is the only time the key is
+// shown; only its hash is kept.
+func (s *Server) CreateAPIKey(c *gin.Context) {
+	var (
+		req models.CreateAPIKeyReq
+		p   domain.CreateAPIKeyParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	key, err := apikey.Generate()
+	if err != nil {
+		s.logger.Error("error generating api key", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while generating api key")))
+		return
+	}
+
+	p.Prefix = key.Prefix
+	p.KeyHash = key.Hash
+	p.CreatedBy = actor(c)
+	p.OrgID = org(c)
+
+	s.logger.Info("creating api key...", zap.String("createdBy", p.CreatedBy))
+	created, err := s.store.CreateAPIKey(c, p)
+	if err != nil {
+		s.logger.Error("error creating api key entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while creating api key")))
+		return
+	}
+
+	s.logger.Info("created api key.", zap.String("externalId", created.ExternalID.String()), zap.String("prefix", created.Prefix))
+	c.JSON(http.StatusCreated, models.NewCreatedAPIKeyResponse(created, key.Token))
+}
+
+func (s *Server) ListAPIKeys(c *gin.Context) {
+	s.logger.Info("listing api keys...")
+	keys, err := s.store.ListAPIKeys(c, org(c))
+	if err != nil {
+		s.logger.Error("error listing api keys", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing api keys")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewAPIKeyListResponse(keys))
+}
+
+func (s *Server) RevokeAPIKeyByExternalID(c *gin.Context) {
+	var externalID uuid.UUID
+
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	s.logger.Info("revoking api key...", zap.String("externalID", externalID.String()))
+	key, err := s.store.RevokeAPIKeyByExternalID(c, domain.RevokeAPIKeyByExternalIDParams{
+		RevokedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrAPIKeyNotExists) {
+			s.logger.Warn("api key not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("api key not found")))
+			return
+		}
+
+		s.logger.Error("error revoking api key", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while revoking api key")))
+		return
+	}
+
+	s.logger.Info("revoked api key.", zap.String("externalId", key.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewAPIKeyResponse(key))
+}
@@ -0,0 +1,483 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+	"github.com/josephlbailey/alert-service/internal/pkg/apikey"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		admin         bool
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "create api key",
+			admin: true,
+			body:  gin.H{"name": "ci", "scopes": []string{ScopeAlertsWrite}, "expiresAt": expiresAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, p domain.CreateAPIKeyParams) (*domain.ApiKey, error) {
+						require.Equal(t, "ci", p.Name)
+						require.Equal(t, []string{ScopeAlertsWrite}, p.Scopes)
+						require.Equal(t, "adminServiceUser", p.CreatedBy)
+						require.True(t, p.ExpiresAt.Time.Equal(expiresAt))
+						return &domain.ApiKey{
+							ExternalID: p.ExternalID,
+							Name:       p.Name,
+							Prefix:     p.Prefix,
+							KeyHash:    p.KeyHash,
+							Scopes:     p.Scopes,
+							CreatedAt:  p.CreatedAt,
+							CreatedBy:  p.CreatedBy,
+							ExpiresAt:  p.ExpiresAt,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var res models.CreatedAPIKeyRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+
+				prefix, err := apikey.Prefix(res.Key)
+				require.NoError(t, err)
+				require.Equal(t, res.Prefix, prefix)
+				require.Equal(t, "ci", res.Name)
+				require.NotContains(t, recorder.Body.String(), apikey.Hash(res.Key))
+			},
+		},
+		{
+			name:  "create api key without admin",
+			admin: false,
+			body:  gin.H{"name": "ci", "scopes": []string{ScopeAlertsWrite}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "create api key with unknown scope",
+			admin: true,
+			body:  gin.H{"name": "ci", "scopes": []string{"alerts:purge"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "create api key without scopes",
+			admin: true,
+			body:  gin.H{"name": "ci", "scopes": []string{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "create api key that has expired",
+			admin: true,
+			body:  gin.H{"name": "ci", "scopes": []string{ScopeAlertsRead}, "expiresAt": time.Now().Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			if testCase.admin {
+				request.SetBasicAuth("adminServiceUser", "adminServicePassword")
+			} else {
+				addBasicAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListAPIKeys(t *testing.T) {
+	key := randomAPIKey(t, ScopeAlertsRead)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListAPIKeys(gomock.Any(), gomock.Eq(db.DefaultOrgID)).
+		Times(1).
+		Return([]*domain.ApiKey{key.row}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api-keys", nil)
+	require.NoError(t, err)
+	request.SetBasicAuth("adminServiceUser", "adminServicePassword")
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []models.APIKeyRes
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	require.Equal(t, key.row.ExternalID, res[0].ExternalID)
+	require.NotContains(t, recorder.Body.String(), key.row.KeyHash)
+}
+
+func TestRevokeAPIKeyByExternalID(t *testing.T) {
+	key := randomAPIKey(t, ScopeAlertsRead)
+
+	testCases := []struct {
+		name          string
+		externalID    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "revoke api key",
+			externalID: key.row.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := *key.row
+				revoked.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					RevokeAPIKeyByExternalID(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.RevokeAPIKeyByExternalIDParams)
+						return p.ExternalID == key.row.ExternalID && p.RevokedAt.Valid
+					})).
+					Times(1).
+					Return(&revoked, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "revokedAt")
+			},
+		},
+		{
+			name:       "revoke missing api key",
+			externalID: key.row.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokeAPIKeyByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAPIKeyNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "revoke api key with invalid external ID",
+			externalID: "not-a-uuid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RevokeAPIKeyByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api-keys/%s", testCase.externalID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			request.SetBasicAuth("adminServiceUser", "adminServicePassword")
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	writer := randomAPIKey(t, ScopeAlertsWrite)
+	reader := randomAPIKey(t, ScopeAlertsRead)
+
+	expired := randomAPIKey(t, ScopeAlertsWrite)
+	expired.row.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+	wrongSecret := writer.token[:len(writer.token)-1] + "0"
+	if wrongSecret == writer.token {
+		wrongSecret = writer.token[:len(writer.token)-1] + "1"
+	}
+
+	recent := randomAPIKey(t, ScopeAlertsWrite)
+	recent.row.LastUsedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true}
+
+	testCases := []struct {
+		name          string
+		token         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "key with scope",
+			token: writer.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(writer.row.Prefix)).
+					Times(1).
+					Return(writer.row, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(domain.TouchAPIKeyParams).ID == writer.row.ID
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, APIKeyActor(writer.row.Prefix), recorder.Body.String())
+			},
+		},
+		{
+			name:  "key used recently",
+			token: recent.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(recent.row.Prefix)).
+					Times(1).
+					Return(recent.row, nil)
+				store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "key without scope",
+			token: reader.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(reader.row.Prefix)).
+					Times(1).
+					Return(reader.row, nil)
+				store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "expired key",
+			token: expired.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(expired.row, nil)
+				store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "key with wrong secret",
+			token: wrongSecret,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(writer.row, nil)
+				store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "revoked key",
+			token: writer.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrAPIKeyNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "malformed key",
+			token: "not-a-key",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "key store unavailable",
+			token: writer.token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			router := gin.New()
+			router.POST("/", server.authorize(ScopeAlertsWrite), func(c *gin.Context) {
+				c.String(http.StatusOK, actor(c))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/", nil)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer "+testCase.token)
+
+			router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAPIKeyOrg(t *testing.T) {
+	key := randomAPIKey(t, ScopeAlertsRead)
+	key.row.OrgID = 2
+	alert, _ := randomAlert()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).Times(1).Return(key.row, nil)
+	store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetOrganizationBySlug(gomock.Any(), gomock.Any()).Times(0)
+
+	// the alert belongs to another organization, so the key cannot see it
+	store.EXPECT().
+		GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: 2, ExternalID: alert.ExternalID})).
+		Times(1).
+		Return(nil, db.ErrAlertNotExists)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/alert/%s", alert.ExternalID), nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+key.token)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestPrincipalCan(t *testing.T) {
+	admin := &Principal{Scopes: []string{ScopeAdmin}}
+	require.True(t, admin.Can(ScopeAlertsDelete))
+	require.True(t, admin.Can(ScopeAdmin))
+
+	writer := &Principal{Scopes: []string{ScopeAlertsWrite}}
+	require.True(t, writer.Can(ScopeAlertsRead))
+	require.True(t, writer.Can(ScopeAlertsWrite))
+	require.False(t, writer.Can(ScopeAlertsDelete))
+	require.False(t, writer.Can(ScopeAdmin))
+
+	reader := &Principal{Scopes: []string{ScopeAlertsRead}}
+	require.True(t, reader.Can(ScopeAlertsRead))
+	require.False(t, reader.Can(ScopeAlertsWrite))
+}
+
+type testAPIKey struct {
+	token string
+	row   *domain.ApiKey
+}
+
+func randomAPIKey(t *testing.T, scopes ...string) testAPIKey {
+	key, err := apikey.Generate()
+	require.NoError(t, err)
+
+	return testAPIKey{
+		token: key.Token,
+		row: &domain.ApiKey{
+			ID:         1,
+			ExternalID: uuid.Must(uuid.NewV4()),
+			Name:       "ci",
+			Prefix:     key.Prefix,
+			KeyHash:    key.Hash,
+			Scopes:     scopes,
+			CreatedAt:  time.Now(),
+			CreatedBy:  "adminServiceUser",
+		},
+	}
+}
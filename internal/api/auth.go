@@ -0,0 +1,397 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/apikey"
+	"github.com/josephlbailey/alert-service/internal/pkg/password"
+)
+
+const (
+	AuthBackendConfig   = "config"
+	AuthBackendPostgres = "postgres"
+
+	principalKey = "principal"
+)
+
+// Scopes limit what a principal may do. Write implies read, and admin implies
+// everything; deleting and restoring alerts takes its own scope.
+const (
+	ScopeAlertsRead   = "alerts:read"
+	ScopeAlertsWrite  = "alerts:write"
+	ScopeAlertsDelete = "alerts:delete"
+	ScopeAdmin        = "admin"
+)
+
+// lastUsedResolution is how stale the last use of an API key may get before it
+// is written again, so that a busy key does not cost a write per request.
+const lastUsedResolution = time.Minute
+
+// ErrInvalidCredentials is returned by an Authenticator for an unknown user or
+// a wrong password.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is an authenticated API user. Users and JWTs have the scopes of
+// their Roles; API keys have the scopes they were given. A principal belongs
+// to the organization OrgID, or the one with the slug Org if that is not
+// known, and otherwise to the default organization.
+type Principal struct {
+	Username string
+	Scopes   []string
+	Roles    []string
+	OrgID    int32
+	Org      string
+}
+
+// Can reports whether the principal has scope.
+func (p *Principal) Can(scope string) bool {
+	if slices.Contains(p.Scopes, ScopeAdmin) {
+		return true
+	}
+	switch scope {
+	case ScopeAlertsRead:
+		return slices.Contains(p.Scopes, ScopeAlertsRead) || slices.Contains(p.Scopes, ScopeAlertsWrite)
+	default:
+		return slices.Contains(p.Scopes, scope)
+	}
+}
+
+// Authenticator checks the credentials of an API user. It returns
+// ErrInvalidCredentials when they are wrong, and any other error when it could
+// not tell.
+type Authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (*Principal, error)
+}
+
+// TokenAuthenticator checks a bearer token, returning ErrInvalidCredentials
+// when it is not valid.
+type TokenAuthenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// ConfigAuthenticator authenticates the users listed in the config.
+type ConfigAuthenticator struct {
+	users map[string]config.BasicUser
+}
+
+// NewConfigAuthenticator checks that every user has a well formed password
+// hash and a known role, so that a plaintext password or a misspelt role in
+// the config fails at startup.
+func NewConfigAuthenticator(users []config.BasicUser) (*ConfigAuthenticator, error) {
+	a := &ConfigAuthenticator{users: make(map[string]config.BasicUser, len(users))}
+	for _, user := range users {
+		if _, ok := a.users[user.Username]; ok {
+			return nil, fmt.Errorf("user %q is listed twice", user.Username)
+		}
+		if err := password.Check(user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("user %q: %w", user.Username, err)
+		}
+		if _, err := userPrincipal(user.Username, user.Role); err != nil {
+			return nil, err
+		}
+		a.users[user.Username] = user
+	}
+	return a, nil
+}
+
+func (a *ConfigAuthenticator) Authenticate(_ context.Context, username, pw string) (*Principal, error) {
+	user, ok := a.users[username]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if err := verifyPassword(user.PasswordHash, pw); err != nil {
+		return nil, err
+	}
+
+	principal, err := userPrincipal(user.Username, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	principal.Org = user.Org
+	return principal, nil
+}
+
+// StoreAuthenticator authenticates the enabled users of the api_user table.
+type StoreAuthenticator struct {
+	store db.Store
+}
+
+func NewStoreAuthenticator(store db.Store) *StoreAuthenticator {
+	return &StoreAuthenticator{store: store}
+}
+
+func (a *StoreAuthenticator) Authenticate(ctx context.Context, username, pw string) (*Principal, error) {
+	user, err := a.store.GetAPIUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrAPIUserNotExists) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if err = verifyPassword(user.PasswordHash, pw); err != nil {
+		return nil, err
+	}
+
+	principal, err := userPrincipal(user.Username, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	principal.OrgID = user.OrgID
+	return principal, nil
+}
+
+// APIKeyAuthenticator authenticates the API keys of the api_key table, keeping
+// track of when each was last used.
+type APIKeyAuthenticator struct {
+	store db.Store
+	now   func() time.Time
+}
+
+func NewAPIKeyAuthenticator(store db.Store) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{store: store, now: time.Now}
+}
+
+func (a *APIKeyAuthenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	prefix, err := apikey.Prefix(token)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	key, err := a.store.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, db.ErrAPIKeyNotExists) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	now := a.now()
+	if !apikey.Matches(token, key.KeyHash) || (key.ExpiresAt.Valid && !now.Before(key.ExpiresAt.Time)) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) >= lastUsedResolution {
+		err = a.store.TouchAPIKey(ctx, domain.TouchAPIKeyParams{
+			LastUsedAt: pgtype.Timestamptz{Time: now, Valid: true},
+			ID:         key.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Principal{
+		Username: APIKeyActor(key.Prefix),
+		Scopes:   key.Scopes,
+		OrgID:    key.OrgID,
+	}, nil
+}
+
+// APIKeyActor is the name under which the changes made with an API key are
+// recorded.
+func APIKeyActor(prefix string) string {
+	return "apikey:" + prefix
+}
+
+// Authenticators tries each authenticator in turn. A user unknown to one may
+// still be known to the next, but any other error stops the search.
+type Authenticators []Authenticator
+
+func (a Authenticators) Authenticate(ctx context.Context, username, pw string) (*Principal, error) {
+	for _, authenticator := range a {
+		principal, err := authenticator.Authenticate(ctx, username, pw)
+		if !errors.Is(err, ErrInvalidCredentials) {
+			return principal, err
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// newAuthenticator builds the authenticator for the configured backends. With
+// Basic credentials disabled, it accepts none.
+func newAuthenticator(config config.Config, store db.Store) (Authenticator, error) {
+	backends := config.Auth.Backends
+	if len(backends) == 0 {
+		backends = []string{AuthBackendConfig}
+	}
+
+	var authenticators Authenticators
+	if config.Auth.DisableBasic {
+		return authenticators, nil
+	}
+
+	for _, backend := range backends {
+		switch backend {
+		case AuthBackendConfig:
+			authenticator, err := NewConfigAuthenticator(config.Users)
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, authenticator)
+		case AuthBackendPostgres:
+			authenticators = append(authenticators, NewStoreAuthenticator(store))
+		default:
+			return nil, fmt.Errorf("unknown auth backend %q", backend)
+		}
+	}
+	return authenticators, nil
+}
+
+// TokenAuthenticators tries each token authenticator in turn, as
+// Authenticators does.
+type TokenAuthenticators []TokenAuthenticator
+
+func (a TokenAuthenticators) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	for _, authenticator := range a {
+		principal, err := authenticator.AuthenticateToken(ctx, token)
+		if !errors.Is(err, ErrInvalidCredentials) {
+			return principal, err
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// newTokenAuthenticator builds the authenticator for bearer tokens: API keys,
+// and JWTs when they are enabled.
+func newTokenAuthenticator(config config.Config, logger *zap.Logger, store db.Store) (TokenAuthenticator, error) {
+	tokens := TokenAuthenticators{NewAPIKeyAuthenticator(store)}
+	if !config.Auth.JWT.Enabled {
+		return tokens, nil
+	}
+
+	authenticator, err := newJWTAuthenticator(config.Auth.JWT, logger)
+	if err != nil {
+		return nil, err
+	}
+	return append(tokens, authenticator), nil
+}
+
+func verifyPassword(hash, pw string) error {
+	err := password.Verify(hash, pw)
+	if errors.Is(err, password.ErrMismatch) {
+		return ErrInvalidCredentials
+	}
+	return err
+}
+
+// authenticate requires Basic credentials that the authenticator accepts, or
+// a bearer token that the token authenticator does, answering 401 otherwise.
+// The user is then available through actor, and the request is scoped to the
+// organization of the user.
+func (s *Server) authenticate(c *gin.Context) {
+	principal, err := s.principal(c)
+	if err != nil {
+
+		if errors.Is(err, ErrInvalidCredentials) {
+			c.Writer.Header().Add("WWW-Authenticate", `Basic realm="Authorization Required"`)
+			c.Writer.Header().Add("WWW-Authenticate", `Bearer realm="Authorization Required"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		s.logger.Error("error authenticating user", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, NewError(errors.New("error occurred while authenticating")))
+		return
+	}
+
+	orgID, err := s.orgID(c, principal)
+	if err != nil {
+
+		if errors.Is(err, db.ErrOrganizationNotExists) {
+			s.logger.Warn("principal of unknown organization, returning 403", zap.String("actor", principal.Username), zap.String("org", principal.Org))
+			c.AbortWithStatusJSON(http.StatusForbidden, NewError(err))
+			return
+		}
+
+		s.logger.Error("error resolving organization", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, NewError(errors.New("error occurred while authenticating")))
+		return
+	}
+
+	c.Set(gin.AuthUserKey, principal.Username)
+	c.Set(principalKey, principal)
+	s.scopeOrg(c, orgID)
+}
+
+// orgID returns the organization of principal. Slugs are looked up once and
+// remembered, as organizations keep their ids.
+func (s *Server) orgID(ctx context.Context, principal *Principal) (int32, error) {
+	switch {
+	case principal.OrgID != 0:
+		return principal.OrgID, nil
+	case principal.Org == "":
+		return db.DefaultOrgID, nil
+	}
+
+	if orgID, ok := s.orgs.Load(principal.Org); ok {
+		return orgID.(int32), nil
+	}
+
+	org, err := s.store.GetOrganizationBySlug(ctx, principal.Org)
+	if err != nil {
+		return 0, err
+	}
+
+	s.orgs.Store(principal.Org, org.ID)
+	return org.ID, nil
+}
+
+// scopeOrg scopes the store calls made for the request to the organization
+// orgID.
+func (s *Server) scopeOrg(c *gin.Context, orgID int32) {
+	c.Request = c.Request.WithContext(db.WithOrg(c.Request.Context(), orgID))
+}
+
+// org returns the organization the request is scoped to. A request that is
+// not scoped to one gets 0, which matches no alerts.
+func org(c *gin.Context) int32 {
+	orgID, _ := db.OrgFrom(c.Request.Context())
+	return orgID
+}
+
+// authorize authenticates the request and requires the principal to have
+// scope, answering 403 if it does not.
+func (s *Server) authorize(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.authenticate(c)
+		if c.IsAborted() {
+			return
+		}
+
+		if principal, _ := s.principal(c); !principal.Can(scope) {
+			s.logger.Warn("principal lacks scope, returning 403", zap.String("actor", actor(c)), zap.String("scope", scope))
+			c.AbortWithStatusJSON(http.StatusForbidden, NewError(fmt.Errorf("the %s scope is required", scope)))
+		}
+	}
+}
+
+// principal returns the principal authenticated for the request, checking its
+// credentials if that has not been done yet.
+func (s *Server) principal(c *gin.Context) (*Principal, error) {
+	if principal, ok := c.Get(principalKey); ok {
+		return principal.(*Principal), nil
+	}
+
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return s.tokens.AuthenticateToken(c, strings.TrimSpace(token))
+	}
+
+	username, pw, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return s.auth.Authenticate(c, username, pw)
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+	"github.com/josephlbailey/alert-service/internal/pkg/password"
+)
+
+func TestConfigAuthenticator(t *testing.T) {
+	bcryptHash, err := password.HashBcrypt("bcryptPassword", bcrypt.MinCost)
+	require.NoError(t, err)
+	argon2Hash, err := password.HashArgon2id("argon2Password", password.Argon2Params{Memory: 64, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32})
+	require.NoError(t, err)
+
+	authenticator, err := NewConfigAuthenticator([]config.BasicUser{
+		{Username: "bcryptUser", PasswordHash: bcryptHash, Role: RoleAdmin},
+		{Username: "argon2User", PasswordHash: argon2Hash, Role: RoleViewer},
+	})
+	require.NoError(t, err)
+
+	principal, err := authenticator.Authenticate(context.Background(), "bcryptUser", "bcryptPassword")
+	require.NoError(t, err)
+	require.Equal(t, "bcryptUser", principal.Username)
+	require.Equal(t, []string{RoleAdmin}, principal.Roles)
+	require.True(t, principal.Can(ScopeAdmin))
+
+	principal, err = authenticator.Authenticate(context.Background(), "argon2User", "argon2Password")
+	require.NoError(t, err)
+	require.Equal(t, []string{RoleViewer}, principal.Roles)
+	require.True(t, principal.Can(ScopeAlertsRead))
+	require.False(t, principal.Can(ScopeAlertsWrite))
+
+	_, err = authenticator.Authenticate(context.Background(), "bcryptUser", "argon2Password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(context.Background(), "unknownUser", "bcryptPassword")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = NewConfigAuthenticator([]config.BasicUser{{Username: "plainUser", PasswordHash: "plainPassword"}})
+	require.ErrorIs(t, err, password.ErrInvalidHash)
+
+	_, err = NewConfigAuthenticator([]config.BasicUser{{Username: "bcryptUser", PasswordHash: bcryptHash, Role: "superuser"}})
+	require.Error(t, err)
+
+	// a user of a config still using the admin flag has no role
+	_, err = NewConfigAuthenticator([]config.BasicUser{{Username: "bcryptUser", PasswordHash: bcryptHash}})
+	require.ErrorContains(t, err, "has no role")
+
+	_, err = NewConfigAuthenticator([]config.BasicUser{
+		{Username: "bcryptUser", PasswordHash: bcryptHash, Role: RoleAdmin},
+		{Username: "bcryptUser", PasswordHash: argon2Hash, Role: RoleAdmin},
+	})
+	require.Error(t, err)
+}
+
+func TestStoreAuthenticator(t *testing.T) {
+	hash, err := password.HashBcrypt("dbPassword", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAPIUserByUsername(gomock.Any(), gomock.Eq("dbUser")).
+		AnyTimes().
+		Return(&domain.ApiUser{Username: "dbUser", PasswordHash: hash, Role: RoleResponder}, nil)
+	store.EXPECT().
+		GetAPIUserByUsername(gomock.Any(), gomock.Eq("unknownUser")).
+		AnyTimes().
+		Return(nil, db.ErrAPIUserNotExists)
+	store.EXPECT().
+		GetAPIUserByUsername(gomock.Any(), gomock.Eq("brokenUser")).
+		AnyTimes().
+		Return(nil, errors.New("connection refused"))
+
+	authenticator := NewStoreAuthenticator(store)
+
+	principal, err := authenticator.Authenticate(context.Background(), "dbUser", "dbPassword")
+	require.NoError(t, err)
+	require.Equal(t, "dbUser", principal.Username)
+	require.True(t, principal.Can(ScopeAlertsWrite))
+	require.False(t, principal.Can(ScopeAlertsDelete))
+
+	_, err = authenticator.Authenticate(context.Background(), "dbUser", "wrongPassword")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(context.Background(), "unknownUser", "dbPassword")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(context.Background(), "brokenUser", "dbPassword")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthenticate(t *testing.T) {
+	hash, err := password.HashBcrypt("dbPassword", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		username      string
+		password      string
+		anonymous     bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "config user",
+			username: "integrationUser",
+			password: "integrationUserPassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIUserByUsername(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "integrationUser", recorder.Body.String())
+			},
+		},
+		{
+			name:     "postgres user",
+			username: "dbUser",
+			password: "dbPassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIUserByUsername(gomock.Any(), gomock.Eq("dbUser")).
+					Times(1).
+					Return(&domain.ApiUser{Username: "dbUser", PasswordHash: hash, Role: RoleViewer}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "dbUser", recorder.Body.String())
+			},
+		},
+		{
+			name:     "wrong password",
+			username: "integrationUser",
+			password: "integrationUserPasswor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIUserByUsername(gomock.Any(), gomock.Eq("integrationUser")).
+					Times(1).
+					Return(nil, db.ErrAPIUserNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+			},
+		},
+		{
+			name:      "no credentials",
+			anonymous: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIUserByUsername(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "user store unavailable",
+			username: "dbUser",
+			password: "dbPassword",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIUserByUsername(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.Auth.Backends = []string{AuthBackendConfig, AuthBackendPostgres}
+			auth, err := newAuthenticator(server.config, store)
+			require.NoError(t, err)
+			server.auth = auth
+
+			router := gin.New()
+			router.GET("/", server.authenticate, func(c *gin.Context) {
+				c.String(http.StatusOK, actor(c))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				request.SetBasicAuth(testCase.username, testCase.password)
+			}
+
+			router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAuthenticateOrg(t *testing.T) {
+	hash, err := password.HashBcrypt("orgPassword", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		username      string
+		requests      int
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "user of the default organization",
+			username: "defaultUser",
+			requests: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOrganizationBySlug(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "1", recorder.Body.String())
+			},
+		},
+		{
+			name:     "user of another organization",
+			username: "acmeUser",
+			requests: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrganizationBySlug(gomock.Any(), gomock.Eq("acme")).
+					Times(1).
+					Return(&domain.Organization{ID: 2, Slug: "acme"}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "2", recorder.Body.String())
+			},
+		},
+		{
+			name:     "user of an unknown organization",
+			username: "unknownOrgUser",
+			requests: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrganizationBySlug(gomock.Any(), gomock.Eq("unknown")).
+					Times(1).
+					Return(nil, db.ErrOrganizationNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "organization store unavailable",
+			username: "acmeUser",
+			requests: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrganizationBySlug(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			auth, err := NewConfigAuthenticator([]config.BasicUser{
+				{Username: "defaultUser", PasswordHash: hash, Role: RoleViewer},
+				{Username: "acmeUser", PasswordHash: hash, Role: RoleViewer, Org: "acme"},
+				{Username: "unknownOrgUser", PasswordHash: hash, Role: RoleViewer, Org: "unknown"},
+			})
+			require.NoError(t, err)
+			server.auth = auth
+
+			router := gin.New()
+			router.GET("/", server.authenticate, func(c *gin.Context) {
+				c.String(http.StatusOK, "%d", org(c))
+			})
+
+			for i := 0; i < testCase.requests; i++ {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodGet, "/", nil)
+				require.NoError(t, err)
+				request.SetBasicAuth(testCase.username, "orgPassword")
+
+				router.ServeHTTP(recorder, request)
+				testCase.checkResponse(recorder)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+)
+
+// ApplyAlertBatch runs a batch of alert creates, updates and deletes. Each
+// operation is answered with the status its single alert request would have
+// had. An atomic batch answers with 200 when all of them are applied, or with
+// the status of the one that failed; a partial batch always answers 207.
+// Deletes take the alerts:delete scope, as they do on their own.
+func (s *Server) ApplyAlertBatch(c *gin.Context) {
+	var req models.AlertBatchReq
+
+	ops, invalid, err := req.Bind(c)
+
+	if err != nil {
+		return
+	}
+
+	if principal, _ := s.principal(c); !principal.Can(ScopeAlertsDelete) && slices.ContainsFunc(ops, isBatchDelete) {
+		s.logger.Warn("batch deletes alerts without the scope, returning 403", zap.String("actor", actor(c)))
+		c.JSON(http.StatusForbidden, NewError(fmt.Errorf("the %s scope is required to delete alerts", ScopeAlertsDelete)))
+		return
+	}
+
+	atomic := req.Mode == models.BatchAtomic
+	results := make([]*models.AlertBatchItemRes, len(ops))
+
+	for i, errs := range invalid {
+		results[i] = &models.AlertBatchItemRes{Index: i, Status: http.StatusBadRequest, Errors: errs}
+	}
+
+	if atomic && len(invalid) > 0 {
+		s.logger.Warn("invalid batch operations, returning 400", zap.Int("invalid", len(invalid)))
+		for i := range results {
+			if results[i] == nil {
+				results[i] = &models.AlertBatchItemRes{Index: i, Status: http.StatusFailedDependency, Error: db.ErrBatchRolledBack.Error()}
+			}
+		}
+		c.JSON(http.StatusBadRequest, models.AlertBatchRes{Mode: req.Mode, Results: results})
+		return
+	}
+
+	valid := make([]db.AlertBatchOp, 0, len(ops))
+	indexes := make([]int, 0, len(ops))
+	for i, op := range ops {
+		if results[i] == nil {
+			valid = append(valid, op)
+			indexes = append(indexes, i)
+		}
+	}
+
+	s.logger.Info("applying alert batch...", zap.String("mode", req.Mode), zap.Int("operations", len(valid)))
+	applied, err := s.store.ApplyAlertBatchTX(c, org(c), valid, atomic, actor(c))
+	if err != nil {
+		s.logger.Error("error applying alert batch", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while applying alert batch")))
+		return
+	}
+
+	status := http.StatusOK
+	for j, result := range applied {
+		i := indexes[j]
+		results[i] = s.batchItemResponse(i, valid[j], result)
+		if result.Err != nil && !errors.Is(result.Err, db.ErrBatchRolledBack) {
+			status = results[i].Status
+		}
+	}
+
+	switch {
+	case !atomic:
+		status = http.StatusMultiStatus
+	case status >= http.StatusInternalServerError:
+		status = http.StatusInternalServerError
+	}
+
+	s.logger.Info("applied alert batch.", zap.Int("status", status))
+	c.JSON(status, models.AlertBatchRes{Mode: req.Mode, Results: results})
+}
+
+// batchItemResponse maps the outcome of an operation to the status its single
+// alert request would have had.
+func (s *Server) batchItemResponse(index int, op db.AlertBatchOp, result db.AlertBatchResult) *models.AlertBatchItemRes {
+	res := &models.AlertBatchItemRes{Index: index}
+
+	err := result.Err
+	switch {
+	case err == nil:
+		res.Status = http.StatusOK
+		if op.Create != nil && result.Alert.Occurrences == 1 {
+			res.Status = http.StatusCreated
+		}
+		res.Alert = models.NewAlertResponse(result.Alert)
+		return res
+	case errors.Is(err, db.ErrBatchRolledBack):
+		res.Status = http.StatusFailedDependency
+	case errors.Is(err, db.ErrAlertNotExists):
+		res.Status = http.StatusNotFound
+		err = errors.New("alert not found")
+	case errors.Is(err, db.ErrAlertVersionStale):
+		res.Status = http.StatusPreconditionFailed
+		err = errPreconditionFailed
+	case errors.Is(err, db.ErrInvalidStatusTransition), errors.Is(err, db.ErrDuplicateOpenAlert):
+		res.Status = http.StatusConflict
+		err = statusUpdateError(op.ExternalID, err)
+	default:
+		s.logger.Error("error applying batch operation", zap.Int("index", index), zap.Error(err))
+		res.Status = http.StatusInternalServerError
+		err = errors.New("error occurred while applying operation")
+	}
+
+	res.Error = err.Error()
+	return res
+}
+
+func isBatchDelete(op db.AlertBatchOp) bool {
+	return op.Delete != nil
+}
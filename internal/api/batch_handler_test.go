@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestApplyAlertBatch(t *testing.T) {
+	alert, message := randomAlert()
+	externalID := uuid.Must(uuid.NewV4())
+
+	operations := []gin.H{
+		{"op": "create", "alert": gin.H{"message": message}},
+		{"op": "update", "externalId": externalID, "version": 1, "alert": gin.H{"message": message, "severity": "critical", "status": "open"}},
+		{"op": "delete", "externalId": externalID, "version": 2},
+	}
+
+	testCases := []struct {
+		name          string
+		anonymous     bool
+		responder     bool
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes)
+	}{
+		{
+			name: "atomic batch applied",
+			body: gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Eq(db.DefaultOrgID), gomock.Cond(func(x any) bool {
+						ops := x.([]db.AlertBatchOp)
+						return len(ops) == 3 &&
+							ops[0].Create != nil && ops[0].Create.Message == message &&
+							ops[1].Update != nil && ops[1].ExternalID == externalID && ops[1].Update.Version.Int32 == 1 &&
+							ops[2].Delete != nil && ops[2].ExternalID == externalID && ops[2].Delete.Version.Int32 == 2
+					}), gomock.Eq(true), gomock.Eq("adminServiceUser")).
+					Times(1).
+					Return([]db.AlertBatchResult{{Alert: alert}, {Alert: alert}, {Alert: alert}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, models.BatchAtomic, res.Mode)
+				require.Len(t, res.Results, 3)
+				require.Equal(t, http.StatusCreated, res.Results[0].Status)
+				require.Equal(t, alert.ExternalID, res.Results[0].Alert.ExternalID)
+				require.Equal(t, http.StatusOK, res.Results[1].Status)
+				require.Equal(t, http.StatusOK, res.Results[2].Status)
+			},
+		},
+		{
+			name: "atomic batch rolled back",
+			body: gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Eq(db.DefaultOrgID), gomock.Any(), gomock.Eq(true), gomock.Any()).
+					Times(1).
+					Return([]db.AlertBatchResult{
+						{Err: db.ErrBatchRolledBack},
+						{Err: db.ErrAlertVersionStale},
+						{Err: db.ErrBatchRolledBack},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				require.Equal(t, http.StatusFailedDependency, res.Results[0].Status)
+				require.Equal(t, http.StatusPreconditionFailed, res.Results[1].Status)
+				require.Equal(t, http.StatusFailedDependency, res.Results[2].Status)
+				require.Nil(t, res.Results[0].Alert)
+			},
+		},
+		{
+			name: "partial batch with mixed results",
+			body: gin.H{"mode": "partial", "operations": []gin.H{
+				operations[0],
+				{"op": "update", "externalId": externalID, "version": 1, "alert": gin.H{"severity": "critical"}},
+				operations[2],
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Eq(db.DefaultOrgID), gomock.Cond(func(x any) bool {
+						ops := x.([]db.AlertBatchOp)
+						return len(ops) == 2 && ops[0].Create != nil && ops[1].Delete != nil
+					}), gomock.Eq(false), gomock.Any()).
+					Times(1).
+					Return([]db.AlertBatchResult{{Alert: alert}, {Err: db.ErrAlertNotExists}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusMultiStatus, recorder.Code)
+				require.Equal(t, models.BatchPartial, res.Mode)
+				require.Equal(t, http.StatusCreated, res.Results[0].Status)
+				require.Equal(t, http.StatusBadRequest, res.Results[1].Status)
+				require.Equal(t, "alert.message", res.Results[1].Errors[0].Field)
+				require.Equal(t, http.StatusNotFound, res.Results[2].Status)
+			},
+		},
+		{
+			name: "atomic batch with invalid operation",
+			body: gin.H{"operations": []gin.H{
+				operations[0],
+				{"op": "delete", "externalId": externalID},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusFailedDependency, res.Results[0].Status)
+				require.Equal(t, http.StatusBadRequest, res.Results[1].Status)
+				require.Equal(t, "version", res.Results[1].Errors[0].Field)
+			},
+		},
+		{
+			name: "unknown operation",
+			body: gin.H{"operations": []gin.H{{"op": "upsert"}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, "op", res.Results[0].Errors[0].Field)
+			},
+		},
+		{
+			name: "empty batch",
+			body: gin.H{"operations": []gin.H{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "internal server error",
+			body: gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "responder cannot delete",
+			responder: true,
+			body:      gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "responder applies batch without deletes",
+			responder: true,
+			body:      gin.H{"operations": operations[:2]},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Eq(db.DefaultOrgID), gomock.Any(), gomock.Eq(true), gomock.Eq("integrationUser")).
+					Times(1).
+					Return([]db.AlertBatchResult{{Alert: alert}, {Alert: alert}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, res.Results, 2)
+			},
+		},
+		{
+			name:      "unauthorized",
+			anonymous: true,
+			body:      gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApplyAlertBatchTX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, res models.AlertBatchRes) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/alert/batch", bytes.NewReader(body))
+			require.NoError(t, err)
+
+			switch {
+			case testCase.responder:
+				addBasicAuth(request)
+			case !testCase.anonymous:
+				addAdminAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+
+			var res models.AlertBatchRes
+			_ = json.Unmarshal(recorder.Body.Bytes(), &res)
+			testCase.checkResponse(recorder, res)
+		})
+	}
+}
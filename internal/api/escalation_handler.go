@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func (s *Server) CreateEscalationPolicy(c *gin.Context) {
+	var (
+		req models.CreateEscalationPolicyReq
+		p   domain.CreateEscalationPolicyParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("creating escalation policy...", zap.String("name", p.Name))
+	policy, err := s.store.CreateEscalationPolicy(c, p)
+	if err != nil {
+		s.logger.Error("error creating escalation policy entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("created escalation policy.", zap.String("externalId", policy.ExternalID.String()))
+	c.JSON(http.StatusCreated, models.NewEscalationPolicyResponse(policy))
+}
+
+func (s *Server) ListEscalationPolicies(c *gin.Context) {
+	s.logger.Info("listing escalation policies...")
+	policies, err := s.store.ListEscalationPolicies(c, org(c))
+	if err != nil {
+		s.logger.Error("error listing escalation policies", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing escalation policies")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewEscalationPolicyListResponse(policies))
+}
+
+func (s *Server) GetEscalationPolicyByExternalID(c *gin.Context) {
+	policy, ok := s.escalationPolicy(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewEscalationPolicyResponse(policy))
+}
+
+func (s *Server) UpdateEscalationPolicyByExternalID(c *gin.Context) {
+	var (
+		req models.UpdateEscalationPolicyReq
+		p   domain.UpdateEscalationPolicyByIDParams
+	)
+
+	policy, ok := s.escalationPolicy(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = policy.OrgID
+	p.ID = policy.ID
+
+	s.logger.Info("updating escalation policy...", zap.String("externalID", policy.ExternalID.String()))
+	policy, err = s.store.UpdateEscalationPolicyByID(c, p)
+	if err != nil {
+		s.logger.Error("error updating escalation policy entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("updated escalation policy.", zap.String("externalId", policy.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewEscalationPolicyResponse(policy))
+}
+
+func (s *Server) DeleteEscalationPolicyByExternalID(c *gin.Context) {
+	policy, ok := s.escalationPolicy(c)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("deleting escalation policy...", zap.String("externalID", policy.ExternalID.String()))
+	err := s.store.DeleteEscalationPolicyByID(c, domain.DeleteEscalationPolicyByIDParams{
+		OrgID: policy.OrgID,
+		ID:    policy.ID,
+	})
+	if err != nil {
+		s.logger.Error("error deleting escalation policy entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("deleted escalation policy.", zap.String("externalId", policy.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewEscalationPolicyResponse(policy))
+}
+
+// escalationPolicy loads the escalation policy named by the externalID path
+// parameter, writing the error response and returning false if it cannot.
+func (s *Server) escalationPolicy(c *gin.Context) (*domain.EscalationPolicy, bool) {
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return nil, false
+	}
+
+	policy, err := s.store.GetEscalationPolicyByExternalID(c, domain.GetEscalationPolicyByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrEscalationPolicyNotExists) {
+			s.logger.Warn("escalation policy not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("escalation policy not found")))
+			return nil, false
+		}
+
+		s.logger.Error("error getting escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting escalation policy")))
+		return nil, false
+	}
+	return policy, true
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestCreateEscalationPolicy(t *testing.T) {
+	policy := randomEscalationPolicy()
+
+	testCases := []testCase{
+		{
+			name: "create escalation policy",
+			body: gin.H{
+				"name":       "database critical",
+				"matchers":   []string{"team=db"},
+				"severities": []string{db.SeverityCritical},
+				"steps": []gin.H{
+					{"target": "primary-oncall", "waitMinutes": 10},
+					{"target": "db-lead"},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEscalationPolicy(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateEscalationPolicyParams)
+						return p.Name == "database critical" &&
+							p.OrgID == db.DefaultOrgID &&
+							string(p.Matchers) == `[{"name":"team","type":"=","value":"db"}]` &&
+							string(p.Steps) == `[{"target":"primary-oncall","waitMinutes":10},{"target":"db-lead","waitMinutes":0}]`
+					})).
+					Times(1).
+					Return(policy, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var got models.EscalationPolicyRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, policy.ExternalID, got.ExternalID)
+				require.Equal(t, []string{`team="db"`}, got.Matchers)
+				require.Len(t, got.Steps, 2)
+				require.Equal(t, int32(10), got.Steps[0].WaitMinutes)
+			},
+		},
+		{
+			name: "create escalation policy without steps",
+			body: gin.H{
+				"name":  "empty",
+				"steps": []gin.H{},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscalationPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "create escalation policy with step missing target",
+			body: gin.H{
+				"name":  "anonymous step",
+				"steps": []gin.H{{"waitMinutes": 5}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscalationPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "target")
+			},
+		},
+		{
+			name: "create escalation policy with negative wait",
+			body: gin.H{
+				"name":  "time travel",
+				"steps": []gin.H{{"target": "primary-oncall", "waitMinutes": -1}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscalationPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "create escalation policy with invalid matcher",
+			body: gin.H{
+				"name":     "bad matcher",
+				"matchers": []string{"not a matcher"},
+				"steps":    []gin.H{{"target": "primary-oncall"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscalationPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "create escalation policy unauthorized",
+			anonymous: true,
+			body: gin.H{
+				"name":  "database critical",
+				"steps": []gin.H{{"target": "primary-oncall"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscalationPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				addBasicAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateEscalationPolicyByExternalID(t *testing.T) {
+	policy := randomEscalationPolicy()
+
+	testCases := []testCase{
+		{
+			name:       "update escalation policy",
+			externalID: policy.ExternalID.String(),
+			body: gin.H{
+				"name":  "database critical",
+				"steps": []gin.H{{"target": "secondary-oncall", "waitMinutes": 30}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEscalationPolicyByExternalID(gomock.Any(), gomock.Eq(domain.GetEscalationPolicyByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: policy.ExternalID})).
+					Times(1).
+					Return(policy, nil)
+				store.EXPECT().
+					UpdateEscalationPolicyByID(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.UpdateEscalationPolicyByIDParams)
+						return p.OrgID == db.DefaultOrgID && p.ID == policy.ID && string(p.Matchers) == "[]" && len(p.Severities) == 0
+					})).
+					Times(1).
+					Return(policy, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "update escalation policy not found",
+			externalID: policy.ExternalID.String(),
+			body: gin.H{
+				"name":  "database critical",
+				"steps": []gin.H{{"target": "secondary-oncall"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEscalationPolicyByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrEscalationPolicyNotExists)
+				store.EXPECT().UpdateEscalationPolicyByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/escalation-policies/%s", testCase.externalID), bytes.NewBuffer(data))
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteEscalationPolicyByExternalID(t *testing.T) {
+	policy := randomEscalationPolicy()
+
+	testCases := []testCase{
+		{
+			name:       "delete escalation policy",
+			externalID: policy.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEscalationPolicyByExternalID(gomock.Any(), gomock.Eq(domain.GetEscalationPolicyByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: policy.ExternalID})).
+					Times(1).
+					Return(policy, nil)
+				store.EXPECT().
+					DeleteEscalationPolicyByID(gomock.Any(), gomock.Eq(domain.DeleteEscalationPolicyByIDParams{OrgID: db.DefaultOrgID, ID: policy.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "delete escalation policy with invalid id",
+			externalID: "invalidUUID",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscalationPolicyByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/escalation-policies/%s", testCase.externalID), nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func randomEscalationPolicy() *domain.EscalationPolicy {
+	return &domain.EscalationPolicy{
+		ID:         1,
+		ExternalID: uuid.Must(uuid.NewV4()),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Name:       "database critical",
+		Matchers:   []byte(`[{"name":"team","type":"=","value":"db"}]`),
+		Severities: []string{db.SeverityCritical},
+		Steps:      []byte(`[{"target":"primary-oncall","waitMinutes":10},{"target":"db-lead","waitMinutes":0}]`),
+		OrgID:      db.DefaultOrgID,
+	}
+}
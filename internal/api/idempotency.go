@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	defaultIdempotencyTTL   = 24 * time.Hour
+)
+
+// replayedHeaders are the response headers kept along with the body.
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// bodyRecorder keeps a copy of the response body as it is written.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent lets clients retry a request safely by sending an
+// Idempotency-Key header. The first request with a key is handled as usual
+// and its response kept; a retry with the same key and body gets that
+// response back without being handled again. Keys are scoped to the
+// authenticated user and their organization, so it must run after the auth
+// middleware. Server errors
+// are not kept, leaving the request free to be retried.
+func (s *Server) idempotent(c *gin.Context) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		s.logger.Warn("idempotency key too long, returning 400")
+		c.AbortWithStatusJSON(http.StatusBadRequest, NewError(errors.New("idempotency key must be at most 255 characters")))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		s.logger.Warn("error reading request body, returning 400", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, NewError(errors.New("error reading request body")))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := requestHash(c.Request.Method, c.Request.URL.Path, body)
+
+	ttl := s.config.Idempotency.TTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	now := time.Now()
+	_, err = s.store.ReserveIdempotencyKey(c, domain.ReserveIdempotencyKeyParams{
+		Actor:       actor(c),
+		Key:         key,
+		RequestHash: hash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		OrgID:       org(c),
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrIdempotencyKeyExists) {
+			s.replayIdempotent(c, key, hash)
+			return
+		}
+
+		s.logger.Error("error reserving idempotency key", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, NewError(errors.New("error occurred while reserving idempotency key")))
+		return
+	}
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		err = s.store.DeleteIdempotencyKey(c, domain.DeleteIdempotencyKeyParams{OrgID: org(c), Actor: actor(c), Key: key})
+		if err != nil {
+			s.logger.Error("error releasing idempotency key", zap.Error(err))
+		}
+		return
+	}
+
+	headers := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if value := recorder.Header().Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		s.logger.Error("error encoding response headers", zap.Error(err))
+		return
+	}
+
+	err = s.store.CompleteIdempotencyKey(c, domain.CompleteIdempotencyKeyParams{
+		ResponseStatus:  int32(recorder.Status()),
+		ResponseHeaders: data,
+		ResponseBody:    recorder.body.Bytes(),
+		OrgID:           org(c),
+		Actor:           actor(c),
+		Key:             key,
+	})
+	if err != nil {
+		s.logger.Error("error saving idempotent response", zap.Error(err))
+	}
+}
+
+// replayIdempotent answers a retry with the response kept for its key.
+func (s *Server) replayIdempotent(c *gin.Context, key, hash string) {
+	stored, err := s.store.GetIdempotencyKey(c, domain.GetIdempotencyKeyParams{OrgID: org(c), Actor: actor(c), Key: key})
+	if err != nil {
+		s.logger.Error("error getting idempotency key", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting idempotency key")))
+		return
+	}
+
+	if stored.RequestHash != hash {
+		s.logger.Warn("idempotency key reused with a different request, returning 422")
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, NewError(errors.New("idempotency key was already used with a different request")))
+		return
+	}
+
+	if !stored.ResponseStatus.Valid {
+		s.logger.Warn("idempotent request still in progress, returning 409")
+		c.AbortWithStatusJSON(http.StatusConflict, NewError(errors.New("a request with this idempotency key is still in progress")))
+		return
+	}
+
+	var headers map[string]string
+	if err = json.Unmarshal(stored.ResponseHeaders, &headers); err != nil {
+		s.logger.Error("error decoding response headers", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, NewError(errors.New("error occurred while replaying response")))
+		return
+	}
+
+	for name, value := range headers {
+		c.Header(name, value)
+	}
+	c.Header(idempotentReplayedHeader, "true")
+
+	s.logger.Info("replaying idempotent response.", zap.String("key", key))
+	c.Data(int(stored.ResponseStatus.Int32), headers["Content-Type"], stored.ResponseBody)
+	c.Abort()
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
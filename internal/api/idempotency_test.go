@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestIdempotentCreateAlert(t *testing.T) {
+	alert, message := randomAlert()
+
+	body, err := json.Marshal(gin.H{"message": message})
+	require.NoError(t, err)
+	hash := requestHash(http.MethodPost, "/alert", body)
+
+	stored := &domain.IdempotencyKey{
+		Actor:           "integrationUser",
+		Key:             "retry-1",
+		RequestHash:     hash,
+		ResponseStatus:  pgtype.Int4{Int32: http.StatusCreated, Valid: true},
+		ResponseHeaders: []byte(`{"Content-Type":"application/json; charset=utf-8","ETag":"\"1\""}`),
+		ResponseBody:    []byte(`{"externalId":"00000000-0000-0000-0000-000000000000","message":"Hello there"}`),
+	}
+
+	testCases := []struct {
+		name          string
+		key           string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "create alert without idempotency key",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create alert with new idempotency key",
+			key:  "retry-1",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReserveIdempotencyKey(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ReserveIdempotencyKeyParams)
+						return p.OrgID == db.DefaultOrgID && p.Actor == "integrationUser" && p.Key == "retry-1" && p.RequestHash == hash &&
+							p.ExpiresAt.Sub(p.CreatedAt) == defaultIdempotencyTTL
+					})).
+					Times(1).
+					Return(&domain.IdempotencyKey{}, nil)
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(alert, nil)
+				store.EXPECT().
+					CompleteIdempotencyKey(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CompleteIdempotencyKeyParams)
+						return p.OrgID == db.DefaultOrgID && p.Key == "retry-1" && p.ResponseStatus == http.StatusCreated &&
+							strings.Contains(string(p.ResponseHeaders), `"ETag":"\"1\""`) &&
+							strings.Contains(string(p.ResponseBody), message)
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchAlert(t, alert, recorder.Body)
+			},
+		},
+		{
+			name: "retry with the same idempotency key",
+			key:  "retry-1",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrIdempotencyKeyExists)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(domain.GetIdempotencyKeyParams{OrgID: db.DefaultOrgID, Actor: "integrationUser", Key: "retry-1"})).
+					Times(1).
+					Return(stored, nil)
+				store.EXPECT().CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+				require.Equal(t, string(stored.ResponseBody), recorder.Body.String())
+			},
+		},
+		{
+			name: "reuse idempotency key with a different body",
+			key:  "retry-1",
+			body: []byte(`{"message":"something else"}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrIdempotencyKeyExists)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(stored, nil)
+				store.EXPECT().CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "retry while the first request is in progress",
+			key:  "retry-1",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				inProgress := *stored
+				inProgress.ResponseStatus = pgtype.Int4{}
+
+				store.EXPECT().
+					ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrIdempotencyKeyExists)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&inProgress, nil)
+				store.EXPECT().CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "server error releases idempotency key",
+			key:  "retry-1",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.IdempotencyKey{}, nil)
+				store.EXPECT().
+					CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(domain.DeleteIdempotencyKeyParams{OrgID: db.DefaultOrgID, Actor: "integrationUser", Key: "retry-1"})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "idempotency key too long",
+			key:  strings.Repeat("k", maxIdempotencyKeyLength+1),
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/alert", bytes.NewReader(testCase.body))
+			require.NoError(t, err)
+
+			addBasicAuth(request)
+			if testCase.key != "" {
+				request.Header.Set(idempotencyKeyHeader, testCase.key)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+// orgAuthenticator lets the same username in by password into the
+// organization the password names.
+type orgAuthenticator map[string]int32
+
+func (a orgAuthenticator) Authenticate(_ context.Context, username, pw string) (*Principal, error) {
+	orgID, ok := a[pw]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Username: username, Scopes: []string{ScopeAlertsWrite}, OrgID: orgID}, nil
+}
+
+func TestIdempotencyKeyOrgs(t *testing.T) {
+	alert, message := randomAlert()
+	alert.OrgID = 2
+
+	body, err := json.Marshal(gin.H{"message": message})
+	require.NoError(t, err)
+
+	stored := &domain.IdempotencyKey{
+		Actor:           "sharedUser",
+		Key:             "retry-1",
+		RequestHash:     requestHash(http.MethodPost, "/alert", body),
+		ResponseStatus:  pgtype.Int4{Int32: http.StatusCreated, Valid: true},
+		ResponseHeaders: []byte(`{"Content-Type":"application/json; charset=utf-8"}`),
+		ResponseBody:    []byte(`{"externalId":"00000000-0000-0000-0000-000000000000","message":"default organization"}`),
+		OrgID:           db.DefaultOrgID,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the key is taken in the default organization only
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ any, p domain.ReserveIdempotencyKeyParams) (*domain.IdempotencyKey, error) {
+			require.Equal(t, "sharedUser", p.Actor)
+			if p.OrgID == db.DefaultOrgID {
+				return nil, db.ErrIdempotencyKeyExists
+			}
+			return &domain.IdempotencyKey{}, nil
+		})
+	store.EXPECT().
+		GetIdempotencyKey(gomock.Any(), gomock.Eq(domain.GetIdempotencyKeyParams{OrgID: db.DefaultOrgID, Actor: "sharedUser", Key: "retry-1"})).
+		Times(1).
+		Return(stored, nil)
+	store.EXPECT().
+		CreateAlertTX(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(alert, nil)
+	store.EXPECT().
+		CompleteIdempotencyKey(gomock.Any(), gomock.Cond(func(x any) bool {
+			p := x.(domain.CompleteIdempotencyKeyParams)
+			return p.OrgID == 2 && p.Actor == "sharedUser" && p.Key == "retry-1"
+		})).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, store)
+	server.auth = orgAuthenticator{"defaultPassword": db.DefaultOrgID, "otherPassword": 2}
+
+	for _, pw := range []string{"defaultPassword", "otherPassword"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/alert", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.SetBasicAuth("sharedUser", pw)
+		request.Header.Set(idempotencyKeyHeader, "retry-1")
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		if pw == "defaultPassword" {
+			require.Equal(t, string(stored.ResponseBody), recorder.Body.String())
+			continue
+		}
+		// the other organization never sees the response kept for the first
+		require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+		require.NotContains(t, recorder.Body.String(), "default organization")
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func (s *Server) ListIncidents(c *gin.Context) {
+	var (
+		req models.ListIncidentsReq
+		p   domain.ListIncidentsParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("listing incidents...")
+	incidents, err := s.store.ListIncidents(c, p)
+	if err != nil {
+		s.logger.Error("error listing incidents", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing incidents")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewIncidentListResponse(incidents))
+}
+
+func (s *Server) GetIncidentByExternalID(c *gin.Context) {
+	incident, ok := s.incident(c)
+	if !ok {
+		return
+	}
+
+	alerts, err := s.store.ListAlertExternalIDsByIncidentID(c, pgtype.Int4{Int32: incident.ID, Valid: true})
+	if err != nil {
+		s.logger.Error("error listing incident alerts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting incident")))
+		return
+	}
+
+	timeline, err := s.store.ListIncidentTimeline(c, incident.ID)
+	if err != nil {
+		s.logger.Error("error listing incident timeline", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting incident")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewIncidentDetailResponse(incident, alerts, timeline))
+}
+
+func (s *Server) ResolveIncidentByExternalID(c *gin.Context) {
+	var (
+		req  models.ResolveIncidentReq
+		p    domain.ResolveIncidentByIDParams
+		note pgtype.Text
+	)
+
+	incident, ok := s.incident(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p, &note)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = incident.OrgID
+	p.ID = incident.ID
+	p.ResolvedBy = actor(c)
+
+	s.logger.Info("resolving incident...", zap.String("externalID", incident.ExternalID.String()), zap.String("actor", p.ResolvedBy))
+	incident, err = s.store.ResolveIncidentByIDTX(c, p, note)
+
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			s.logger.Warn("incident already resolved, returning 409", zap.Error(err))
+			c.JSON(http.StatusConflict, NewError(err))
+			return
+		}
+
+		s.logger.Error("error resolving incident", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("resolved incident.", zap.String("externalId", incident.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewIncidentResponse(incident))
+}
+
+// incident loads the incident named by the externalID path parameter, writing
+// the error response and returning false if it cannot.
+func (s *Server) incident(c *gin.Context) (*domain.Incident, bool) {
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return nil, false
+	}
+
+	incident, err := s.store.GetIncidentByExternalID(c, domain.GetIncidentByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrIncidentNotExists) {
+			s.logger.Warn("incident not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("incident not found")))
+			return nil, false
+		}
+
+		s.logger.Error("error getting incident", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting incident")))
+		return nil, false
+	}
+	return incident, true
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestListIncidents(t *testing.T) {
+	incident := randomIncident()
+
+	testCases := []testCase{
+		{
+			name:  "list open incidents",
+			query: "?status=open",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListIncidents(gomock.Any(), gomock.Eq(domain.ListIncidentsParams{
+						OrgID:    db.DefaultOrgID,
+						Status:   pgtype.Text{String: db.StatusOpen, Valid: true},
+						PageSize: 50,
+					})).
+					Times(1).
+					Return([]*domain.Incident{incident}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []*models.IncidentRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got, 1)
+				require.Equal(t, map[string]string{"alertname": "DiskFull"}, got[0].GroupLabels)
+				require.Nil(t, got[0].Timeline)
+			},
+		},
+		{
+			name:  "list incidents with invalid status",
+			query: "?status=acknowledged",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListIncidents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/incidents"+testCase.query, nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetIncidentByExternalID(t *testing.T) {
+	incident := randomIncident()
+	alertID := uuid.Must(uuid.NewV4())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetIncidentByExternalID(gomock.Any(), gomock.Eq(domain.GetIncidentByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: incident.ExternalID})).
+		Times(1).
+		Return(incident, nil)
+	store.EXPECT().
+		ListAlertExternalIDsByIncidentID(gomock.Any(), gomock.Eq(pgtype.Int4{Int32: incident.ID, Valid: true})).
+		Times(1).
+		Return([]uuid.UUID{alertID}, nil)
+	store.EXPECT().
+		ListIncidentTimeline(gomock.Any(), gomock.Eq(incident.ID)).
+		Times(1).
+		Return([]*domain.ListIncidentTimelineRow{
+			{IncidentID: incident.ID, CreatedAt: incident.CreatedAt, EntryType: db.TimelineIncidentOpened},
+			{
+				IncidentID:      incident.ID,
+				CreatedAt:       incident.CreatedAt,
+				EntryType:       db.TimelineAlertAdded,
+				AlertID:         pgtype.Int4{Int32: 7, Valid: true},
+				AlertExternalID: pgtype.UUID{Bytes: alertID, Valid: true},
+			},
+		}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/incidents/%s", incident.ExternalID), nil)
+	require.NoError(t, err)
+	addBasicAuth(request)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got models.IncidentRes
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, []uuid.UUID{alertID}, got.Alerts)
+	require.Len(t, got.Timeline, 2)
+	require.Nil(t, got.Timeline[0].Alert)
+	require.Equal(t, db.TimelineAlertAdded, got.Timeline[1].Type)
+	require.Equal(t, alertID, *got.Timeline[1].Alert)
+}
+
+func TestResolveIncidentByExternalID(t *testing.T) {
+	incident := randomIncident()
+
+	testCases := []testCase{
+		{
+			name:       "resolve incident",
+			externalID: incident.ExternalID.String(),
+			body:       gin.H{"note": "disk cleaned up"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIncidentByExternalID(gomock.Any(), gomock.Eq(domain.GetIncidentByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: incident.ExternalID})).
+					Times(1).
+					Return(incident, nil)
+				store.EXPECT().
+					ResolveIncidentByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ResolveIncidentByIDParams)
+						return p.OrgID == db.DefaultOrgID && p.ID == incident.ID && p.ResolvedBy == "integrationUser"
+					}), gomock.Eq(pgtype.Text{String: "disk cleaned up", Valid: true})).
+					Times(1).
+					DoAndReturn(func(_ any, p domain.ResolveIncidentByIDParams, _ pgtype.Text) (*domain.Incident, error) {
+						resolved := *incident
+						resolved.Status = db.StatusResolved
+						resolved.ResolvedAt = pgtype.Timestamptz{Time: p.ResolvedAt, Valid: true}
+						resolved.ResolvedBy = pgtype.Text{String: p.ResolvedBy, Valid: true}
+						return &resolved, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.IncidentRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.StatusResolved, got.Status)
+				require.Equal(t, "integrationUser", got.Resolution.By)
+			},
+		},
+		{
+			name:       "resolve incident without body",
+			externalID: incident.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIncidentByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(incident, nil)
+				store.EXPECT().
+					ResolveIncidentByIDTX(gomock.Any(), gomock.Any(), gomock.Eq(pgtype.Text{})).
+					Times(1).
+					Return(incident, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "resolve resolved incident",
+			externalID: incident.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIncidentByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(incident, nil)
+				store.EXPECT().
+					ResolveIncidentByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: incident is already resolved", db.ErrInvalidStatusTransition))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:       "resolve missing incident",
+			externalID: incident.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIncidentByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrIncidentNotExists)
+				store.EXPECT().ResolveIncidentByIDTX(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "resolve incident unauthorized",
+			externalID: incident.ExternalID.String(),
+			anonymous:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIncidentByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if testCase.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(testCase.body))
+			}
+
+			url := fmt.Sprintf("/incidents/%s/resolve", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPost, url, &body)
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				addBasicAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func randomIncident() *domain.Incident {
+	return &domain.Incident{
+		ID:          1,
+		ExternalID:  uuid.Must(uuid.NewV4()),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		GroupKey:    `1:{"alertname":"DiskFull"}`,
+		GroupLabels: []byte(`{"alertname":"DiskFull"}`),
+		Status:      db.StatusOpen,
+		LastAlertAt: time.Now(),
+		OrgID:       db.DefaultOrgID,
+	}
+}
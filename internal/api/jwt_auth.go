@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt"
+)
+
+const (
+	defaultUsernameClaim = "sub"
+	defaultRolesClaim    = "roles"
+	defaultOrgClaim      = "org"
+
+	jwksFetchTimeout = 10 * time.Second
+)
+
+// JWTAuthenticator authenticates bearer JWTs signed by a key of a JWKS.
+type JWTAuthenticator struct {
+	logger        *zap.Logger
+	keys          jwt.Keys
+	validator     jwt.Validator
+	usernameClaim string
+	rolesClaim    string
+	orgClaim      string
+	roleScopes    map[string][]string
+	now           func() time.Time
+}
+
+func NewJWTAuthenticator(config config.JWTConfig, logger *zap.Logger, keys jwt.Keys) *JWTAuthenticator {
+	a := &JWTAuthenticator{
+		logger: logger,
+		keys:   keys,
+		validator: jwt.Validator{
+			Issuer:   config.Issuer,
+			Audience: config.Audience,
+			Leeway:   config.Leeway,
+		},
+		usernameClaim: config.UsernameClaim,
+		rolesClaim:    config.RolesClaim,
+		orgClaim:      config.OrgClaim,
+		roleScopes:    make(map[string][]string),
+		now:           time.Now,
+	}
+	if a.usernameClaim == "" {
+		a.usernameClaim = defaultUsernameClaim
+	}
+	if a.rolesClaim == "" {
+		a.rolesClaim = defaultRolesClaim
+	}
+	if a.orgClaim == "" {
+		a.orgClaim = defaultOrgClaim
+	}
+
+	for role, scopes := range roleScopes {
+		a.roleScopes[role] = scopes
+	}
+	for role, scopes := range config.RoleScopes {
+		a.roleScopes[strings.ToLower(role)] = scopes
+	}
+	return a
+}
+
+// newJWTAuthenticator loads the configured JWKS. A file is read at once so
+// that a bad path fails at startup; a URL is fetched on first use.
+func newJWTAuthenticator(config config.JWTConfig, logger *zap.Logger) (*JWTAuthenticator, error) {
+	var keys *jwt.KeySet
+	switch {
+	case config.JWKSURL != "" && config.JWKSFile != "":
+		return nil, errors.New("jwt: set one of jwks_url and jwks_file")
+	case config.JWKSURL != "":
+		keys = jwt.NewURLKeySet(config.JWKSURL, &http.Client{Timeout: jwksFetchTimeout}, config.RefreshInterval)
+	case config.JWKSFile != "":
+		keys = jwt.NewFileKeySet(config.JWKSFile, config.RefreshInterval)
+		if err := keys.Load(context.Background()); err != nil {
+			return nil, fmt.Errorf("jwt: loading jwks: %w", err)
+		}
+	default:
+		return nil, errors.New("jwt: jwks_url or jwks_file is required")
+	}
+	return NewJWTAuthenticator(config, logger, keys), nil
+}
+
+func (a *JWTAuthenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	claims, err := jwt.Parse(ctx, token, a.keys)
+	if err != nil {
+		if errors.Is(err, jwt.ErrKeySetUnavailable) {
+			// the token cannot be checked, so it is turned away like a bad one
+			a.logger.Warn("error loading jwks", zap.Error(err))
+		}
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err = a.validator.Validate(claims, a.now()); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	username := claims.String(a.usernameClaim)
+	if username == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	roles := claims.Strings(a.rolesClaim)
+	scopes := []string{}
+	for _, role := range roles {
+		for _, scope := range a.roleScopes[strings.ToLower(role)] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return &Principal{
+		Username: username,
+		Scopes:   scopes,
+		Roles:    roles,
+		Org:      claims.String(a.orgClaim),
+	}, nil
+}
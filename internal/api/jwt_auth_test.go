@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt"
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt/jwttest"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := jwttest.NewRSAKey("rsa-1")
+	require.NoError(t, err)
+	ecKey, err := jwttest.NewECKey("ec-1")
+	require.NoError(t, err)
+	unknownKey, err := jwttest.NewRSAKey("rsa-2")
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, jwttest.JWKS(rsaKey, ecKey), 0o600))
+
+	now := time.Now()
+	claims := func(roles any) map[string]any {
+		return map[string]any{
+			"iss":   "https://idp.example.com",
+			"aud":   "alert-service",
+			"sub":   "jdoe",
+			"exp":   now.Add(time.Hour).Unix(),
+			"roles": roles,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		key           *jwttest.Key
+		claims        map[string]any
+		rolesClaim    string
+		method        string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "RS256 responder writes",
+			key:    rsaKey,
+			claims: claims([]string{"responder"}),
+			method: http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "jdoe", recorder.Body.String())
+			},
+		},
+		{
+			name:   "ES256 viewer reads",
+			key:    ecKey,
+			claims: claims("viewer"),
+			method: http.MethodGet,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "viewer cannot write",
+			key:    ecKey,
+			claims: claims([]string{"viewer"}),
+			method: http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "nested roles claim",
+			key:  rsaKey,
+			claims: func() map[string]any {
+				c := claims(nil)
+				c["realm_access"] = map[string]any{"roles": []string{"Responder"}}
+				return c
+			}(),
+			rolesClaim: "realm_access.roles",
+			method:     http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "expired",
+			key:  rsaKey,
+			claims: func() map[string]any {
+				c := claims([]string{"responder"})
+				c["exp"] = now.Add(-time.Hour).Unix()
+				return c
+			}(),
+			method: http.MethodGet,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "wrong audience",
+			key:  rsaKey,
+			claims: func() map[string]any {
+				c := claims([]string{"responder"})
+				c["aud"] = "other-service"
+				return c
+			}(),
+			method: http.MethodGet,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "unknown key",
+			key:    unknownKey,
+			claims: claims([]string{"responder"}),
+			method: http.MethodGet,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			server.config.Auth.JWT = config.JWTConfig{
+				Enabled:    true,
+				JWKSFile:   jwksFile,
+				Issuer:     "https://idp.example.com",
+				Audience:   "alert-service",
+				Leeway:     time.Minute,
+				RolesClaim: testCase.rolesClaim,
+			}
+			tokens, err := newTokenAuthenticator(server.config, zap.NewNop(), store)
+			require.NoError(t, err)
+			server.tokens = tokens
+
+			router := gin.New()
+			handler := func(c *gin.Context) {
+				c.String(http.StatusOK, actor(c))
+			}
+			router.GET("/", server.authorize(ScopeAlertsRead), handler)
+			router.POST("/", server.authorize(ScopeAlertsWrite), handler)
+
+			token, err := testCase.key.Sign(testCase.claims)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(testCase.method, "/", nil)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer "+token)
+
+			router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestJWTAuthenticatorKeySetUnavailable(t *testing.T) {
+	key, err := jwttest.NewRSAKey("rsa-1")
+	require.NoError(t, err)
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer jwks.Close()
+
+	authenticator := NewJWTAuthenticator(config.JWTConfig{}, zap.NewNop(), jwt.NewURLKeySet(jwks.URL, jwks.Client(), time.Hour))
+
+	token, err := key.Sign(map[string]any{"sub": "jdoe", "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	// a token that cannot be checked is answered with a 401, not a 500
+	_, err = authenticator.AuthenticateToken(context.Background(), token)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestNewJWTAuthenticator(t *testing.T) {
+	_, err := newJWTAuthenticator(config.JWTConfig{Enabled: true}, zap.NewNop())
+	require.Error(t, err)
+
+	_, err = newJWTAuthenticator(config.JWTConfig{Enabled: true, JWKSURL: "https://idp.example.com/jwks", JWKSFile: "jwks.json"}, zap.NewNop())
+	require.Error(t, err)
+
+	_, err = newJWTAuthenticator(config.JWTConfig{Enabled: true, JWKSFile: filepath.Join(t.TempDir(), "missing.json")}, zap.NewNop())
+	require.Error(t, err)
+
+	_, err = newJWTAuthenticator(config.JWTConfig{Enabled: true, JWKSURL: "https://idp.example.com/jwks"}, zap.NewNop())
+	require.NoError(t, err)
+}
+
+func TestDisableBasic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAPIUserByUsername(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.config.Auth.DisableBasic = true
+	auth, err := newAuthenticator(server.config, store)
+	require.NoError(t, err)
+	server.auth = auth
+
+	router := gin.New()
+	router.GET("/", server.authenticate, func(c *gin.Context) {
+		c.String(http.StatusOK, actor(c))
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	addBasicAuth(request)
+
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -1,31 +1,110 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/josephlbailey/alert-service/internal/db"
 	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+	"github.com/josephlbailey/alert-service/internal/pkg/patch"
 )
 
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+var errUnsupportedPatch = errors.New("patch must be " + MergePatchContentType + " or " + JSONPatchContentType)
+
 type CreateAlertReq struct {
-	Message string `json:"message" binding:"required"`
+	Message     string            `json:"message" binding:"required"`
+	Severity    string            `json:"severity" binding:"omitempty,oneof=critical high warning info"`
+	Source      string            `json:"source" binding:"max=255"`
+	Fingerprint string            `json:"fingerprint" binding:"max=255"`
+	Labels      map[string]string `json:"labels" binding:"omitempty,max=64,dive,keys,labelname,endkeys,max=1024"`
+	Annotations map[string]string `json:"annotations" binding:"omitempty,max=64,dive,keys,min=1,max=256,endkeys"`
 }
 
 type UpdateAlertReq struct {
-	Message string `json:"message" binding:"required"`
+	Message     string            `json:"message" binding:"required"`
+	Severity    string            `json:"severity" binding:"omitempty,oneof=critical high warning info"`
+	Status      string            `json:"status" binding:"omitempty,oneof=open acknowledged resolved"`
+	Labels      map[string]string `json:"labels" binding:"omitempty,max=64,dive,keys,labelname,endkeys,max=1024"`
+	Annotations map[string]string `json:"annotations" binding:"omitempty,max=64,dive,keys,min=1,max=256,endkeys"`
+}
+
+type AcknowledgeAlertReq struct {
+	Note string `json:"note" binding:"max=1024"`
+}
+
+type ResolveAlertReq struct {
+	Note string `json:"note" binding:"max=1024"`
 }
 
 type AlertRes struct {
-	ExternalID uuid.UUID `json:"externalId"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
-	Message    string    `json:"message"`
+	ExternalID      uuid.UUID         `json:"externalId"`
+	Version         int32             `json:"version"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+	Message         string            `json:"message"`
+	Severity        string            `json:"severity"`
+	Status          string            `json:"status"`
+	Source          string            `json:"source"`
+	Fingerprint     string            `json:"fingerprint"`
+	Occurrences     int32             `json:"occurrences"`
+	LastSeenAt      time.Time         `json:"lastSeenAt"`
+	Labels          map[string]string `json:"labels"`
+	Annotations     map[string]string `json:"annotations"`
+	Silenced        bool              `json:"silenced"`
+	SilencedUntil   *time.Time        `json:"silencedUntil,omitempty"`
+	InhibitedBy     *uuid.UUID        `json:"inhibitedBy,omitempty"`
+	Acknowledgement *AlertActionRes   `json:"acknowledgement,omitempty"`
+	Resolution      *AlertActionRes   `json:"resolution,omitempty"`
+	Deletion        *AlertActionRes   `json:"deletion,omitempty"`
+}
+
+// AlertActionRes records who acknowledged, resolved or deleted an alert.
+type AlertActionRes struct {
+	At   time.Time `json:"at"`
+	By   string    `json:"by"`
+	Note string    `json:"note,omitempty"`
+}
+
+type ListAlertsReq struct {
+	CreatedAfter   *time.Time `json:"createdAfter" form:"createdAfter"`
+	CreatedBefore  *time.Time `json:"createdBefore" form:"createdBefore"`
+	UpdatedAfter   *time.Time `json:"updatedAfter" form:"updatedAfter"`
+	UpdatedBefore  *time.Time `json:"updatedBefore" form:"updatedBefore"`
+	Message        string     `json:"message" form:"message"`
+	Severity       string     `json:"severity" form:"severity" binding:"omitempty,oneof=critical high warning info"`
+	Status         string     `json:"status" form:"status" binding:"omitempty,oneof=open acknowledged resolved"`
+	Label          []string   `json:"label" form:"label"`
+	Sort           string     `json:"sort" form:"sort" binding:"omitempty,oneof=createdAt updatedAt"`
+	Order          string     `json:"order" form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit          int32      `json:"limit" form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor         string     `json:"cursor" form:"cursor"`
+	IncludeDeleted bool       `json:"include_deleted" form:"include_deleted"`
+}
+
+// GetAlertReq holds the query parameters of a single alert lookup.
+type GetAlertReq struct {
+	IncludeDeleted bool `json:"include_deleted" form:"include_deleted"`
+}
+
+type ListAlertsRes struct {
+	Alerts     []*AlertRes `json:"alerts"`
+	NextCursor string      `json:"nextCursor,omitempty"`
 }
 
 type ErrorMsg struct {
@@ -35,71 +114,342 @@ type ErrorMsg struct {
 
 func (req *CreateAlertReq) Bind(c *gin.Context, p *domain.CreateAlertParams) error {
 	if err := c.ShouldBindJSON(req); err != nil {
-		var (
-			ve validator.ValidationErrors
-			je *json.UnmarshalTypeError
-		)
-		if errors.As(err, &ve) {
-			out := make([]ErrorMsg, len(ve))
-			for i, fe := range ve {
-				out[i] = ErrorMsg{fe.Field(), getErrorMsg(fe)}
-			}
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
-		} else if errors.As(err, &je) {
-			out := make([]ErrorMsg, 1)
-			out[0] = ErrorMsg{je.Field, "invalid type for field"}
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
-		}
+		abortWithBindError(c, err)
 		return err
 	}
 
+	req.params(p)
+	return nil
+}
+
+func (req *CreateAlertReq) params(p *domain.CreateAlertParams) {
 	p.ExternalID = uuid.Must(uuid.NewV4())
 	p.CreatedAt = time.Now()
 	p.UpdatedAt = time.Now()
 	p.Message = req.Message
-	return nil
+	p.Severity = req.Severity
+	if p.Severity == "" {
+		p.Severity = db.SeverityWarning
+	}
+	p.LastSeenAt = p.CreatedAt
+	p.Source = req.Source
+	p.Fingerprint = req.Fingerprint
+	if p.Fingerprint == "" {
+		p.Fingerprint = db.Fingerprint(req.Source, req.Labels, req.Message)
+	}
+	p.Labels = jsonObject(req.Labels)
+	p.Annotations = jsonObject(req.Annotations)
 }
 
 func (req *UpdateAlertReq) Bind(c *gin.Context, p *domain.UpdateAlertByIDParams) error {
 	if err := c.ShouldBindJSON(req); err != nil {
-		var (
-			ve validator.ValidationErrors
-			je *json.UnmarshalTypeError
-		)
-		if errors.As(err, &ve) {
-			out := make([]ErrorMsg, len(ve))
-			for i, fe := range ve {
-				out[i] = ErrorMsg{fe.Field(), getErrorMsg(fe)}
-			}
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
-		} else if errors.As(err, &je) {
-			out := make([]ErrorMsg, 1)
-			out[0] = ErrorMsg{je.Field, "invalid type for field"}
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		abortWithBindError(c, err)
+		return err
+	}
+
+	req.params(p)
+	return nil
+}
+
+// BindPatch applies the patch in the request body to alert and reads the
+// result as a full update, so it is held to the same rules as Bind. The patch
+// may be a JSON Merge Patch or a JSON Patch, going by the Content-Type.
+func (req *UpdateAlertReq) BindPatch(c *gin.Context, alert *domain.Alert, p *domain.UpdateAlertByIDParams) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	current, err := json.Marshal(UpdateAlertReq{
+		Message:     alert.Message,
+		Severity:    alert.Severity,
+		Status:      alert.Status,
+		Labels:      decodeMap(alert.Labels),
+		Annotations: decodeMap(alert.Annotations),
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"errors": []ErrorMsg{{"body", err.Error()}}})
+		return err
+	}
+
+	var patched []byte
+	switch c.ContentType() {
+	case MergePatchContentType:
+		patched, err = patch.Merge(current, body)
+	case JSONPatchContentType:
+		patched, err = patch.Apply(current, body)
+	default:
+		c.Header("Accept-Patch", MergePatchContentType+", "+JSONPatchContentType)
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"errors": []ErrorMsg{{"Content-Type", errUnsupportedPatch.Error()}}})
+		return errUnsupportedPatch
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, patch.ErrConflict) {
+			status = http.StatusConflict
 		}
+		c.AbortWithStatusJSON(status, gin.H{"errors": []ErrorMsg{{"patch", err.Error()}}})
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(req); err == nil {
+		err = binding.Validator.ValidateStruct(req)
+	}
+	if err != nil {
+		abortWithBindError(c, err)
 		return err
 	}
 
+	req.params(p)
+	return nil
+}
+
+func (req *UpdateAlertReq) params(p *domain.UpdateAlertByIDParams) {
 	p.UpdatedAt = time.Now()
 	p.Message = req.Message
+	p.Severity = text(req.Severity)
+	p.Status = text(req.Status)
+	p.Labels = jsonb(req.Labels)
+	p.Annotations = jsonb(req.Annotations)
+}
+
+// Bind reads the optional note. An empty body is allowed; the actor and time
+// are filled in by the handler.
+func (req *AcknowledgeAlertReq) Bind(c *gin.Context, p *domain.AcknowledgeAlertByIDParams) error {
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.AcknowledgedAt = time.Now()
+	p.AcknowledgedNote = text(req.Note)
+	return nil
+}
+
+// Bind reads the optional note. An empty body is allowed; the actor and time
+// are filled in by the handler.
+func (req *ResolveAlertReq) Bind(c *gin.Context, p *domain.ResolveAlertByIDParams) error {
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.ResolvedAt = time.Now()
+	p.ResolvedNote = text(req.Note)
 	return nil
 }
 
+func (req *GetAlertReq) Bind(c *gin.Context) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+	return nil
+}
+
+// Bind reads the listing query parameters into p, and the regular expression
+// label matchers, which the database cannot evaluate, into ms. PageSize is set
+// one past the requested limit so the handler can tell whether another page
+// exists.
+func (req *ListAlertsReq) Bind(c *gin.Context, p *domain.ListAlertsParams, ms *labels.Matchers) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	if req.Limit == 0 {
+		req.Limit = DefaultListLimit
+	}
+
+	p.CreatedAfter = timestamptz(req.CreatedAfter)
+	p.CreatedBefore = timestamptz(req.CreatedBefore)
+	p.UpdatedAfter = timestamptz(req.UpdatedAfter)
+	p.UpdatedBefore = timestamptz(req.UpdatedBefore)
+	if req.Message != "" {
+		p.Message = pgtype.Text{String: escapeLike(req.Message), Valid: true}
+	}
+	p.Severity = text(req.Severity)
+	p.Status = text(req.Status)
+	regexps, err := bindLabelMatchers(req.Label, p)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"label", err.Error()}}})
+		return err
+	}
+	*ms = regexps
+
+	p.SortBy = "created_at"
+	if req.Sort == "updatedAt" {
+		p.SortBy = "updated_at"
+	}
+	p.SortDesc = req.Order == "desc"
+	p.IncludeDeleted = req.IncludeDeleted
+	p.PageSize = req.Limit + 1
+
+	if req.Cursor != "" {
+		cur, err := decodeCursor(req.Cursor)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"cursor", "invalid cursor"}}})
+			return err
+		}
+		p.CursorID = pgtype.Int4{Int32: cur.ID, Valid: true}
+		p.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+	return nil
+}
+
+// bindLabelMatchers splits the label filters of a listing. Equality matchers
+// on non-empty values become a containment filter that can use the labels
+// index, and the other plain matchers are evaluated per row. The regular
+// expression matchers are returned to be evaluated by the store.
+func bindLabelMatchers(filters []string, p *domain.ListAlertsParams) (labels.Matchers, error) {
+	ms, err := labels.ParseMatchers(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var regexps labels.Matchers
+	contain := make(map[string]string)
+	for _, m := range ms {
+		if m.IsRegexp() {
+			regexps = append(regexps, m)
+			continue
+		}
+		if _, seen := contain[m.Name]; m.Type == labels.MatchEqual && m.Value != "" && !seen {
+			contain[m.Name] = m.Value
+			continue
+		}
+		p.MatcherNames = append(p.MatcherNames, m.Name)
+		p.MatcherTypes = append(p.MatcherTypes, string(m.Type))
+		p.MatcherValues = append(p.MatcherValues, m.Value)
+	}
+	if len(contain) > 0 {
+		p.LabelsContain = jsonb(contain)
+	}
+	return regexps, nil
+}
+
+// NewListAlertsResponse trims the extra row fetched by ListAlertsReq.Bind and,
+// when it was present, returns a cursor pointing just past the last alert.
+// When the listing stopped short of a page, scanned is the last alert it read
+// and the cursor points past that instead.
+func NewListAlertsResponse(alerts []*domain.Alert, scanned *domain.Alert, p domain.ListAlertsParams) *ListAlertsRes {
+	resp := new(ListAlertsRes)
+	resp.Alerts = make([]*AlertRes, 0, len(alerts))
+
+	limit := int(p.PageSize - 1)
+	if len(alerts) > limit {
+		alerts = alerts[:limit]
+		scanned = alerts[len(alerts)-1]
+	}
+	if scanned != nil {
+		cur := pageCursor{ID: scanned.ID, Time: scanned.CreatedAt}
+		if p.SortBy == "updated_at" {
+			cur.Time = scanned.UpdatedAt
+		}
+		resp.NextCursor = cur.encode()
+	}
+
+	for _, alert := range alerts {
+		resp.Alerts = append(resp.Alerts, NewAlertResponse(alert))
+	}
+	return resp
+}
+
 func NewAlertResponse(alert *domain.Alert) *AlertRes {
 	resp := new(AlertRes)
 	resp.CreatedAt = alert.CreatedAt
 	resp.UpdatedAt = alert.UpdatedAt
 	resp.ExternalID = alert.ExternalID
+	resp.Version = alert.Version
 	resp.Message = alert.Message
+	resp.Severity = alert.Severity
+	resp.Status = alert.Status
+	resp.Source = alert.Source
+	resp.Fingerprint = alert.Fingerprint
+	resp.Occurrences = alert.Occurrences
+	resp.LastSeenAt = alert.LastSeenAt
+	resp.Labels = decodeMap(alert.Labels)
+	resp.Annotations = decodeMap(alert.Annotations)
+	if db.AlertSilenced(alert, time.Now()) {
+		resp.Silenced = true
+		resp.SilencedUntil = &alert.SilencedUntil.Time
+	}
+	if db.AlertInhibited(alert) {
+		inhibitedBy := uuid.UUID(alert.InhibitedBy.Bytes)
+		resp.InhibitedBy = &inhibitedBy
+	}
+	if alert.AcknowledgedAt.Valid {
+		resp.Acknowledgement = &AlertActionRes{
+			At:   alert.AcknowledgedAt.Time,
+			By:   alert.AcknowledgedBy.String,
+			Note: alert.AcknowledgedNote.String,
+		}
+	}
+	if alert.ResolvedAt.Valid {
+		resp.Resolution = &AlertActionRes{
+			At:   alert.ResolvedAt.Time,
+			By:   alert.ResolvedBy.String,
+			Note: alert.ResolvedNote.String,
+		}
+	}
+	if alert.DeletedAt.Valid {
+		resp.Deletion = &AlertActionRes{
+			At: alert.DeletedAt.Time,
+			By: alert.DeletedBy.String,
+		}
+	}
 	return resp
 }
 
+func abortWithBindError(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": bindErrors(err)})
+}
+
+// bindErrors describes what is wrong with a request that failed to bind.
+func bindErrors(err error) []ErrorMsg {
+	var (
+		ve validator.ValidationErrors
+		je *json.UnmarshalTypeError
+	)
+	if errors.As(err, &ve) {
+		out := make([]ErrorMsg, len(ve))
+		for i, fe := range ve {
+			out[i] = ErrorMsg{fe.Field(), getErrorMsg(fe)}
+		}
+		return out
+	} else if errors.As(err, &je) {
+		return []ErrorMsg{{je.Field, "invalid type for field"}}
+	}
+	return []ErrorMsg{{"body", err.Error()}}
+}
+
 func getErrorMsg(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
 		return "this field is required"
+	case "required_unless":
+		return "this field is required unless " + fe.Param()
 	case "gte":
 		return "should be greater than " + fe.Param()
+	case "lte":
+		return "should be less than " + fe.Param()
+	case "labelname":
+		return "invalid label name"
+	case "min":
+		return "should be at least " + fe.Param() + " characters"
+	case "max":
+		return "should be at most " + fe.Param() + " characters"
+	case "http_url":
+		return "should be an http or https URL"
+	case "oneof":
+		return "should be one of " + fe.Param()
+	case "timezone":
+		return "should be an IANA time zone name"
+	case "datetime":
+		return "should be in the format " + fe.Param()
 	}
 	return "unknown error"
 }
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	AlertmanagerSource   = "alertmanager"
+	AlertmanagerFiring   = "firing"
+	AlertmanagerResolved = "resolved"
+)
+
+// AlertmanagerWebhookReq is the version 4 payload Alertmanager sends to a
+// webhook_config receiver.
+type AlertmanagerWebhookReq struct {
+	Version           string              `json:"version" binding:"required,eq=4"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status" binding:"required,oneof=firing resolved"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts" binding:"required,dive"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status" binding:"required,oneof=firing resolved"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+type AlertmanagerWebhookRes struct {
+	Created      int `json:"created"`
+	Deduplicated int `json:"deduplicated"`
+	Resolved     int `json:"resolved"`
+	Ignored      int `json:"ignored"`
+}
+
+func (req *AlertmanagerWebhookReq) Bind(c *gin.Context) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+	return nil
+}
+
+// AlertFingerprint returns the fingerprint Alertmanager computed for the
+// alert's label set, falling back to our own when it is absent.
+func (a *AlertmanagerAlert) AlertFingerprint() string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+	return db.Fingerprint(AlertmanagerSource, a.Labels, "")
+}
+
+// CreateParams maps a firing Alertmanager alert onto a new alert row.
+func (a *AlertmanagerAlert) CreateParams(p *domain.CreateAlertParams) {
+	now := time.Now()
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = a.StartsAt
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+	p.LastSeenAt = now
+	p.Message = a.message()
+	p.Severity = a.severity()
+	p.Source = AlertmanagerSource
+	p.Fingerprint = a.AlertFingerprint()
+	p.Labels = jsonObject(a.Labels)
+	p.Annotations = jsonObject(a.annotations())
+}
+
+// ResolveParams maps a resolved Alertmanager alert onto the resolution of
+// the matching alert row.
+func (a *AlertmanagerAlert) ResolveParams(p *domain.ResolveAlertByIDParams) {
+	p.ResolvedAt = a.EndsAt
+	if p.ResolvedAt.IsZero() {
+		p.ResolvedAt = time.Now()
+	}
+	p.ResolvedBy = AlertmanagerSource
+}
+
+func (a *AlertmanagerAlert) message() string {
+	for _, key := range []string{"summary", "description", "message"} {
+		if v := a.Annotations[key]; v != "" {
+			return v
+		}
+	}
+	if v := a.Labels["alertname"]; v != "" {
+		return v
+	}
+	return "alert received from Alertmanager"
+}
+
+func (a *AlertmanagerAlert) severity() string {
+	switch a.Labels["severity"] {
+	case db.SeverityCritical, "page":
+		return db.SeverityCritical
+	case db.SeverityHigh, "error", "major":
+		return db.SeverityHigh
+	case db.SeverityInfo, "none":
+		return db.SeverityInfo
+	}
+	return db.SeverityWarning
+}
+
+// annotations keeps the link back to the generating rule alongside the
+// Alertmanager annotations.
+func (a *AlertmanagerAlert) annotations() map[string]string {
+	if a.GeneratorURL == "" {
+		return a.Annotations
+	}
+	out := make(map[string]string, len(a.Annotations)+1)
+	for k, v := range a.Annotations {
+		out[k] = v
+	}
+	if _, ok := out["generatorURL"]; !ok {
+		out["generatorURL"] = a.GeneratorURL
+	}
+	return out
+}
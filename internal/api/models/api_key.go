@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name" binding:"required,max=255"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=alerts:read alerts:write alerts:delete admin"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// APIKeyRes describes an API key. The key itself is only ever returned once,
+// by CreatedAPIKeyRes.
+type APIKeyRes struct {
+	ExternalID uuid.UUID  `json:"externalId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	CreatedBy  string     `json:"createdBy"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+type CreatedAPIKeyRes struct {
+	APIKeyRes
+	Key string `json:"key"`
+}
+
+// Bind reads the request into p. The key and its creator are filled in by the
+// handler.
+func (req *CreateAPIKeyReq) Bind(c *gin.Context, p *domain.CreateAPIKeyParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"expiresAt", "should be in the future"}}})
+		return errInvalidAPIKey
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.Name = req.Name
+	p.Scopes = req.Scopes
+	p.CreatedAt = time.Now()
+	p.ExpiresAt = timestamptz(req.ExpiresAt)
+	return nil
+}
+
+func NewAPIKeyResponse(key *domain.ApiKey) *APIKeyRes {
+	resp := new(APIKeyRes)
+	resp.ExternalID = key.ExternalID
+	resp.Name = key.Name
+	resp.Prefix = key.Prefix
+	resp.Scopes = key.Scopes
+	resp.CreatedAt = key.CreatedAt
+	resp.CreatedBy = key.CreatedBy
+	if key.ExpiresAt.Valid {
+		resp.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		resp.LastUsedAt = &key.LastUsedAt.Time
+	}
+	if key.RevokedAt.Valid {
+		resp.RevokedAt = &key.RevokedAt.Time
+	}
+	return resp
+}
+
+func NewCreatedAPIKeyResponse(key *domain.ApiKey, token string) *CreatedAPIKeyRes {
+	return &CreatedAPIKeyRes{APIKeyRes: *NewAPIKeyResponse(key), Key: token}
+}
+
+func NewAPIKeyListResponse(keys []*domain.ApiKey) []*APIKeyRes {
+	resp := make([]*APIKeyRes, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, NewAPIKeyResponse(key))
+	}
+	return resp
+}
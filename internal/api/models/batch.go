@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	BatchAtomic  = "atomic"
+	BatchPartial = "partial"
+)
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// AlertBatchReq is a list of alert operations. In atomic mode, the default,
+// either all of them are applied or none are; in partial mode each one stands
+// on its own.
+type AlertBatchReq struct {
+	Mode       string            `json:"mode" binding:"omitempty,oneof=atomic partial"`
+	Operations []json.RawMessage `json:"operations" binding:"required,min=1,max=500"`
+}
+
+// AlertBatchOpReq is one operation of a batch. Updates and deletes name the
+// version of the alert they apply to, as If-Match does for the single alert
+// endpoints.
+type AlertBatchOpReq struct {
+	Op         string          `json:"op" binding:"required,oneof=create update delete"`
+	ExternalID *uuid.UUID      `json:"externalId" binding:"required_unless=Op create"`
+	Version    *int32          `json:"version" binding:"required_unless=Op create"`
+	Alert      json.RawMessage `json:"alert" binding:"required_unless=Op delete"`
+}
+
+type AlertBatchRes struct {
+	Mode    string               `json:"mode"`
+	Results []*AlertBatchItemRes `json:"results"`
+}
+
+// AlertBatchItemRes is the outcome of one operation, with the HTTP status the
+// matching single alert request would have returned.
+type AlertBatchItemRes struct {
+	Index  int        `json:"index"`
+	Status int        `json:"status"`
+	Alert  *AlertRes  `json:"alert,omitempty"`
+	Error  string     `json:"error,omitempty"`
+	Errors []ErrorMsg `json:"errors,omitempty"`
+}
+
+// Bind reads the batch and its operations. ops holds an entry for every
+// operation; those that are not valid are left zero and described in invalid
+// by their index.
+func (req *AlertBatchReq) Bind(c *gin.Context) (ops []db.AlertBatchOp, invalid map[int][]ErrorMsg, err error) {
+	if err = c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return nil, nil, err
+	}
+
+	if req.Mode == "" {
+		req.Mode = BatchAtomic
+	}
+
+	ops = make([]db.AlertBatchOp, len(req.Operations))
+	invalid = make(map[int][]ErrorMsg)
+	for i, data := range req.Operations {
+		var opReq AlertBatchOpReq
+		if errs := opReq.parse(data, &ops[i]); errs != nil {
+			invalid[i] = errs
+		}
+	}
+	return ops, invalid, nil
+}
+
+func (req *AlertBatchOpReq) parse(data json.RawMessage, op *db.AlertBatchOp) []ErrorMsg {
+	if err := json.Unmarshal(data, req); err != nil {
+		return bindErrors(err)
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return bindErrors(err)
+	}
+
+	switch req.Op {
+	case BatchCreate:
+		var alert CreateAlertReq
+		if errs := decodeBatchAlert(req.Alert, &alert); errs != nil {
+			return errs
+		}
+		op.Create = new(domain.CreateAlertParams)
+		alert.params(op.Create)
+	case BatchUpdate:
+		var alert UpdateAlertReq
+		if errs := decodeBatchAlert(req.Alert, &alert); errs != nil {
+			return errs
+		}
+		op.ExternalID = *req.ExternalID
+		op.Update = new(domain.UpdateAlertByIDParams)
+		alert.params(op.Update)
+		op.Update.Version = pgtype.Int4{Int32: *req.Version, Valid: true}
+	case BatchDelete:
+		op.ExternalID = *req.ExternalID
+		op.Delete = &domain.DeleteAlertByIDParams{
+			DeletedAt: time.Now(),
+			Version:   pgtype.Int4{Int32: *req.Version, Valid: true},
+		}
+	}
+	return nil
+}
+
+// decodeBatchAlert reads and validates the alert of an operation, naming the
+// fields it reports relative to the operation.
+func decodeBatchAlert(data json.RawMessage, alert any) []ErrorMsg {
+	err := json.Unmarshal(data, alert)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(alert)
+	}
+	if err == nil {
+		return nil
+	}
+
+	errs := bindErrors(err)
+	for i := range errs {
+		errs[i].Field = "alert." + errs[i].Field
+	}
+	return errs
+}
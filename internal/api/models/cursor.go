@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const DefaultListLimit int32 = 25
+
+// pageCursor is the keyset position of the last row on a page. It is handed
+// to clients as an opaque base64 token.
+type pageCursor struct {
+	Time time.Time `json:"t"`
+	ID   int32     `json:"i"`
+}
+
+func (cur pageCursor) encode() string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cur pageCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &cur)
+	return
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+// EscalationPolicySpec is the part of an escalation policy a client controls.
+// A policy without matchers or severities applies to every alert.
+type EscalationPolicySpec struct {
+	Name       string               `json:"name" binding:"required,max=255"`
+	Matchers   []string             `json:"matchers" binding:"omitempty,max=32"`
+	Severities []string             `json:"severities" binding:"omitempty,dive,oneof=critical high warning info"`
+	Steps      []EscalationStepSpec `json:"steps" binding:"required,min=1,max=20,dive"`
+}
+
+// EscalationStepSpec notifies Target, then waits WaitMinutes before moving on
+// to the next step. The wait of the last step is not used. A target of the
+// form schedule:<externalId> pages whoever is on call for that schedule.
+type EscalationStepSpec struct {
+	Target      string `json:"target" binding:"required,max=255"`
+	WaitMinutes int32  `json:"waitMinutes" binding:"gte=0,lte=10080"`
+}
+
+type CreateEscalationPolicyReq struct {
+	EscalationPolicySpec
+}
+
+type UpdateEscalationPolicyReq struct {
+	EscalationPolicySpec
+}
+
+type EscalationPolicyRes struct {
+	ExternalID uuid.UUID            `json:"externalId"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	UpdatedAt  time.Time            `json:"updatedAt"`
+	Name       string               `json:"name"`
+	Matchers   []string             `json:"matchers"`
+	Severities []string             `json:"severities"`
+	Steps      []EscalationStepSpec `json:"steps"`
+}
+
+func (req *CreateEscalationPolicyReq) Bind(c *gin.Context, p *domain.CreateEscalationPolicyParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	matchers, steps, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+	p.Name = req.Name
+	p.Matchers = matchers
+	p.Severities = req.Severities
+	p.Steps = steps
+	return nil
+}
+
+func (req *UpdateEscalationPolicyReq) Bind(c *gin.Context, p *domain.UpdateEscalationPolicyByIDParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	matchers, steps, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.UpdatedAt = time.Now()
+	p.Name = req.Name
+	p.Matchers = matchers
+	p.Severities = req.Severities
+	p.Steps = steps
+	return nil
+}
+
+// bind parses the matchers and returns the matchers and steps encoded for
+// storage.
+func (spec *EscalationPolicySpec) bind(c *gin.Context) ([]byte, []byte, error) {
+	if spec.Severities == nil {
+		spec.Severities = []string{}
+	}
+
+	ms, err := labels.ParseMatchers(spec.Matchers)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"matchers", err.Error()}}})
+		return nil, nil, err
+	}
+
+	steps := make([]db.EscalationStep, len(spec.Steps))
+	for i, step := range spec.Steps {
+		steps[i] = db.EscalationStep{Target: step.Target, WaitMinutes: step.WaitMinutes}
+	}
+
+	matchers, err := json.Marshal(ms)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return nil, nil, err
+	}
+	return matchers, data, nil
+}
+
+func NewEscalationPolicyResponse(policy *domain.EscalationPolicy) *EscalationPolicyRes {
+	resp := new(EscalationPolicyRes)
+	resp.ExternalID = policy.ExternalID
+	resp.CreatedAt = policy.CreatedAt
+	resp.UpdatedAt = policy.UpdatedAt
+	resp.Name = policy.Name
+	resp.Severities = policy.Severities
+
+	resp.Matchers = []string{}
+	if ms, err := db.EscalationPolicyMatchers(policy); err == nil {
+		for _, m := range ms {
+			resp.Matchers = append(resp.Matchers, m.String())
+		}
+	}
+
+	resp.Steps = []EscalationStepSpec{}
+	if steps, err := db.EscalationSteps(policy); err == nil {
+		for _, step := range steps {
+			resp.Steps = append(resp.Steps, EscalationStepSpec{Target: step.Target, WaitMinutes: step.WaitMinutes})
+		}
+	}
+	return resp
+}
+
+func NewEscalationPolicyListResponse(policies []*domain.EscalationPolicy) []*EscalationPolicyRes {
+	resp := make([]*EscalationPolicyRes, 0, len(policies))
+	for _, policy := range policies {
+		resp = append(resp, NewEscalationPolicyResponse(policy))
+	}
+	return resp
+}
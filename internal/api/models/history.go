@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// AlertHistoryRes is one change to an alert. Before is absent for the change
+// that created the alert and After for the one that deleted it.
+type AlertHistoryRes struct {
+	At     time.Time `json:"at"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	Before *AlertRes `json:"before,omitempty"`
+	After  *AlertRes `json:"after,omitempty"`
+}
+
+func NewAlertHistoryResponse(entry *domain.AlertHistory) (*AlertHistoryRes, error) {
+	before, after, err := db.HistoryAlerts(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(AlertHistoryRes)
+	resp.At = entry.CreatedAt
+	resp.Actor = entry.Actor
+	resp.Action = entry.Action
+	if before != nil {
+		resp.Before = NewAlertResponse(before)
+	}
+	if after != nil {
+		resp.After = NewAlertResponse(after)
+	}
+	return resp, nil
+}
+
+func NewAlertHistoryListResponse(entries []*domain.AlertHistory) ([]*AlertHistoryRes, error) {
+	resp := make([]*AlertHistoryRes, 0, len(entries))
+	for _, entry := range entries {
+		r, err := NewAlertHistoryResponse(entry)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, r)
+	}
+	return resp, nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const defaultIncidentPageSize = 50
+
+type ListIncidentsReq struct {
+	Status string `json:"status" form:"status" binding:"omitempty,oneof=open resolved"`
+	Limit  int32  `json:"limit" form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+type ResolveIncidentReq struct {
+	Note string `json:"note" binding:"max=1024"`
+}
+
+type IncidentRes struct {
+	ExternalID  uuid.UUID                   `json:"externalId"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	UpdatedAt   time.Time                   `json:"updatedAt"`
+	Status      string                      `json:"status"`
+	GroupLabels map[string]string           `json:"groupLabels"`
+	LastAlertAt time.Time                   `json:"lastAlertAt"`
+	Resolution  *AlertActionRes             `json:"resolution,omitempty"`
+	Alerts      []uuid.UUID                 `json:"alerts,omitempty"`
+	Timeline    []*IncidentTimelineEntryRes `json:"timeline,omitempty"`
+}
+
+type IncidentTimelineEntryRes struct {
+	At    time.Time  `json:"at"`
+	Type  string     `json:"type"`
+	Alert *uuid.UUID `json:"alert,omitempty"`
+	Actor string     `json:"actor,omitempty"`
+	Note  string     `json:"note,omitempty"`
+}
+
+func (req *ListIncidentsReq) Bind(c *gin.Context, p *domain.ListIncidentsParams) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.Status = text(req.Status)
+	p.PageSize = req.Limit
+	if p.PageSize == 0 {
+		p.PageSize = defaultIncidentPageSize
+	}
+	return nil
+}
+
+// Bind reads the optional note. An empty body is allowed; the actor is filled
+// in by the handler.
+func (req *ResolveIncidentReq) Bind(c *gin.Context, p *domain.ResolveIncidentByIDParams, note *pgtype.Text) error {
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.ResolvedAt = time.Now()
+	*note = text(req.Note)
+	return nil
+}
+
+func NewIncidentResponse(incident *domain.Incident) *IncidentRes {
+	resp := new(IncidentRes)
+	resp.ExternalID = incident.ExternalID
+	resp.CreatedAt = incident.CreatedAt
+	resp.UpdatedAt = incident.UpdatedAt
+	resp.Status = incident.Status
+	resp.GroupLabels = decodeMap(incident.GroupLabels)
+	resp.LastAlertAt = incident.LastAlertAt
+	if incident.ResolvedAt.Valid {
+		resp.Resolution = &AlertActionRes{
+			At: incident.ResolvedAt.Time,
+			By: incident.ResolvedBy.String,
+		}
+	}
+	return resp
+}
+
+// NewIncidentDetailResponse is NewIncidentResponse with the member alerts and
+// the timeline of the incident.
+func NewIncidentDetailResponse(
+	incident *domain.Incident,
+	alerts []uuid.UUID,
+	timeline []*domain.ListIncidentTimelineRow,
+) *IncidentRes {
+	resp := NewIncidentResponse(incident)
+	resp.Alerts = alerts
+	resp.Timeline = make([]*IncidentTimelineEntryRes, 0, len(timeline))
+	for _, entry := range timeline {
+		e := &IncidentTimelineEntryRes{
+			At:    entry.CreatedAt,
+			Type:  entry.EntryType,
+			Actor: entry.Actor.String,
+			Note:  entry.Note.String,
+		}
+		if entry.AlertExternalID.Valid {
+			id := uuid.UUID(entry.AlertExternalID.Bytes)
+			e.Alert = &id
+		}
+		resp.Timeline = append(resp.Timeline, e)
+	}
+	return resp
+}
+
+func NewIncidentListResponse(incidents []*domain.Incident) []*IncidentRes {
+	resp := make([]*IncidentRes, 0, len(incidents))
+	for _, incident := range incidents {
+		resp = append(resp, NewIncidentResponse(incident))
+	}
+	return resp
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func timestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+// text maps an optional string to a nullable query parameter, treating the
+// empty string as absent.
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes the pattern characters of an ilike operand so message
+// filters match literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// jsonb encodes a map for a jsonb parameter. A nil map stays nil so that it
+// is passed as SQL null.
+func jsonb(m map[string]string) []byte {
+	if m == nil {
+		return nil
+	}
+	data, _ := json.Marshal(m)
+	return data
+}
+
+// jsonObject is jsonb for not null columns, encoding a nil map as {}.
+func jsonObject(m map[string]string) []byte {
+	if m == nil {
+		return []byte("{}")
+	}
+	return jsonb(m)
+}
+
+func decodeMap(data []byte) map[string]string {
+	m := make(map[string]string)
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &m)
+	}
+	return m
+}
@@ -0,0 +1,250 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+var errInvalidScheduleOverride = errors.New("invalid schedule override")
+
+// ScheduleSpec is the part of a schedule a client controls. Layers are listed
+// from lowest to highest precedence.
+type ScheduleSpec struct {
+	Name     string              `json:"name" binding:"required,max=255"`
+	TimeZone string              `json:"timeZone" binding:"required,timezone"`
+	Layers   []ScheduleLayerSpec `json:"layers" binding:"required,min=1,max=10,dive"`
+}
+
+// ScheduleLayerSpec rotates through Users, handing off daily or weekly at
+// HandoffTime from the Start date, both in the time zone of the schedule.
+type ScheduleLayerSpec struct {
+	Name        string   `json:"name" binding:"required,max=255"`
+	Rotation    string   `json:"rotation" binding:"required,oneof=daily weekly"`
+	Start       string   `json:"start" binding:"required,datetime=2006-01-02"`
+	HandoffTime string   `json:"handoffTime" binding:"required,datetime=15:04"`
+	Users       []string `json:"users" binding:"required,min=1,max=100,dive,required,max=255"`
+}
+
+type CreateScheduleReq struct {
+	ScheduleSpec
+}
+
+type UpdateScheduleReq struct {
+	ScheduleSpec
+}
+
+type CreateScheduleOverrideReq struct {
+	User     string     `json:"user" binding:"required,max=255"`
+	StartsAt *time.Time `json:"startsAt"`
+	EndsAt   time.Time  `json:"endsAt" binding:"required"`
+}
+
+type GetOnCallReq struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type ScheduleRes struct {
+	ExternalID uuid.UUID           `json:"externalId"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	UpdatedAt  time.Time           `json:"updatedAt"`
+	Name       string              `json:"name"`
+	TimeZone   string              `json:"timeZone"`
+	Layers     []ScheduleLayerSpec `json:"layers"`
+}
+
+type ScheduleOverrideRes struct {
+	ExternalID uuid.UUID `json:"externalId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	StartsAt   time.Time `json:"startsAt"`
+	EndsAt     time.Time `json:"endsAt"`
+	User       string    `json:"user"`
+	CreatedBy  string    `json:"createdBy"`
+}
+
+// OnCallRes names the user on call at a point in time and the layer shift or
+// override that put them there, which lasts from Start until End.
+type OnCallRes struct {
+	Schedule uuid.UUID  `json:"schedule"`
+	At       time.Time  `json:"at"`
+	User     string     `json:"user"`
+	Layer    string     `json:"layer,omitempty"`
+	Override *uuid.UUID `json:"override,omitempty"`
+	Start    time.Time  `json:"start"`
+	End      time.Time  `json:"end"`
+}
+
+func (req *CreateScheduleReq) Bind(c *gin.Context, p *domain.CreateScheduleParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	layers, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+	p.Name = req.Name
+	p.TimeZone = req.TimeZone
+	p.Layers = layers
+	return nil
+}
+
+func (req *UpdateScheduleReq) Bind(c *gin.Context, p *domain.UpdateScheduleByIDParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	layers, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.UpdatedAt = time.Now()
+	p.Name = req.Name
+	p.TimeZone = req.TimeZone
+	p.Layers = layers
+	return nil
+}
+
+// bind returns the layers encoded for storage.
+func (spec *ScheduleSpec) bind(c *gin.Context) ([]byte, error) {
+	layers := make([]db.ScheduleLayer, len(spec.Layers))
+	for i, layer := range spec.Layers {
+		layers[i] = db.ScheduleLayer{
+			Name:        layer.Name,
+			Rotation:    layer.Rotation,
+			Start:       layer.Start,
+			HandoffTime: layer.HandoffTime,
+			Users:       layer.Users,
+		}
+	}
+
+	data, err := json.Marshal(layers)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"layers", err.Error()}}})
+		return nil, err
+	}
+	return data, nil
+}
+
+// Bind defaults StartsAt to now; the schedule and actor are filled in by the
+// handler.
+func (req *CreateScheduleOverrideReq) Bind(c *gin.Context, p *domain.CreateScheduleOverrideParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	if req.StartsAt == nil {
+		now := time.Now()
+		req.StartsAt = &now
+	}
+
+	var out []ErrorMsg
+	if !req.EndsAt.After(*req.StartsAt) {
+		out = append(out, ErrorMsg{"endsAt", "should be after startsAt"})
+	} else if !req.EndsAt.After(time.Now()) {
+		out = append(out, ErrorMsg{"endsAt", "should be in the future"})
+	}
+	if len(out) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return errInvalidScheduleOverride
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = time.Now()
+	p.StartsAt = *req.StartsAt
+	p.EndsAt = req.EndsAt
+	p.UserName = req.User
+	return nil
+}
+
+// Bind reads the time to resolve, which defaults to now.
+func (req *GetOnCallReq) Bind(c *gin.Context) (time.Time, error) {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return time.Time{}, err
+	}
+
+	if req.At.IsZero() {
+		return time.Now(), nil
+	}
+	return req.At, nil
+}
+
+func NewScheduleResponse(schedule *domain.Schedule) *ScheduleRes {
+	resp := new(ScheduleRes)
+	resp.ExternalID = schedule.ExternalID
+	resp.CreatedAt = schedule.CreatedAt
+	resp.UpdatedAt = schedule.UpdatedAt
+	resp.Name = schedule.Name
+	resp.TimeZone = schedule.TimeZone
+
+	resp.Layers = []ScheduleLayerSpec{}
+	if layers, err := db.ScheduleLayers(schedule); err == nil {
+		for _, layer := range layers {
+			resp.Layers = append(resp.Layers, ScheduleLayerSpec{
+				Name:        layer.Name,
+				Rotation:    layer.Rotation,
+				Start:       layer.Start,
+				HandoffTime: layer.HandoffTime,
+				Users:       layer.Users,
+			})
+		}
+	}
+	return resp
+}
+
+func NewScheduleListResponse(schedules []*domain.Schedule) []*ScheduleRes {
+	resp := make([]*ScheduleRes, 0, len(schedules))
+	for _, schedule := range schedules {
+		resp = append(resp, NewScheduleResponse(schedule))
+	}
+	return resp
+}
+
+func NewScheduleOverrideResponse(override *domain.ScheduleOverride) *ScheduleOverrideRes {
+	resp := new(ScheduleOverrideRes)
+	resp.ExternalID = override.ExternalID
+	resp.CreatedAt = override.CreatedAt
+	resp.StartsAt = override.StartsAt
+	resp.EndsAt = override.EndsAt
+	resp.User = override.UserName
+	resp.CreatedBy = override.CreatedBy
+	return resp
+}
+
+func NewScheduleOverrideListResponse(overrides []*domain.ScheduleOverride) []*ScheduleOverrideRes {
+	resp := make([]*ScheduleOverrideRes, 0, len(overrides))
+	for _, override := range overrides {
+		resp = append(resp, NewScheduleOverrideResponse(override))
+	}
+	return resp
+}
+
+func NewOnCallResponse(schedule *domain.Schedule, at time.Time, onCall *db.OnCall) *OnCallRes {
+	resp := new(OnCallRes)
+	resp.Schedule = schedule.ExternalID
+	resp.At = at
+	resp.User = onCall.User
+	resp.Layer = onCall.Layer
+	resp.Start = onCall.Start
+	resp.End = onCall.End
+	if onCall.Override != nil {
+		resp.Override = &onCall.Override.ExternalID
+	}
+	return resp
+}
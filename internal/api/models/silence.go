@@ -0,0 +1,186 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+const (
+	SilencePending = "pending"
+	SilenceActive  = "active"
+	SilenceExpired = "expired"
+)
+
+var errInvalidSilence = errors.New("invalid silence")
+
+// SilenceSpec is the part of a silence a client controls. A silence must have
+// at least one matcher or severity so that it cannot silence everything.
+type SilenceSpec struct {
+	StartsAt   *time.Time `json:"startsAt"`
+	EndsAt     time.Time  `json:"endsAt" binding:"required"`
+	Matchers   []string   `json:"matchers" binding:"omitempty,max=32"`
+	Severities []string   `json:"severities" binding:"omitempty,dive,oneof=critical high warning info"`
+	Comment    string     `json:"comment" binding:"required,max=1024"`
+}
+
+type CreateSilenceReq struct {
+	SilenceSpec
+}
+
+type UpdateSilenceReq struct {
+	SilenceSpec
+}
+
+type ListSilencesReq struct {
+	State string `json:"state" form:"state" binding:"omitempty,oneof=pending active expired"`
+}
+
+type SilenceRes struct {
+	ExternalID uuid.UUID `json:"externalId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	StartsAt   time.Time `json:"startsAt"`
+	EndsAt     time.Time `json:"endsAt"`
+	Matchers   []string  `json:"matchers"`
+	Severities []string  `json:"severities"`
+	CreatedBy  string    `json:"createdBy"`
+	Comment    string    `json:"comment"`
+	State      string    `json:"state"`
+}
+
+func (req *CreateSilenceReq) Bind(c *gin.Context, p *domain.CreateSilenceParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	matchers, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+	p.StartsAt = *req.StartsAt
+	p.EndsAt = req.EndsAt
+	p.Matchers = matchers
+	p.Severities = req.Severities
+	p.Comment = req.Comment
+	return nil
+}
+
+func (req *UpdateSilenceReq) Bind(c *gin.Context, p *domain.UpdateSilenceByIDParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	matchers, err := req.bind(c)
+	if err != nil {
+		return err
+	}
+
+	p.UpdatedAt = time.Now()
+	p.StartsAt = *req.StartsAt
+	p.EndsAt = req.EndsAt
+	p.Matchers = matchers
+	p.Severities = req.Severities
+	p.Comment = req.Comment
+	return nil
+}
+
+// bind applies the checks the validator cannot express, defaults StartsAt to
+// now and returns the matchers encoded for storage.
+func (spec *SilenceSpec) bind(c *gin.Context) ([]byte, error) {
+	if spec.StartsAt == nil {
+		now := time.Now()
+		spec.StartsAt = &now
+	}
+	if spec.Severities == nil {
+		spec.Severities = []string{}
+	}
+
+	var out []ErrorMsg
+	ms, err := labels.ParseMatchers(spec.Matchers)
+	if err != nil {
+		out = append(out, ErrorMsg{"matchers", err.Error()})
+	}
+	if len(spec.Matchers) == 0 && len(spec.Severities) == 0 {
+		out = append(out, ErrorMsg{"matchers", "at least one matcher or severity is required"})
+	}
+	if !spec.EndsAt.After(*spec.StartsAt) {
+		out = append(out, ErrorMsg{"endsAt", "should be after startsAt"})
+	} else if !spec.EndsAt.After(time.Now()) {
+		out = append(out, ErrorMsg{"endsAt", "should be in the future"})
+	}
+	if len(out) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return nil, errInvalidSilence
+	}
+
+	data, err := json.Marshal(ms)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"matchers", err.Error()}}})
+		return nil, err
+	}
+	return data, nil
+}
+
+func (req *ListSilencesReq) Bind(c *gin.Context, p *domain.ListSilencesParams) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.State = text(req.State)
+	p.Now = time.Now()
+	return nil
+}
+
+func NewSilenceResponse(silence *domain.Silence) *SilenceRes {
+	resp := new(SilenceRes)
+	resp.ExternalID = silence.ExternalID
+	resp.CreatedAt = silence.CreatedAt
+	resp.UpdatedAt = silence.UpdatedAt
+	resp.StartsAt = silence.StartsAt
+	resp.EndsAt = silence.EndsAt
+	resp.Severities = silence.Severities
+	resp.CreatedBy = silence.CreatedBy
+	resp.Comment = silence.Comment
+
+	resp.Matchers = []string{}
+	if ms, err := db.SilenceMatchers(silence); err == nil {
+		for _, m := range ms {
+			resp.Matchers = append(resp.Matchers, m.String())
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case now.Before(silence.StartsAt):
+		resp.State = SilencePending
+	case now.Before(silence.EndsAt):
+		resp.State = SilenceActive
+	default:
+		resp.State = SilenceExpired
+	}
+	return resp
+}
+
+func NewSilenceListResponse(silences []*domain.Silence) []*SilenceRes {
+	resp := make([]*SilenceRes, 0, len(silences))
+	for _, silence := range silences {
+		resp = append(resp, NewSilenceResponse(silence))
+	}
+	return resp
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+// LastEventIDHeader is sent by EventSource clients when they reconnect.
+const LastEventIDHeader = "Last-Event-ID"
+
+type StreamAlertsReq struct {
+	Severity []string `json:"severity" form:"severity" binding:"omitempty,dive,oneof=critical high warning info"`
+	Label    []string `json:"label" form:"label"`
+	// LastEventID is for clients that cannot set the Last-Event-ID header on
+	// their first connection. The header wins if both are given.
+	LastEventID string `json:"lastEventId" form:"lastEventId"`
+}
+
+// AlertStreamFilter selects the events sent to a stream client.
+type AlertStreamFilter struct {
+	Severities map[string]bool
+	Matchers   labels.Matchers
+	// Resume is set when the client supplied the sequence of the last event
+	// it received, in LastEventID.
+	Resume      bool
+	LastEventID int64
+}
+
+type AlertEventRes struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Alert      *AlertRes `json:"alert"`
+	// Detail is set for event types that carry more than the alert, such as
+	// the step of an alert.escalated event.
+	Detail json.RawMessage `json:"detail,omitempty"`
+}
+
+func (req *StreamAlertsReq) Bind(c *gin.Context, f *AlertStreamFilter) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	if len(req.Severity) > 0 {
+		f.Severities = make(map[string]bool, len(req.Severity))
+		for _, severity := range req.Severity {
+			f.Severities[severity] = true
+		}
+	}
+
+	ms, err := labels.ParseMatchers(req.Label)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"label", err.Error()}}})
+		return err
+	}
+	f.Matchers = ms
+
+	lastEventID := c.GetHeader(LastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = req.LastEventID
+	}
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 63)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"lastEventId", "must be an event id from this stream"}}})
+			return err
+		}
+		f.Resume = true
+		f.LastEventID = int64(id)
+	}
+	return nil
+}
+
+func (f *AlertStreamFilter) Matches(alert *domain.Alert) bool {
+	if f.Severities != nil && !f.Severities[alert.Severity] {
+		return false
+	}
+	return f.Matchers.Matches(decodeMap(alert.Labels))
+}
+
+func NewAlertEventResponse(event *domain.AlertEvent, alert *domain.Alert) *AlertEventRes {
+	return &AlertEventRes{
+		ID:         event.ExternalID,
+		Type:       event.EventType,
+		OccurredAt: event.CreatedAt,
+		Alert:      NewAlertResponse(alert),
+		Detail:     event.Detail,
+	}
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+type CreateWebhookSubscriptionReq struct {
+	URL        string   `json:"url" binding:"required,http_url,max=2048"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,oneof=alert.created alert.updated alert.deleted alert.restored alert.acknowledged alert.resolved alert.escalated"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=256"`
+}
+
+type WebhookSubscriptionRes struct {
+	ExternalID uuid.UUID `json:"externalId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+}
+
+type ListWebhookDeliveriesReq struct {
+	Status string `json:"status" form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	Limit  int32  `json:"limit" form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor string `json:"cursor" form:"cursor"`
+}
+
+type WebhookDeliveryRes struct {
+	ExternalID     uuid.UUID       `json:"externalId"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time      `json:"lastAttemptAt,omitempty"`
+	ResponseStatus *int32          `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+type ListWebhookDeliveriesRes struct {
+	Deliveries []*WebhookDeliveryRes `json:"deliveries"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+// Bind generates a signing secret when the client does not supply one. The
+// secret is only ever returned in the response to this request.
+func (req *CreateWebhookSubscriptionReq) Bind(c *gin.Context, p *domain.CreateWebhookSubscriptionParams) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	p.ExternalID = uuid.Must(uuid.NewV4())
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+	p.Url = req.URL
+	p.EventTypes = req.EventTypes
+	p.Secret = req.Secret
+	if p.Secret == "" {
+		p.Secret = newSecret()
+	}
+	return nil
+}
+
+func (req *ListWebhookDeliveriesReq) Bind(c *gin.Context, p *domain.ListWebhookDeliveriesBySubscriptionIDParams) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		abortWithBindError(c, err)
+		return err
+	}
+
+	if req.Limit == 0 {
+		req.Limit = DefaultListLimit
+	}
+
+	p.Status = text(req.Status)
+	p.PageSize = req.Limit + 1
+
+	if req.Cursor != "" {
+		cur, err := decodeCursor(req.Cursor)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []ErrorMsg{{"cursor", "invalid cursor"}}})
+			return err
+		}
+		p.BeforeID = pgtype.Int4{Int32: cur.ID, Valid: true}
+	}
+	return nil
+}
+
+func NewWebhookSubscriptionResponse(subscription *domain.WebhookSubscription, withSecret bool) *WebhookSubscriptionRes {
+	resp := new(WebhookSubscriptionRes)
+	resp.ExternalID = subscription.ExternalID
+	resp.CreatedAt = subscription.CreatedAt
+	resp.UpdatedAt = subscription.UpdatedAt
+	resp.URL = subscription.Url
+	resp.EventTypes = subscription.EventTypes
+	resp.Active = subscription.Active
+	if withSecret {
+		resp.Secret = subscription.Secret
+	}
+	return resp
+}
+
+func NewWebhookSubscriptionListResponse(subscriptions []*domain.WebhookSubscription) []*WebhookSubscriptionRes {
+	resp := make([]*WebhookSubscriptionRes, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		resp = append(resp, NewWebhookSubscriptionResponse(subscription, false))
+	}
+	return resp
+}
+
+func NewListWebhookDeliveriesResponse(deliveries []*domain.WebhookDelivery, p domain.ListWebhookDeliveriesBySubscriptionIDParams) *ListWebhookDeliveriesRes {
+	resp := new(ListWebhookDeliveriesRes)
+	resp.Deliveries = make([]*WebhookDeliveryRes, 0, len(deliveries))
+
+	limit := int(p.PageSize - 1)
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		last := deliveries[len(deliveries)-1]
+		resp.NextCursor = pageCursor{ID: last.ID, Time: last.CreatedAt}.encode()
+	}
+
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, NewWebhookDeliveryResponse(delivery))
+	}
+	return resp
+}
+
+func NewWebhookDeliveryResponse(delivery *domain.WebhookDelivery) *WebhookDeliveryRes {
+	resp := new(WebhookDeliveryRes)
+	resp.ExternalID = delivery.ExternalID
+	resp.EventType = delivery.EventType
+	resp.Status = delivery.Status
+	resp.Attempts = delivery.Attempts
+	if delivery.Status == db.DeliveryPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	resp.LastAttemptAt = timePtr(delivery.LastAttemptAt)
+	if delivery.ResponseStatus.Valid {
+		resp.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+	resp.LastError = delivery.LastError.String
+	resp.CreatedAt = delivery.CreatedAt
+	resp.DeliveredAt = timePtr(delivery.DeliveredAt)
+	resp.Payload = delivery.Payload
+	return resp
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+// TestOrgIsolation checks that every route hands the organization of the
+// principal, not the default one, to the store.
+func TestOrgIsolation(t *testing.T) {
+	const orgID int32 = 2
+	externalID := uuid.Must(uuid.NewV4())
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        string
+		buildStubs func(store *mockdb.MockStore)
+		wantCode   int
+	}{
+		{
+			name: "list alerts",
+			url:  "/alert",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAlertsMatching(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(domain.ListAlertsParams).OrgID == orgID
+					}), gomock.Any()).
+					Times(1).
+					Return([]*domain.Alert{}, nil, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get alert",
+			url:  fmt.Sprintf("/alert/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAlertByExternalID(gomock.Any(), gomock.Eq(domain.GetAlertByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrAlertNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:   "batch",
+			method: http.MethodPost,
+			url:    "/alert/batch",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyAlertBatchTX(gomock.Any(), gomock.Eq(orgID), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.AlertBatchResult{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "list incidents",
+			url:  "/incidents",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListIncidents(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(domain.ListIncidentsParams).OrgID == orgID
+					})).
+					Times(1).
+					Return([]*domain.Incident{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get incident",
+			url:  fmt.Sprintf("/incidents/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIncidentByExternalID(gomock.Any(), gomock.Eq(domain.GetIncidentByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrIncidentNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "list silences",
+			url:  "/silences",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListSilences(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(domain.ListSilencesParams).OrgID == orgID
+					})).
+					Times(1).
+					Return([]*domain.Silence{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get silence",
+			url:  fmt.Sprintf("/silences/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSilenceByExternalID(gomock.Any(), gomock.Eq(domain.GetSilenceByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrSilenceNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "list escalation policies",
+			url:  "/escalation-policies",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEscalationPolicies(gomock.Any(), gomock.Eq(orgID)).
+					Times(1).
+					Return([]*domain.EscalationPolicy{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get escalation policy",
+			url:  fmt.Sprintf("/escalation-policies/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEscalationPolicyByExternalID(gomock.Any(), gomock.Eq(domain.GetEscalationPolicyByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrEscalationPolicyNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "list schedules",
+			url:  "/schedules",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListSchedules(gomock.Any(), gomock.Eq(orgID)).
+					Times(1).
+					Return([]*domain.Schedule{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get schedule",
+			url:  fmt.Sprintf("/schedules/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Eq(domain.GetScheduleByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrScheduleNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "list webhook subscriptions",
+			url:  "/webhooks",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListWebhookSubscriptions(gomock.Any(), gomock.Eq(orgID)).
+					Times(1).
+					Return([]*domain.WebhookSubscription{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "get webhook subscription",
+			url:  fmt.Sprintf("/webhooks/%s", externalID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Eq(domain.GetWebhookSubscriptionByExternalIDParams{OrgID: orgID, ExternalID: externalID})).
+					Times(1).
+					Return(nil, db.ErrWebhookSubscriptionNotExists)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "list api keys",
+			url:  "/api-keys",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAPIKeys(gomock.Any(), gomock.Eq(orgID)).
+					Times(1).
+					Return([]*domain.ApiKey{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			key := randomAPIKey(t, ScopeAdmin)
+			key.row.OrgID = orgID
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).Times(1).Return(key.row, nil)
+			store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			method, body := testCase.method, []byte(nil)
+			if method == "" {
+				method = http.MethodGet
+			} else {
+				var err error
+				body, err = json.Marshal(gin.H{"operations": []gin.H{{"op": "create", "alert": gin.H{"message": "disk is full"}}}})
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(method, testCase.url, bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer "+key.token)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, testCase.wantCode, recorder.Code, recorder.Body.String())
+		})
+	}
+}
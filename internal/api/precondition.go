@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+var errPreconditionFailed = errors.New("alert has been changed since it was read")
+
+// alertETag is the entity tag of an alert, a strong tag made of its version.
+func alertETag(alert *domain.Alert) string {
+	return strconv.Quote(strconv.Itoa(int(alert.Version)))
+}
+
+// setAlertETag sets the ETag header for a response carrying the alert.
+func setAlertETag(c *gin.Context, alert *domain.Alert) {
+	c.Header("ETag", alertETag(alert))
+}
+
+// ifMatch checks the If-Match header of a write to the alert and returns the
+// version the write must apply to. A wildcard matches any version and yields
+// an invalid version. It writes 428 when the header is missing and 412 when
+// it does not match the alert, returning false.
+func (s *Server) ifMatch(c *gin.Context, alert *domain.Alert) (pgtype.Int4, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		s.logger.Warn("missing If-Match header, returning 428")
+		c.JSON(http.StatusPreconditionRequired, NewError(errors.New("the If-Match header is required")))
+		return pgtype.Int4{}, false
+	}
+
+	current := alertETag(alert)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return pgtype.Int4{}, true
+		}
+		// weak tags never match under the strong comparison If-Match uses
+		if tag == current {
+			return pgtype.Int4{Int32: alert.Version, Valid: true}, true
+		}
+	}
+
+	s.logger.Warn("stale If-Match header, returning 412")
+	c.JSON(http.StatusPreconditionFailed, NewError(errPreconditionFailed))
+	return pgtype.Int4{}, false
+}
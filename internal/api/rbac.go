@@ -0,0 +1,32 @@
+package api
+
+import "fmt"
+
+// Roles are granted to users in the config, the api_user table or the claims
+// of a JWT. Viewers read, responders also create and work alerts, and admins
+// may do anything, including deleting alerts and managing API keys.
+const (
+	RoleViewer    = "viewer"
+	RoleResponder = "responder"
+	RoleAdmin     = "admin"
+)
+
+var roleScopes = map[string][]string{
+	RoleViewer:    {ScopeAlertsRead},
+	RoleResponder: {ScopeAlertsRead, ScopeAlertsWrite},
+	RoleAdmin:     {ScopeAlertsRead, ScopeAlertsWrite, ScopeAlertsDelete, ScopeAdmin},
+}
+
+// userPrincipal is the principal of a user with role. Every user must have
+// one: a config still using the admin flag that roles replaced would
+// otherwise quietly leave its users with less access than they had.
+func userPrincipal(username, role string) (*Principal, error) {
+	if role == "" {
+		return nil, fmt.Errorf("user %q has no role; set role to viewer, responder or admin in place of the removed admin flag", username)
+	}
+	scopes, ok := roleScopes[role]
+	if !ok {
+		return nil, fmt.Errorf("user %q has unknown role %q", username, role)
+	}
+	return &Principal{Username: username, Scopes: scopes, Roles: []string{role}}, nil
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	conf "github.com/josephlbailey/alert-service/config"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+	common "github.com/josephlbailey/alert-service/internal/pkg/config"
+	"github.com/josephlbailey/alert-service/internal/pkg/password"
+)
+
+func TestRoles(t *testing.T) {
+	hash, err := password.HashBcrypt("viewerPassword", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		auth          []string
+		keyScopes     []string
+		anonymousRead bool
+		code          int
+	}{
+		{
+			name:   "viewer reads alerts",
+			method: http.MethodGet,
+			url:    "/alert/invalid",
+			auth:   []string{"viewerUser", "viewerPassword"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "viewer cannot create alerts",
+			method: http.MethodPost,
+			url:    "/alert",
+			auth:   []string{"viewerUser", "viewerPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "viewer cannot acknowledge alerts",
+			method: http.MethodPost,
+			url:    "/alert/invalid/ack",
+			auth:   []string{"viewerUser", "viewerPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "responder acknowledges alerts",
+			method: http.MethodPost,
+			url:    "/alert/invalid/ack",
+			auth:   []string{"integrationUser", "integrationUserPassword"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "responder cannot delete alerts",
+			method: http.MethodDelete,
+			url:    "/alert/invalid",
+			auth:   []string{"integrationUser", "integrationUserPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "responder cannot restore alerts",
+			method: http.MethodPost,
+			url:    "/alert/invalid/restore",
+			auth:   []string{"integrationUser", "integrationUserPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "admin deletes alerts",
+			method: http.MethodDelete,
+			url:    "/alert/invalid",
+			auth:   []string{"adminServiceUser", "adminServicePassword"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "responder cannot create webhook subscriptions",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			auth:   []string{"integrationUser", "integrationUserPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "responder cannot delete webhook subscriptions",
+			method: http.MethodDelete,
+			url:    "/webhooks/invalid",
+			auth:   []string{"integrationUser", "integrationUserPassword"},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "admin creates webhook subscriptions",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			auth:   []string{"adminServiceUser", "adminServicePassword"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "admin deletes webhook subscriptions",
+			method: http.MethodDelete,
+			url:    "/webhooks/invalid",
+			auth:   []string{"adminServiceUser", "adminServicePassword"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "anonymous cannot read alerts",
+			method: http.MethodGet,
+			url:    "/alert/invalid",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:          "anonymous reads alerts when allowed",
+			method:        http.MethodGet,
+			url:           "/alert/invalid",
+			anonymousRead: true,
+			code:          http.StatusBadRequest,
+		},
+		{
+			name:          "anonymous cannot write alerts when reads are allowed",
+			method:        http.MethodPost,
+			url:           "/alert/invalid/ack",
+			anonymousRead: true,
+			code:          http.StatusUnauthorized,
+		},
+		{
+			name:          "credentials still need the read scope when reads are allowed",
+			method:        http.MethodGet,
+			url:           "/alert/invalid",
+			keyScopes:     []string{ScopeAlertsDelete},
+			anonymousRead: true,
+			code:          http.StatusForbidden,
+		},
+		{
+			name:          "api key reads alerts when reads are allowed",
+			method:        http.MethodGet,
+			url:           "/alert/invalid",
+			keyScopes:     []string{ScopeAlertsRead},
+			anonymousRead: true,
+			code:          http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			config := common.LoadConfig[conf.Config]("alert-service", "dev")
+			config.Users = append(config.Users, conf.BasicUser{Username: "viewerUser", PasswordHash: hash, Role: RoleViewer})
+			config.Auth.AnonymousRead = testCase.anonymousRead
+			server := NewServer(config, zap.NewNop(), store)
+			server.MountHandlers()
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(testCase.method, testCase.url, nil)
+			require.NoError(t, err)
+
+			if testCase.auth != nil {
+				request.SetBasicAuth(testCase.auth[0], testCase.auth[1])
+			}
+			if testCase.keyScopes != nil {
+				key := randomAPIKey(t, testCase.keyScopes...)
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.row.Prefix)).Times(1).Return(key.row, nil)
+				store.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				request.Header.Set("Authorization", "Bearer "+key.token)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, testCase.code, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func (s *Server) CreateSchedule(c *gin.Context) {
+	var (
+		req models.CreateScheduleReq
+		p   domain.CreateScheduleParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("creating schedule...", zap.String("name", p.Name))
+	schedule, err := s.store.CreateSchedule(c, p)
+	if err != nil {
+		s.logger.Error("error creating schedule entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("created schedule.", zap.String("externalId", schedule.ExternalID.String()))
+	c.JSON(http.StatusCreated, models.NewScheduleResponse(schedule))
+}
+
+func (s *Server) ListSchedules(c *gin.Context) {
+	s.logger.Info("listing schedules...")
+	schedules, err := s.store.ListSchedules(c, org(c))
+	if err != nil {
+		s.logger.Error("error listing schedules", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing schedules")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewScheduleListResponse(schedules))
+}
+
+func (s *Server) GetScheduleByExternalID(c *gin.Context) {
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewScheduleResponse(schedule))
+}
+
+func (s *Server) UpdateScheduleByExternalID(c *gin.Context) {
+	var (
+		req models.UpdateScheduleReq
+		p   domain.UpdateScheduleByIDParams
+	)
+
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = schedule.OrgID
+	p.ID = schedule.ID
+
+	s.logger.Info("updating schedule...", zap.String("externalID", schedule.ExternalID.String()))
+	schedule, err = s.store.UpdateScheduleByID(c, p)
+	if err != nil {
+		s.logger.Error("error updating schedule entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("updated schedule.", zap.String("externalId", schedule.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewScheduleResponse(schedule))
+}
+
+func (s *Server) DeleteScheduleByExternalID(c *gin.Context) {
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("deleting schedule...", zap.String("externalID", schedule.ExternalID.String()))
+	err := s.store.DeleteScheduleByID(c, domain.DeleteScheduleByIDParams{
+		OrgID: schedule.OrgID,
+		ID:    schedule.ID,
+	})
+	if err != nil {
+		s.logger.Error("error deleting schedule entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("deleted schedule.", zap.String("externalId", schedule.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewScheduleResponse(schedule))
+}
+
+func (s *Server) GetScheduleOnCall(c *gin.Context) {
+	var req models.GetOnCallReq
+
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	at, err := req.Bind(c)
+
+	if err != nil {
+		return
+	}
+
+	s.logger.Info("resolving on call...", zap.String("externalID", schedule.ExternalID.String()), zap.Time("at", at))
+	onCall, err := db.ScheduleOnCall(c, s.store, schedule, at)
+	if err != nil {
+		s.logger.Error("error resolving on call", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while resolving on call")))
+		return
+	}
+
+	if onCall == nil {
+		s.logger.Warn("nobody on call, returning 404")
+		c.JSON(http.StatusNotFound, NewError(errors.New("nobody is on call at the given time")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewOnCallResponse(schedule, at, onCall))
+}
+
+func (s *Server) CreateScheduleOverride(c *gin.Context) {
+	var (
+		req models.CreateScheduleOverrideReq
+		p   domain.CreateScheduleOverrideParams
+	)
+
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.ScheduleID = schedule.ID
+	p.CreatedBy = actor(c)
+	p.OrgID = schedule.OrgID
+
+	s.logger.Info("creating schedule override...", zap.String("schedule", schedule.ExternalID.String()), zap.String("user", p.UserName))
+	override, err := s.store.CreateScheduleOverride(c, p)
+	if err != nil {
+		s.logger.Error("error creating schedule override entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("created schedule override.", zap.String("externalId", override.ExternalID.String()))
+	c.JSON(http.StatusCreated, models.NewScheduleOverrideResponse(override))
+}
+
+// ListScheduleOverrides lists the overrides of a schedule that have not yet
+// ended.
+func (s *Server) ListScheduleOverrides(c *gin.Context) {
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("listing schedule overrides...", zap.String("schedule", schedule.ExternalID.String()))
+	overrides, err := s.store.ListScheduleOverrides(c, domain.ListScheduleOverridesParams{
+		ScheduleID: schedule.ID,
+		EndsAfter:  time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("error listing schedule overrides", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing schedule overrides")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewScheduleOverrideListResponse(overrides))
+}
+
+func (s *Server) DeleteScheduleOverride(c *gin.Context) {
+	schedule, ok := s.schedule(c)
+	if !ok {
+		return
+	}
+
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("overrideID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return
+	}
+
+	override, err := s.store.GetScheduleOverrideByExternalID(c, domain.GetScheduleOverrideByExternalIDParams{
+		ScheduleID: schedule.ID,
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrScheduleOverrideNotExists) {
+			s.logger.Warn("schedule override not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("schedule override not found")))
+			return
+		}
+
+		s.logger.Error("error getting schedule override", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting schedule override")))
+		return
+	}
+
+	s.logger.Info("deleting schedule override...", zap.String("externalID", override.ExternalID.String()))
+	err = s.store.DeleteScheduleOverrideByID(c, domain.DeleteScheduleOverrideByIDParams{
+		OrgID: override.OrgID,
+		ID:    override.ID,
+	})
+	if err != nil {
+		s.logger.Error("error deleting schedule override entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("deleted schedule override.", zap.String("externalId", override.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewScheduleOverrideResponse(override))
+}
+
+// schedule loads the schedule named by the externalID path parameter, writing
+// the error response and returning false if it cannot.
+func (s *Server) schedule(c *gin.Context) (*domain.Schedule, bool) {
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return nil, false
+	}
+
+	schedule, err := s.store.GetScheduleByExternalID(c, domain.GetScheduleByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrScheduleNotExists) {
+			s.logger.Warn("schedule not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("schedule not found")))
+			return nil, false
+		}
+
+		s.logger.Error("error getting schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting schedule")))
+		return nil, false
+	}
+	return schedule, true
+}
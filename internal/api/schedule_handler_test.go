@@ -0,0 +1,431 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	schedule := randomSchedule()
+
+	layer := gin.H{
+		"name":        "primary",
+		"rotation":    "weekly",
+		"start":       "2024-07-01",
+		"handoffTime": "09:00",
+		"users":       []string{"alice", "bob"},
+	}
+
+	testCases := []testCase{
+		{
+			name: "create schedule",
+			body: gin.H{
+				"name":     "database",
+				"timeZone": "Europe/London",
+				"layers":   []gin.H{layer},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateSchedule(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateScheduleParams)
+						return p.Name == "database" &&
+							p.OrgID == db.DefaultOrgID &&
+							p.TimeZone == "Europe/London" &&
+							string(p.Layers) == `[{"name":"primary","rotation":"weekly","start":"2024-07-01","handoffTime":"09:00","users":["alice","bob"]}]`
+					})).
+					Times(1).
+					Return(schedule, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var got models.ScheduleRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, schedule.ExternalID, got.ExternalID)
+				require.Len(t, got.Layers, 1)
+				require.Equal(t, []string{"alice", "bob"}, got.Layers[0].Users)
+			},
+		},
+		{
+			name: "create schedule with unknown time zone",
+			body: gin.H{
+				"name":     "database",
+				"timeZone": "Mars/Olympus_Mons",
+				"layers":   []gin.H{layer},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "timeZone")
+			},
+		},
+		{
+			name: "create schedule with invalid handoff time",
+			body: gin.H{
+				"name":     "database",
+				"timeZone": "Europe/London",
+				"layers": []gin.H{{
+					"name":        "primary",
+					"rotation":    "daily",
+					"start":       "2024-07-01",
+					"handoffTime": "9am",
+					"users":       []string{"alice"},
+				}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "handoffTime")
+			},
+		},
+		{
+			name: "create schedule with unknown rotation",
+			body: gin.H{
+				"name":     "database",
+				"timeZone": "Europe/London",
+				"layers": []gin.H{{
+					"name":        "primary",
+					"rotation":    "monthly",
+					"start":       "2024-07-01",
+					"handoffTime": "09:00",
+					"users":       []string{"alice"},
+				}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "create schedule unauthorized",
+			anonymous: true,
+			body: gin.H{
+				"name":     "database",
+				"timeZone": "Europe/London",
+				"layers":   []gin.H{layer},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				addBasicAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetScheduleOnCall(t *testing.T) {
+	schedule := randomSchedule()
+
+	testCases := []testCase{
+		{
+			name:       "on call from rotation",
+			externalID: schedule.ExternalID.String(),
+			query:      "?at=2024-07-09T12:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Eq(domain.GetScheduleByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: schedule.ExternalID})).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().
+					ListScheduleOverridesAt(gomock.Any(), gomock.Eq(domain.ListScheduleOverridesAtParams{
+						ScheduleID: schedule.ID,
+						At:         time.Date(2024, 7, 9, 12, 0, 0, 0, time.UTC),
+					})).
+					Times(1).
+					Return([]*domain.ScheduleOverride{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.OnCallRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, "bob", got.User)
+				require.Equal(t, "primary", got.Layer)
+				require.Nil(t, got.Override)
+				require.True(t, time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC).Equal(got.End))
+			},
+		},
+		{
+			name:       "on call from override",
+			externalID: schedule.ExternalID.String(),
+			query:      "?at=2024-07-09T12:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				override := &domain.ScheduleOverride{
+					ExternalID: uuid.Must(uuid.NewV4()),
+					ScheduleID: schedule.ID,
+					StartsAt:   time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC),
+					EndsAt:     time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC),
+					UserName:   "erin",
+				}
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().
+					ListScheduleOverridesAt(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.ScheduleOverride{override}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.OnCallRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, "erin", got.User)
+				require.NotNil(t, got.Override)
+			},
+		},
+		{
+			name:       "nobody on call",
+			externalID: schedule.ExternalID.String(),
+			query:      "?at=2024-06-01T00:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().
+					ListScheduleOverridesAt(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.ScheduleOverride{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "invalid time",
+			externalID: schedule.ExternalID.String(),
+			query:      "?at=yesterday",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().ListScheduleOverridesAt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "schedule not found",
+			externalID: schedule.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrScheduleNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/schedules/%s/oncall%s", testCase.externalID, testCase.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateScheduleOverride(t *testing.T) {
+	schedule := randomSchedule()
+	endsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []testCase{
+		{
+			name:       "create override",
+			externalID: schedule.ExternalID.String(),
+			body: gin.H{
+				"user":   "erin",
+				"endsAt": endsAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Eq(domain.GetScheduleByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: schedule.ExternalID})).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().
+					CreateScheduleOverride(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateScheduleOverrideParams)
+						return p.ScheduleID == schedule.ID &&
+							p.OrgID == db.DefaultOrgID &&
+							p.UserName == "erin" &&
+							p.CreatedBy == "integrationUser" &&
+							p.EndsAt.Equal(endsAt)
+					})).
+					Times(1).
+					DoAndReturn(func(_ any, p domain.CreateScheduleOverrideParams) (*domain.ScheduleOverride, error) {
+						return &domain.ScheduleOverride{
+							ID:         1,
+							ExternalID: p.ExternalID,
+							ScheduleID: p.ScheduleID,
+							CreatedAt:  p.CreatedAt,
+							StartsAt:   p.StartsAt,
+							EndsAt:     p.EndsAt,
+							UserName:   p.UserName,
+							CreatedBy:  p.CreatedBy,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:       "create override ending before it starts",
+			externalID: schedule.ExternalID.String(),
+			body: gin.H{
+				"user":     "erin",
+				"startsAt": endsAt,
+				"endsAt":   endsAt.Add(-time.Minute),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduleByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(schedule, nil)
+				store.EXPECT().CreateScheduleOverride(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "endsAt")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/schedules/%s/overrides", testCase.externalID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteScheduleOverride(t *testing.T) {
+	schedule := randomSchedule()
+	overrideID := uuid.Must(uuid.NewV4())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetScheduleByExternalID(gomock.Any(), gomock.Eq(domain.GetScheduleByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: schedule.ExternalID})).
+		Times(1).
+		Return(schedule, nil)
+	store.EXPECT().
+		GetScheduleOverrideByExternalID(gomock.Any(), gomock.Eq(domain.GetScheduleOverrideByExternalIDParams{
+			ScheduleID: schedule.ID,
+			ExternalID: overrideID,
+		})).
+		Times(1).
+		Return(nil, db.ErrScheduleOverrideNotExists)
+	store.EXPECT().DeleteScheduleOverrideByID(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/schedules/%s/overrides/%s", schedule.ExternalID, overrideID)
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+	addBasicAuth(request)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func randomSchedule() *domain.Schedule {
+	return &domain.Schedule{
+		ID:         1,
+		ExternalID: uuid.Must(uuid.NewV4()),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Name:       "database",
+		TimeZone:   "Europe/London",
+		Layers:     []byte(`[{"name":"primary","rotation":"weekly","start":"2024-07-01","handoffTime":"09:00","users":["alice","bob"]}]`),
+		OrgID:      db.DefaultOrgID,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -13,14 +14,19 @@ import (
 
 	"github.com/josephlbailey/alert-service/config"
 	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+	"github.com/josephlbailey/alert-service/internal/stream"
 )
 
 type Server struct {
-	config   config.Config
-	logger   *zap.Logger
-	router   *gin.Engine
-	store    db.Store
-	accounts gin.Accounts
+	config config.Config
+	logger *zap.Logger
+	router *gin.Engine
+	store  db.Store
+	broker *stream.Broker
+	auth   Authenticator
+	tokens TokenAuthenticator
+	orgs   sync.Map
 }
 
 func NewServer(config config.Config, logger *zap.Logger, store db.Store) *Server {
@@ -41,25 +47,39 @@ func NewServer(config config.Config, logger *zap.Logger, store db.Store) *Server
 			}
 			return name
 		})
+		_ = v.RegisterValidation("labelname", func(fl validator.FieldLevel) bool {
+			return labels.ValidName(fl.Field().String())
+		})
 	}
 
-	var accounts = make(gin.Accounts)
+	auth, err := newAuthenticator(config, store)
+	if err != nil {
+		logger.Fatal("invalid auth config", zap.Error(err))
+	}
 
-	for _, user := range config.Users {
-		accounts[user.Username] = user.Password
+	tokens, err := newTokenAuthenticator(config, logger, store)
+	if err != nil {
+		logger.Fatal("invalid token auth config", zap.Error(err))
 	}
 
+	// store calls take the gin context, which must reach the organization
+	// that the request context is scoped to
+	engine.ContextWithFallback = true
+
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"http://localhost:4200"}
 	corsConfig.AllowHeaders = []string{"*"}
+	corsConfig.ExposeHeaders = []string{"ETag", idempotentReplayedHeader}
 	engine.Use(cors.New(corsConfig))
 
 	server := &Server{
-		config:   config,
-		logger:   logger,
-		router:   engine,
-		store:    store,
-		accounts: accounts,
+		config: config,
+		logger: logger,
+		router: engine,
+		store:  store,
+		broker: stream.NewBroker(),
+		auth:   auth,
+		tokens: tokens,
 	}
 	return server
 }
@@ -73,17 +93,108 @@ func (s *Server) MountHandlers() {
 		})
 	})
 
+	read := s.authorizeRead()
+
 	alert := s.router.Group("/alert")
-	alert.POST("", gin.BasicAuth(s.accounts), s.CreateAlert)
-	alert.GET("/:externalID", s.GetAlertByExternalID)
-	alert.PUT("/:externalID", gin.BasicAuth(s.accounts), s.UpdateAlertByExternalID)
-	alert.DELETE("/:externalID", gin.BasicAuth(s.accounts), s.DeleteAlertByExternalID)
+	alert.POST("", s.authorize(ScopeAlertsWrite), s.idempotent, s.CreateAlert)
+	alert.POST("/batch", s.authorize(ScopeAlertsWrite), s.idempotent, s.ApplyAlertBatch)
+	alert.GET("", read, s.ListAlerts)
+	alert.GET("/stream", read, s.StreamAlerts)
+	alert.GET("/:externalID", read, s.GetAlertByExternalID)
+	alert.GET("/:externalID/history", read, s.GetAlertHistoryByExternalID)
+	alert.PUT("/:externalID", s.authorize(ScopeAlertsWrite), s.UpdateAlertByExternalID)
+	alert.PATCH("/:externalID", s.authorize(ScopeAlertsWrite), s.PatchAlertByExternalID)
+	alert.DELETE("/:externalID", s.authorize(ScopeAlertsDelete), s.DeleteAlertByExternalID)
+	alert.POST("/:externalID/ack", s.authorize(ScopeAlertsWrite), s.AcknowledgeAlertByExternalID)
+	alert.POST("/:externalID/resolve", s.authorize(ScopeAlertsWrite), s.ResolveAlertByExternalID)
+	alert.POST("/:externalID/restore", s.authorize(ScopeAlertsDelete), s.RestoreAlertByExternalID)
+
+	incidents := s.router.Group("/incidents")
+	incidents.GET("", read, s.ListIncidents)
+	incidents.GET("/:externalID", read, s.GetIncidentByExternalID)
+	incidents.POST("/:externalID/resolve", s.authorize(ScopeAlertsWrite), s.ResolveIncidentByExternalID)
+
+	integrations := s.router.Group("/integrations")
+	integrations.POST("/alertmanager", s.authorize(ScopeAlertsWrite), s.ReceiveAlertmanagerWebhook)
+
+	silences := s.router.Group("/silences")
+	silences.POST("", s.authorize(ScopeAlertsWrite), s.CreateSilence)
+	silences.GET("", s.authorize(ScopeAlertsRead), s.ListSilences)
+	silences.GET("/:externalID", s.authorize(ScopeAlertsRead), s.GetSilenceByExternalID)
+	silences.PUT("/:externalID", s.authorize(ScopeAlertsWrite), s.UpdateSilenceByExternalID)
+	silences.DELETE("/:externalID", s.authorize(ScopeAlertsWrite), s.DeleteSilenceByExternalID)
+
+	policies := s.router.Group("/escalation-policies")
+	policies.POST("", s.authorize(ScopeAlertsWrite), s.CreateEscalationPolicy)
+	policies.GET("", s.authorize(ScopeAlertsRead), s.ListEscalationPolicies)
+	policies.GET("/:externalID", s.authorize(ScopeAlertsRead), s.GetEscalationPolicyByExternalID)
+	policies.PUT("/:externalID", s.authorize(ScopeAlertsWrite), s.UpdateEscalationPolicyByExternalID)
+	policies.DELETE("/:externalID", s.authorize(ScopeAlertsWrite), s.DeleteEscalationPolicyByExternalID)
+
+	schedules := s.router.Group("/schedules")
+	schedules.POST("", s.authorize(ScopeAlertsWrite), s.CreateSchedule)
+	schedules.GET("", s.authorize(ScopeAlertsRead), s.ListSchedules)
+	schedules.GET("/:externalID", s.authorize(ScopeAlertsRead), s.GetScheduleByExternalID)
+	schedules.PUT("/:externalID", s.authorize(ScopeAlertsWrite), s.UpdateScheduleByExternalID)
+	schedules.DELETE("/:externalID", s.authorize(ScopeAlertsWrite), s.DeleteScheduleByExternalID)
+	schedules.GET("/:externalID/oncall", s.authorize(ScopeAlertsRead), s.GetScheduleOnCall)
+	schedules.POST("/:externalID/overrides", s.authorize(ScopeAlertsWrite), s.CreateScheduleOverride)
+	schedules.GET("/:externalID/overrides", s.authorize(ScopeAlertsRead), s.ListScheduleOverrides)
+	schedules.DELETE("/:externalID/overrides/:overrideID", s.authorize(ScopeAlertsWrite), s.DeleteScheduleOverride)
+
+	webhooks := s.router.Group("/webhooks")
+	webhooks.POST("", s.authorize(ScopeAdmin), s.CreateWebhookSubscription)
+	webhooks.GET("", s.authorize(ScopeAlertsRead), s.ListWebhookSubscriptions)
+	webhooks.GET("/:externalID", s.authorize(ScopeAlertsRead), s.GetWebhookSubscriptionByExternalID)
+	webhooks.DELETE("/:externalID", s.authorize(ScopeAdmin), s.DeleteWebhookSubscriptionByExternalID)
+	webhooks.GET("/:externalID/deliveries", s.authorize(ScopeAlertsRead), s.ListWebhookDeliveries)
+
+	keys := s.router.Group("/api-keys")
+	keys.POST("", s.authorize(ScopeAdmin), s.CreateAPIKey)
+	keys.GET("", s.authorize(ScopeAdmin), s.ListAPIKeys)
+	keys.DELETE("/:externalID", s.authorize(ScopeAdmin), s.RevokeAPIKeyByExternalID)
+}
+
+// actor returns the name of the authenticated user making the request.
+func actor(c *gin.Context) string {
+	return c.GetString(gin.AuthUserKey)
+}
+
+// authorizeRead guards the routes that read alerts and incidents, which take
+// the alerts:read scope unless anonymous reads are allowed. Anonymous readers
+// see the default organization.
+func (s *Server) authorizeRead() gin.HandlerFunc {
+	authorize := s.authorize(ScopeAlertsRead)
+	if !s.config.Auth.AnonymousRead {
+		return authorize
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			authorize(c)
+			return
+		}
+		s.scopeOrg(c, db.DefaultOrgID)
+	}
+}
+
+// admin reports whether the request carries the credentials of an admin
+// user. Public routes use it to guard their admin-only options.
+func (s *Server) admin(c *gin.Context) bool {
+	principal, err := s.principal(c)
+	return err == nil && principal.Can(ScopeAdmin)
 }
 
 func (s *Server) Start(addr string) error {
 	return s.router.Run(addr)
 }
 
+// Broker is the source of the alert event stream. It must be fed by the
+// outbox relay.
+func (s *Server) Broker() *stream.Broker {
+	return s.broker
+}
+
 func (s *Server) Router() *gin.Engine {
 	return s.router
 }
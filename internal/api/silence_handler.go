@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func (s *Server) CreateSilence(c *gin.Context) {
+	var (
+		req models.CreateSilenceReq
+		p   domain.CreateSilenceParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.CreatedBy = actor(c)
+	p.OrgID = org(c)
+
+	s.logger.Info("creating silence...", zap.String("createdBy", p.CreatedBy))
+	silence, err := s.store.CreateSilence(c, p)
+	if err != nil {
+		s.logger.Error("error creating silence entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("created silence.", zap.String("externalId", silence.ExternalID.String()))
+	c.JSON(http.StatusCreated, models.NewSilenceResponse(silence))
+}
+
+func (s *Server) ListSilences(c *gin.Context) {
+	var (
+		req models.ListSilencesReq
+		p   domain.ListSilencesParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("listing silences...")
+	silences, err := s.store.ListSilences(c, p)
+	if err != nil {
+		s.logger.Error("error listing silences", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing silences")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSilenceListResponse(silences))
+}
+
+func (s *Server) GetSilenceByExternalID(c *gin.Context) {
+	silence, ok := s.silence(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSilenceResponse(silence))
+}
+
+func (s *Server) UpdateSilenceByExternalID(c *gin.Context) {
+	var (
+		req models.UpdateSilenceReq
+		p   domain.UpdateSilenceByIDParams
+	)
+
+	silence, ok := s.silence(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = silence.OrgID
+	p.ID = silence.ID
+
+	s.logger.Info("updating silence...", zap.String("externalID", silence.ExternalID.String()))
+	silence, err = s.store.UpdateSilenceByIDTX(c, p)
+	if err != nil {
+		s.logger.Error("error updating silence entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("updated silence.", zap.String("externalId", silence.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewSilenceResponse(silence))
+}
+
+func (s *Server) DeleteSilenceByExternalID(c *gin.Context) {
+	silence, ok := s.silence(c)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("deleting silence...", zap.String("externalID", silence.ExternalID.String()))
+	err := s.store.DeleteSilenceByIDTX(c, domain.DeleteSilenceByIDParams{
+		OrgID: silence.OrgID,
+		ID:    silence.ID,
+	})
+	if err != nil {
+		s.logger.Error("error deleting silence entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("deleted silence.", zap.String("externalId", silence.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewSilenceResponse(silence))
+}
+
+// silence loads the silence named by the externalID path parameter, writing
+// the error response and returning false if it cannot.
+func (s *Server) silence(c *gin.Context) (*domain.Silence, bool) {
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return nil, false
+	}
+
+	silence, err := s.store.GetSilenceByExternalID(c, domain.GetSilenceByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrSilenceNotExists) {
+			s.logger.Warn("silence not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("silence not found")))
+			return nil, false
+		}
+
+		s.logger.Error("error getting silence", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting silence")))
+		return nil, false
+	}
+	return silence, true
+}
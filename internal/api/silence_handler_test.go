@@ -0,0 +1,429 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestCreateSilence(t *testing.T) {
+	silence := randomSilence()
+	endsAt := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []testCase{
+		{
+			name: "create silence",
+			body: gin.H{
+				"endsAt":     endsAt,
+				"matchers":   []string{`env="prod"`, "service=~api-.*"},
+				"severities": []string{db.SeverityWarning},
+				"comment":    "database maintenance",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateSilence(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateSilenceParams)
+						return p.CreatedBy == "integrationUser" &&
+							p.OrgID == db.DefaultOrgID &&
+							p.EndsAt.Equal(endsAt) &&
+							!p.StartsAt.IsZero() &&
+							string(p.Matchers) == `[{"name":"env","type":"=","value":"prod"},{"name":"service","type":"=~","value":"api-.*"}]` &&
+							len(p.Severities) == 1
+					})).
+					Times(1).
+					Return(silence, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var got models.SilenceRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, silence.ExternalID, got.ExternalID)
+				require.Equal(t, []string{`env="prod"`}, got.Matchers)
+				require.Equal(t, models.SilenceActive, got.State)
+			},
+		},
+		{
+			name: "create silence with only a severity",
+			body: gin.H{
+				"endsAt":     endsAt,
+				"severities": []string{db.SeverityInfo},
+				"comment":    "quiet hours",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateSilence(gomock.Any(), gomock.Cond(func(x any) bool {
+						return string(x.(domain.CreateSilenceParams).Matchers) == "[]"
+					})).
+					Times(1).
+					Return(silence, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create silence without matchers or severities",
+			body: gin.H{
+				"endsAt":  endsAt,
+				"comment": "silence everything",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "at least one matcher or severity")
+			},
+		},
+		{
+			name: "create silence ending before it starts",
+			body: gin.H{
+				"startsAt": endsAt,
+				"endsAt":   endsAt.Add(-time.Hour),
+				"matchers": []string{"env=prod"},
+				"comment":  "backwards",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "endsAt")
+			},
+		},
+		{
+			name: "create silence that has already ended",
+			body: gin.H{
+				"startsAt": time.Now().Add(-2 * time.Hour),
+				"endsAt":   time.Now().Add(-time.Hour),
+				"matchers": []string{"env=prod"},
+				"comment":  "too late",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "should be in the future")
+			},
+		},
+		{
+			name: "create silence with invalid matcher",
+			body: gin.H{
+				"endsAt":   endsAt,
+				"matchers": []string{"env=~(prod"},
+				"comment":  "broken regexp",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "create silence without comment",
+			body: gin.H{
+				"endsAt":   endsAt,
+				"matchers": []string{"env=prod"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "create silence unauthorized",
+			anonymous: true,
+			body: gin.H{
+				"endsAt":   endsAt,
+				"matchers": []string{"env=prod"},
+				"comment":  "who am I",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSilence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/silences", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				addBasicAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListSilences(t *testing.T) {
+	silence := randomSilence()
+
+	testCases := []testCase{
+		{
+			name: "list all silences",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListSilences(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListSilencesParams)
+						return p.OrgID == db.DefaultOrgID && !p.State.Valid
+					})).
+					Times(1).
+					Return([]*domain.Silence{silence}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []models.SilenceRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got, 1)
+			},
+		},
+		{
+			name:  "list active silences",
+			query: "state=active",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListSilences(gomock.Any(), gomock.Cond(func(x any) bool { return x.(domain.ListSilencesParams).State.String == "active" })).
+					Times(1).
+					Return([]*domain.Silence{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		},
+		{
+			name:  "list silences with invalid state",
+			query: "state=forgotten",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListSilences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/silences?%s", testCase.query), nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateSilenceByExternalID(t *testing.T) {
+	silence := randomSilence()
+	endsAt := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+
+	testCases := []testCase{
+		{
+			name:       "shorten silence",
+			externalID: silence.ExternalID.String(),
+			body: gin.H{
+				"startsAt": silence.StartsAt,
+				"endsAt":   endsAt,
+				"matchers": []string{"env=prod"},
+				"comment":  "finished early",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSilenceByExternalID(gomock.Any(), gomock.Eq(domain.GetSilenceByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: silence.ExternalID})).
+					Times(1).
+					Return(silence, nil)
+				store.EXPECT().
+					UpdateSilenceByIDTX(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.UpdateSilenceByIDParams)
+						return p.OrgID == db.DefaultOrgID && p.ID == silence.ID && p.EndsAt.Equal(endsAt) && p.Comment == "finished early"
+					})).
+					Times(1).
+					Return(silence, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "update silence not found",
+			externalID: silence.ExternalID.String(),
+			body: gin.H{
+				"endsAt":   endsAt,
+				"matchers": []string{"env=prod"},
+				"comment":  "finished early",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSilenceByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrSilenceNotExists)
+				store.EXPECT().UpdateSilenceByIDTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "update silence with invalid body",
+			externalID: silence.ExternalID.String(),
+			body: gin.H{
+				"endsAt":  endsAt,
+				"comment": "no matchers",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSilenceByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(silence, nil)
+				store.EXPECT().UpdateSilenceByIDTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/silences/%s", testCase.externalID), bytes.NewBuffer(data))
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteSilenceByExternalID(t *testing.T) {
+	silence := randomSilence()
+
+	testCases := []testCase{
+		{
+			name:       "delete silence",
+			externalID: silence.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSilenceByExternalID(gomock.Any(), gomock.Eq(domain.GetSilenceByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: silence.ExternalID})).
+					Times(1).
+					Return(silence, nil)
+				store.EXPECT().
+					DeleteSilenceByIDTX(gomock.Any(), gomock.Eq(domain.DeleteSilenceByIDParams{OrgID: db.DefaultOrgID, ID: silence.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "delete silence with invalid id",
+			externalID: "invalidUUID",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSilenceByExternalID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteSilenceByIDTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/silences/%s", testCase.externalID), nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func randomSilence() *domain.Silence {
+	return &domain.Silence{
+		ID:         1,
+		ExternalID: uuid.Must(uuid.NewV4()),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		StartsAt:   time.Now().Add(-time.Hour),
+		EndsAt:     time.Now().Add(time.Hour),
+		Matchers:   []byte(`[{"name":"env","type":"=","value":"prod"}]`),
+		Severities: []string{},
+		CreatedBy:  "integrationUser",
+		Comment:    "database maintenance",
+		OrgID:      db.DefaultOrgID,
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	// streamReplayPageSize is how many persisted events are read at a time
+	// when a client resumes.
+	streamReplayPageSize = 100
+	// streamKeepAlive is how often an idle stream sends a comment so that
+	// proxies do not close the connection.
+	streamKeepAlive = 15 * time.Second
+)
+
+// StreamAlerts sends alert change events as Server-Sent Events. Each event's
+// id is its outbox sequence, so a client that reconnects with Last-Event-ID
+// first receives the events it missed and then continues live.
+func (s *Server) StreamAlerts(c *gin.Context) {
+	var (
+		req    models.StreamAlertsReq
+		filter models.AlertStreamFilter
+	)
+
+	err := req.Bind(c, &filter)
+
+	if err != nil {
+		return
+	}
+
+	// subscribe before replaying so nothing published in between is missed;
+	// anything seen twice is skipped by sequence
+	sub := s.broker.Subscribe()
+	defer s.broker.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	s.logger.Info("streaming alert events...", zap.Bool("resume", filter.Resume), zap.Int64("lastEventId", filter.LastEventID))
+
+	last := filter.LastEventID
+	if filter.Resume {
+		for {
+			events, err := s.store.ListPublishedAlertEventsAfterSequence(c, domain.ListPublishedAlertEventsAfterSequenceParams{
+				OrgID:         org(c),
+				AfterSequence: last,
+				PageSize:      streamReplayPageSize,
+			})
+			if err != nil {
+				s.logger.Error("error replaying alert events", zap.Error(err))
+				return
+			}
+
+			for _, event := range events {
+				if err = s.writeAlertEvent(c, &filter, event); err != nil {
+					return
+				}
+				last = event.Sequence.Int64
+			}
+
+			if len(events) < streamReplayPageSize {
+				break
+			}
+		}
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// dropped for falling behind; the client resumes from last
+				s.logger.Warn("alert stream client fell behind, closing stream")
+				return
+			}
+			if event.Sequence.Int64 <= last {
+				continue
+			}
+			if err = s.writeAlertEvent(c, &filter, event); err != nil {
+				return
+			}
+			last = event.Sequence.Int64
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeAlertEvent writes a single event if it is about an alert of the
+// organization of the request and passes the filter.
+func (s *Server) writeAlertEvent(c *gin.Context, filter *models.AlertStreamFilter, event *domain.AlertEvent) error {
+	alert, err := db.EventAlert(event)
+	if err != nil {
+		// a bad snapshot should not end the stream
+		s.logger.Error("error decoding alert event", zap.Int64("sequence", event.Sequence.Int64), zap.Error(err))
+		return nil
+	}
+
+	if event.OrgID != org(c) || !filter.Matches(alert) {
+		return nil
+	}
+
+	data, err := json.Marshal(models.NewAlertEventResponse(event, alert))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence.Int64, event.EventType, data)
+	if err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
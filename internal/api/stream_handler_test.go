@@ -0,0 +1,271 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestStreamAlertsReplay(t *testing.T) {
+	critical, _ := randomAlert()
+	critical.Severity = db.SeverityCritical
+	warning, _ := randomAlert()
+	otherOrg, _ := randomAlert()
+	otherOrg.OrgID = 2
+
+	testCases := []struct {
+		name          string
+		query         string
+		lastEventID   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "resume replays missed events",
+			lastEventID: "4",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Eq(domain.ListPublishedAlertEventsAfterSequenceParams{
+						OrgID:         db.DefaultOrgID,
+						AfterSequence: 4,
+						PageSize:      streamReplayPageSize,
+					})).
+					Times(1).
+					Return([]*domain.AlertEvent{
+						randomAlertEvent(t, 5, db.EventAlertCreated, critical),
+						randomAlertEvent(t, 6, db.EventAlertAcknowledged, warning),
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+
+				events := parseStreamEvents(t, recorder.Body.String())
+				require.Len(t, events, 2)
+				require.Equal(t, "5", events[0]["id"])
+				require.Equal(t, db.EventAlertCreated, events[0]["event"])
+				require.Equal(t, "6", events[1]["id"])
+				require.Equal(t, db.EventAlertAcknowledged, events[1]["event"])
+			},
+		},
+		{
+			name:        "resume from query parameter applies filters",
+			query:       "severity=critical&lastEventId=4",
+			lastEventID: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.AlertEvent{
+						randomAlertEvent(t, 5, db.EventAlertCreated, critical),
+						randomAlertEvent(t, 6, db.EventAlertCreated, warning),
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				events := parseStreamEvents(t, recorder.Body.String())
+				require.Len(t, events, 1)
+				require.Equal(t, "5", events[0]["id"])
+
+				var data map[string]any
+				require.NoError(t, json.Unmarshal([]byte(events[0]["data"]), &data))
+				require.Equal(t, critical.ExternalID.String(), data["alert"].(map[string]any)["externalId"])
+			},
+		},
+		{
+			name:  "label filter excludes non-matching alerts",
+			query: "label=env%3Dstaging&lastEventId=0",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.AlertEvent{randomAlertEvent(t, 1, db.EventAlertCreated, warning)}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, parseStreamEvents(t, recorder.Body.String()))
+			},
+		},
+		{
+			name:  "events of other organizations are skipped",
+			query: "lastEventId=0",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]*domain.AlertEvent{
+						randomAlertEvent(t, 1, db.EventAlertCreated, otherOrg),
+						randomAlertEvent(t, 2, db.EventAlertCreated, warning),
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				events := parseStreamEvents(t, recorder.Body.String())
+				require.Len(t, events, 1)
+				require.Equal(t, "2", events[0]["id"])
+			},
+		},
+		{
+			name: "without last event id nothing is replayed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, parseStreamEvents(t, recorder.Body.String()))
+			},
+		},
+		{
+			name:        "invalid last event id",
+			lastEventID: "yesterday",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "invalid severity",
+			query: "severity=apocalyptic",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			// the stream ends as soon as the replay is done
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/alert/stream?%s", testCase.query), nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+			if testCase.lastEventID != "" {
+				request.Header.Set("Last-Event-ID", testCase.lastEventID)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestStreamAlertsLive(t *testing.T) {
+	alert, _ := randomAlert()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListPublishedAlertEventsAfterSequence(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]*domain.AlertEvent{randomAlertEvent(t, 3, db.EventAlertCreated, alert)}, nil)
+
+	server := newTestServer(t, store)
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	request, err := http.NewRequest(http.MethodGet, ts.URL+"/alert/stream", nil)
+	require.NoError(t, err)
+	addBasicAuth(request)
+	request.Header.Set("Last-Event-ID", "2")
+
+	resp, err := ts.Client().Do(request)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Eventually(t, func() bool { return server.Broker().Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	// 3 was already replayed, so only 4 is new
+	for _, event := range []*domain.AlertEvent{
+		randomAlertEvent(t, 3, db.EventAlertCreated, alert),
+		randomAlertEvent(t, 4, db.EventAlertResolved, alert),
+	} {
+		require.NoError(t, server.Broker().Publish(context.Background(), event))
+	}
+
+	var body strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		body.WriteString(scanner.Text() + "\n")
+		if scanner.Text() == "" && strings.Contains(body.String(), "id: 4\n") {
+			break
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	events := parseStreamEvents(t, body.String())
+	require.Len(t, events, 2)
+	require.Equal(t, "3", events[0]["id"])
+	require.Equal(t, "4", events[1]["id"])
+	require.Equal(t, db.EventAlertResolved, events[1]["event"])
+}
+
+// parseStreamEvents splits an SSE body into events, skipping comments.
+func parseStreamEvents(t *testing.T, body string) (events []map[string]string) {
+	for _, block := range strings.Split(body, "\n\n") {
+		if strings.TrimSpace(block) == "" || strings.HasPrefix(block, ":") {
+			continue
+		}
+		event := make(map[string]string)
+		for _, line := range strings.Split(block, "\n") {
+			field, value, ok := strings.Cut(line, ": ")
+			require.True(t, ok, "malformed line %q", line)
+			event[field] = value
+		}
+		events = append(events, event)
+	}
+	return
+}
+
+func randomAlertEvent(t *testing.T, sequence int64, eventType string, alert *domain.Alert) *domain.AlertEvent {
+	payload, err := json.Marshal(alert)
+	require.NoError(t, err)
+
+	return &domain.AlertEvent{
+		ID:              sequence,
+		ExternalID:      uuid.Must(uuid.NewV4()),
+		AlertID:         alert.ID,
+		AlertExternalID: alert.ExternalID,
+		EventType:       eventType,
+		Payload:         payload,
+		CreatedAt:       time.Now(),
+		PublishedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		Sequence:        pgtype.Int8{Int64: sequence, Valid: true},
+		OrgID:           alert.OrgID,
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func (s *Server) CreateWebhookSubscription(c *gin.Context) {
+	var (
+		req models.CreateWebhookSubscriptionReq
+		p   domain.CreateWebhookSubscriptionParams
+	)
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.OrgID = org(c)
+
+	s.logger.Info("creating webhook subscription...", zap.String("url", p.Url))
+	subscription, err := s.store.CreateWebhookSubscription(c, p)
+	if err != nil {
+		s.logger.Error("error creating webhook subscription entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("created webhook subscription.", zap.String("externalId", subscription.ExternalID.String()))
+	c.JSON(http.StatusCreated, models.NewWebhookSubscriptionResponse(subscription, true))
+}
+
+func (s *Server) ListWebhookSubscriptions(c *gin.Context) {
+	s.logger.Info("listing webhook subscriptions...")
+	subscriptions, err := s.store.ListWebhookSubscriptions(c, org(c))
+	if err != nil {
+		s.logger.Error("error listing webhook subscriptions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing webhook subscriptions")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewWebhookSubscriptionListResponse(subscriptions))
+}
+
+func (s *Server) GetWebhookSubscriptionByExternalID(c *gin.Context) {
+	subscription, ok := s.webhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewWebhookSubscriptionResponse(subscription, false))
+}
+
+func (s *Server) DeleteWebhookSubscriptionByExternalID(c *gin.Context) {
+	subscription, ok := s.webhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("deleting webhook subscription...", zap.String("externalID", subscription.ExternalID.String()))
+	err := s.store.DeleteWebhookSubscriptionByID(c, domain.DeleteWebhookSubscriptionByIDParams{
+		OrgID: subscription.OrgID,
+		ID:    subscription.ID,
+	})
+	if err != nil {
+		s.logger.Error("error deleting webhook subscription entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(err))
+		return
+	}
+
+	s.logger.Info("deleted webhook subscription.", zap.String("externalId", subscription.ExternalID.String()))
+	c.JSON(http.StatusOK, models.NewWebhookSubscriptionResponse(subscription, false))
+}
+
+func (s *Server) ListWebhookDeliveries(c *gin.Context) {
+	var (
+		req models.ListWebhookDeliveriesReq
+		p   domain.ListWebhookDeliveriesBySubscriptionIDParams
+	)
+
+	subscription, ok := s.webhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	err := req.Bind(c, &p)
+
+	if err != nil {
+		return
+	}
+
+	p.SubscriptionID = subscription.ID
+
+	deliveries, err := s.store.ListWebhookDeliveriesBySubscriptionID(c, p)
+	if err != nil {
+		s.logger.Error("error listing webhook deliveries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while listing webhook deliveries")))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewListWebhookDeliveriesResponse(deliveries, p))
+}
+
+// webhookSubscription loads the subscription named by the externalID path
+// parameter, writing the error response and returning false if it cannot.
+func (s *Server) webhookSubscription(c *gin.Context) (*domain.WebhookSubscription, bool) {
+	var externalID uuid.UUID
+	err := externalID.Parse(c.Param("externalID"))
+	if err != nil {
+		s.logger.Warn("invalid identifier format, returning 400")
+		c.JSON(http.StatusBadRequest, NewError(errors.New("invalid identifier format")))
+		return nil, false
+	}
+
+	subscription, err := s.store.GetWebhookSubscriptionByExternalID(c, domain.GetWebhookSubscriptionByExternalIDParams{
+		OrgID:      org(c),
+		ExternalID: externalID,
+	})
+	if err != nil {
+
+		if errors.Is(err, db.ErrWebhookSubscriptionNotExists) {
+			s.logger.Warn("webhook subscription not found, returning 404")
+			c.JSON(http.StatusNotFound, NewError(errors.New("webhook subscription not found")))
+			return nil, false
+		}
+
+		s.logger.Error("error getting webhook subscription", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, NewError(errors.New("error occurred while getting webhook subscription")))
+		return nil, false
+	}
+	return subscription, true
+}
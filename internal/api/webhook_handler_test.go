@@ -0,0 +1,423 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	subscription := randomWebhookSubscription()
+
+	testCases := []testCase{
+		{
+			name: "create subscription generates a secret",
+			body: gin.H{
+				"url":        subscription.Url,
+				"eventTypes": []string{db.EventAlertCreated, db.EventAlertResolved},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.CreateWebhookSubscriptionParams)
+						return p.Url == subscription.Url && len(p.EventTypes) == 2 && len(p.Secret) == 64 && p.OrgID == db.DefaultOrgID
+					})).
+					Times(1).
+					Return(subscription, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var got models.WebhookSubscriptionRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, subscription.ExternalID, got.ExternalID)
+				require.Equal(t, subscription.Secret, got.Secret)
+			},
+		},
+		{
+			name: "create subscription with client secret",
+			body: gin.H{
+				"url":        subscription.Url,
+				"eventTypes": []string{db.EventAlertCreated},
+				"secret":     "0123456789abcdef0123",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(domain.CreateWebhookSubscriptionParams).Secret == "0123456789abcdef0123"
+					})).
+					Times(1).
+					Return(subscription, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "create subscription with unknown event type",
+			body: gin.H{
+				"url":        subscription.Url,
+				"eventTypes": []string{"alert.exploded"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "create subscription with invalid url",
+			body: gin.H{
+				"url":        "not a url",
+				"eventTypes": []string{db.EventAlertCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "create subscription with short secret",
+			body: gin.H{
+				"url":        subscription.Url,
+				"eventTypes": []string{db.EventAlertCreated},
+				"secret":     "short",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "create subscription unauthorized",
+			anonymous: true,
+			body: gin.H{
+				"url":        subscription.Url,
+				"eventTypes": []string{db.EventAlertCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			if !testCase.anonymous {
+				addAdminAuth(request)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetWebhookSubscriptionByExternalID(t *testing.T) {
+	subscription := randomWebhookSubscription()
+
+	testCases := []testCase{
+		{
+			name:       "get subscription hides the secret",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Eq(domain.GetWebhookSubscriptionByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: subscription.ExternalID})).
+					Times(1).
+					Return(subscription, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.WebhookSubscriptionRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, subscription.Url, got.URL)
+				require.Empty(t, got.Secret)
+			},
+		},
+		{
+			name:       "get subscription not found",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrWebhookSubscriptionNotExists)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "get subscription with invalid id",
+			externalID: "nope",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "get subscription internal error",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("connection reset"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/webhooks/%s", testCase.externalID), nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteWebhookSubscriptionByExternalID(t *testing.T) {
+	subscription := randomWebhookSubscription()
+
+	testCases := []testCase{
+		{
+			name:       "delete subscription",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Eq(domain.GetWebhookSubscriptionByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: subscription.ExternalID})).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					DeleteWebhookSubscriptionByID(gomock.Any(), gomock.Eq(domain.DeleteWebhookSubscriptionByIDParams{OrgID: db.DefaultOrgID, ID: subscription.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "delete subscription not found",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, db.ErrWebhookSubscriptionNotExists)
+				store.EXPECT().DeleteWebhookSubscriptionByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/webhooks/%s", testCase.externalID), nil)
+			require.NoError(t, err)
+			addAdminAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	subscription := randomWebhookSubscription()
+	deliveries := randomWebhookDeliveries(subscription, 3)
+
+	testCases := []testCase{
+		{
+			name:       "list deliveries",
+			externalID: subscription.ExternalID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Eq(domain.GetWebhookSubscriptionByExternalIDParams{OrgID: db.DefaultOrgID, ExternalID: subscription.ExternalID})).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ListWebhookDeliveriesBySubscriptionID(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListWebhookDeliveriesBySubscriptionIDParams)
+						return p.SubscriptionID == subscription.ID && !p.Status.Valid
+					})).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.ListWebhookDeliveriesRes
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Deliveries, len(deliveries))
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name:       "list deliveries filtered by status",
+			externalID: subscription.ExternalID.String(),
+			query:      "status=failed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ListWebhookDeliveriesBySubscriptionID(gomock.Any(), gomock.Cond(func(x any) bool {
+						p := x.(domain.ListWebhookDeliveriesBySubscriptionIDParams)
+						return p.Status.Valid && p.Status.String == db.DeliveryFailed
+					})).
+					Times(1).
+					Return([]*domain.WebhookDelivery{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "list deliveries with invalid status",
+			externalID: subscription.ExternalID.String(),
+			query:      "status=lost",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscriptionByExternalID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().ListWebhookDeliveriesBySubscriptionID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%s/deliveries?%s", testCase.externalID, testCase.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addBasicAuth(request)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(recorder)
+		})
+	}
+}
+
+func addBasicAuth(request *http.Request) {
+	auth := "integrationUser:integrationUserPassword"
+	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+	request.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuth))
+}
+
+func addAdminAuth(request *http.Request) {
+	request.SetBasicAuth("adminServiceUser", "adminServicePassword")
+}
+
+func randomWebhookSubscription() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:         1,
+		ExternalID: uuid.Must(uuid.NewV4()),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Url:        "https://hooks.example.com/alerts",
+		EventTypes: []string{db.EventAlertCreated},
+		Secret:     "5f4dcc3b5aa765d61d8327deb882cf995f4dcc3b5aa765d61d8327deb882cf99",
+		Active:     true,
+		OrgID:      db.DefaultOrgID,
+	}
+}
+
+func randomWebhookDeliveries(subscription *domain.WebhookSubscription, n int) (deliveries []*domain.WebhookDelivery) {
+	for i := 0; i < n; i++ {
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			ID:             int32(n - i),
+			ExternalID:     uuid.Must(uuid.NewV4()),
+			SubscriptionID: subscription.ID,
+			EventType:      db.EventAlertCreated,
+			Payload:        []byte(`{"type":"alert.created"}`),
+			Status:         db.DeliverySucceeded,
+			Attempts:       1,
+			NextAttemptAt:  time.Now(),
+			CreatedAt:      time.Now(),
+		})
+	}
+	return
+}
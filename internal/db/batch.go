@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// copyThreshold is the number of consecutive creates from which an atomic
+// batch inserts them with COPY rather than one statement at a time.
+const copyThreshold = 10
+
+var ErrBatchRolledBack = errors.New("not applied: another operation of the batch failed")
+
+// AlertBatchOp is one operation of an alert batch. Exactly one of Create,
+// Update and Delete is set. Updates and deletes name their alert by
+// ExternalID; the ID of their params is filled in from it.
+type AlertBatchOp struct {
+	ExternalID uuid.UUID
+	Create     *domain.CreateAlertParams
+	Update     *domain.UpdateAlertByIDParams
+	Delete     *domain.DeleteAlertByIDParams
+}
+
+// AlertBatchResult is the outcome of one operation of a batch: the alert it
+// left behind, or the error that stopped it.
+type AlertBatchResult struct {
+	Alert *domain.Alert
+	Err   error
+}
+
+// ApplyAlertBatchTX runs the operations in order in a single transaction.
+//
+// An atomic batch stops at the first operation that fails and rolls back, so
+// every other operation reports ErrBatchRolledBack. Runs of creates long
+// enough to be worth it are inserted with COPY.
+//
+// A partial batch runs each operation in a savepoint of its own and commits
+// the ones that succeed.
+//
+// Every operation works on the alerts of the organization orgID, which new
+// alerts are created in.
+//
+// Operation errors are reported in the results; the error returned is for a
+// failure of the transaction itself.
+func (store *AlertServiceStore) ApplyAlertBatchTX(
+	ctx context.Context,
+	orgID int32,
+	ops []AlertBatchOp,
+	atomic bool,
+	actor string,
+) ([]AlertBatchResult, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	results := make([]AlertBatchResult, len(ops))
+
+	if atomic {
+		failed := -1
+		for i := 0; i < len(ops) && failed < 0; {
+			n := createRun(ops[i:])
+			if n < copyThreshold {
+				n = 1
+				results[i].Alert, results[i].Err = store.applyBatchOp(ctx, qtx, orgID, ops[i], actor)
+			} else {
+				var alerts []*domain.Alert
+				if alerts, err = store.copyAlerts(ctx, qtx, orgID, ops[i:i+n], actor); err != nil {
+					results[i].Err = err
+				}
+				for j, alert := range alerts {
+					results[i+j].Alert = alert
+				}
+			}
+			if results[i].Err != nil {
+				failed = i
+			}
+			i += n
+		}
+
+		if failed >= 0 {
+			for i := range results {
+				if i != failed {
+					results[i] = AlertBatchResult{Err: ErrBatchRolledBack}
+				}
+			}
+			return results, nil
+		}
+	} else {
+		for i, op := range ops {
+			sp, err := tx.Begin(context.Background())
+			if err != nil {
+				return nil, err
+			}
+
+			results[i].Alert, results[i].Err = store.applyBatchOp(ctx, store.Queries.WithTx(sp), orgID, op, actor)
+
+			if results[i].Err != nil {
+				results[i].Alert = nil
+				err = sp.Rollback(context.Background())
+			} else {
+				err = sp.Commit(context.Background())
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (store *AlertServiceStore) applyBatchOp(
+	ctx context.Context,
+	qtx *domain.Queries,
+	orgID int32,
+	op AlertBatchOp,
+	actor string,
+) (*domain.Alert, error) {
+	if op.Create != nil {
+		arg := *op.Create
+		arg.OrgID = orgID
+		return store.createAlert(ctx, qtx, arg, actor)
+	}
+
+	current, err := qtx.GetAlertByExternalID(ctx, domain.GetAlertByExternalIDParams{
+		OrgID:      orgID,
+		ExternalID: op.ExternalID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertNotExists
+		}
+		return nil, err
+	}
+
+	if op.Update != nil {
+		arg := *op.Update
+		arg.ID = current.ID
+		return store.updateAlert(ctx, qtx, arg, actor)
+	}
+
+	arg := *op.Delete
+	arg.ID = current.ID
+	arg.DeletedBy = actor
+	return store.deleteAlert(ctx, qtx, arg)
+}
+
+// copyAlerts creates a run of alerts, inserting those with a new fingerprint
+// in one COPY. The rest are folded into the unresolved alert with their
+// fingerprint, which may be one copied just before, in the usual way.
+func (store *AlertServiceStore) copyAlerts(
+	ctx context.Context,
+	qtx *domain.Queries,
+	orgID int32,
+	ops []AlertBatchOp,
+	actor string,
+) ([]*domain.Alert, error) {
+	args := make([]domain.CreateAlertParams, len(ops))
+	fingerprints := make([]string, len(ops))
+	for i, op := range ops {
+		args[i] = *op.Create
+		args[i].OrgID = orgID
+		if err := silenceAlert(ctx, qtx, &args[i]); err != nil {
+			return nil, err
+		}
+		fingerprints[i] = args[i].Fingerprint
+	}
+
+	open, err := qtx.ListUnresolvedAlertFingerprints(ctx, domain.ListUnresolvedAlertFingerprintsParams{
+		OrgID:        orgID,
+		Fingerprints: fingerprints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	copied := CopyableAlerts(args, open)
+	rows := make([]domain.CopyAlertsParams, 0, len(args))
+	externalIDs := make([]uuid.UUID, 0, len(args))
+	for i, arg := range args {
+		if copied[i] {
+			rows = append(rows, domain.CopyAlertsParams(arg))
+			externalIDs = append(externalIDs, arg.ExternalID)
+		}
+	}
+
+	if _, err = qtx.CopyAlerts(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	inserted, err := qtx.ListAlertsByExternalIDs(ctx, domain.ListAlertsByExternalIDsParams{
+		OrgID:       orgID,
+		ExternalIds: externalIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	byExternalID := make(map[uuid.UUID]*domain.Alert, len(inserted))
+	for _, alert := range inserted {
+		byExternalID[alert.ExternalID] = alert
+	}
+
+	alerts := make([]*domain.Alert, len(args))
+	for i, arg := range args {
+		if copied[i] {
+			alerts[i], err = store.raiseAlert(ctx, qtx, byExternalID[arg.ExternalID], actor)
+		} else {
+			alerts[i], err = store.insertAlert(ctx, qtx, arg, actor)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return alerts, nil
+}
+
+// CopyableAlerts reports which of the new alerts can be copied in: the first
+// alert of each fingerprint that has no unresolved alert yet.
+func CopyableAlerts(args []domain.CreateAlertParams, openFingerprints []string) []bool {
+	seen := make(map[string]bool, len(openFingerprints)+len(args))
+	for _, fingerprint := range openFingerprints {
+		seen[fingerprint] = true
+	}
+
+	copyable := make([]bool, len(args))
+	for i, arg := range args {
+		if !seen[arg.Fingerprint] {
+			seen[arg.Fingerprint] = true
+			copyable[i] = true
+		}
+	}
+	return copyable
+}
+
+// createRun counts the creates at the start of ops.
+func createRun(ops []AlertBatchOp) int {
+	n := 0
+	for n < len(ops) && ops[n].Create != nil {
+		n++
+	}
+	return n
+}
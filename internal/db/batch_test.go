@@ -0,0 +1,30 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestCopyableAlerts(t *testing.T) {
+	args := []domain.CreateAlertParams{
+		{Fingerprint: "a"},
+		{Fingerprint: "b"},
+		{Fingerprint: "a"},
+		{Fingerprint: "c"},
+	}
+
+	require.Equal(t, []bool{true, false, false, true}, CopyableAlerts(args, []string{"b"}))
+	require.Equal(t, []bool{true, true, false, true}, CopyableAlerts(args, nil))
+}
+
+func TestCreateRun(t *testing.T) {
+	create := AlertBatchOp{Create: &domain.CreateAlertParams{}}
+	update := AlertBatchOp{Update: &domain.UpdateAlertByIDParams{}}
+
+	require.Equal(t, 2, createRun([]AlertBatchOp{create, create, update, create}))
+	require.Equal(t, 0, createRun([]AlertBatchOp{update, create}))
+	require.Equal(t, 0, createRun(nil))
+}
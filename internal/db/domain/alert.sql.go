@@ -10,24 +10,125 @@ import (
 	"time"
 
 	uuid "github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acknowledgeAlertByID = `-- name: AcknowledgeAlertByID :one
+update alert
+set status = 'acknowledged',
+    acknowledged_at = $1::timestamptz,
+    acknowledged_by = $2::text,
+    acknowledged_note = $3::text,
+    updated_at = $1,
+    version = version + 1
+where id = $4
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+`
+
+type AcknowledgeAlertByIDParams struct {
+	AcknowledgedAt   time.Time
+	AcknowledgedBy   string
+	AcknowledgedNote pgtype.Text
+	ID               int32
+}
+
+func (q *Queries) AcknowledgeAlertByID(ctx context.Context, arg AcknowledgeAlertByIDParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, acknowledgeAlertByID,
+		arg.AcknowledgedAt,
+		arg.AcknowledgedBy,
+		arg.AcknowledgedNote,
+		arg.ID,
+	)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+type CopyAlertsParams struct {
+	ExternalID    uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Message       string
+	Severity      string
+	Source        string
+	Fingerprint   string
+	LastSeenAt    time.Time
+	Labels        []byte
+	Annotations   []byte
+	SilenceID     pgtype.Int4
+	SilencedUntil pgtype.Timestamptz
+	OrgID         int32
+}
+
 const createAlert = `-- name: CreateAlert :one
 insert into alert (
                      external_id,
                      created_at,
                      updated_at,
-                     message
+                     message,
+                     severity,
+                     source,
+                     fingerprint,
+                     last_seen_at,
+                     labels,
+                     annotations,
+                     silence_id,
+                     silenced_until,
+                     org_id
 )
-values ($1, $2, $3, $4)
-returning id, external_id, created_at, updated_at, message
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+on conflict (org_id, fingerprint) where status <> 'resolved' and deleted_at is null
+    do update set occurrences    = alert.occurrences + 1,
+                  last_seen_at   = excluded.last_seen_at,
+                  updated_at     = excluded.updated_at,
+                  silence_id     = excluded.silence_id,
+                  silenced_until = excluded.silenced_until,
+                  version        = alert.version + 1
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
 `
 
 type CreateAlertParams struct {
-	ExternalID uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Message    string
+	ExternalID    uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Message       string
+	Severity      string
+	Source        string
+	Fingerprint   string
+	LastSeenAt    time.Time
+	Labels        []byte
+	Annotations   []byte
+	SilenceID     pgtype.Int4
+	SilencedUntil pgtype.Timestamptz
+	OrgID         int32
 }
 
 func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (*Alert, error) {
@@ -36,6 +137,15 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (*Aler
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.Message,
+		arg.Severity,
+		arg.Source,
+		arg.Fingerprint,
+		arg.LastSeenAt,
+		arg.Labels,
+		arg.Annotations,
+		arg.SilenceID,
+		arg.SilencedUntil,
+		arg.OrgID,
 	)
 	var i Alert
 	err := row.Scan(
@@ -44,28 +154,772 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (*Aler
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
 	)
 	return &i, err
 }
 
-const deleteAlertByID = `-- name: DeleteAlertByID :exec
-delete from alert
-where id = $1
+const deleteAlertByID = `-- name: DeleteAlertByID :one
+update alert
+set deleted_at = $1::timestamptz,
+    deleted_by = $2::text,
+    version = version + 1
+where id = $3
+  and ($4::integer is null or version = $4)
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
 `
 
-func (q *Queries) DeleteAlertByID(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteAlertByID, id)
-	return err
+type DeleteAlertByIDParams struct {
+	DeletedAt time.Time
+	DeletedBy string
+	ID        int32
+	Version   pgtype.Int4
+}
+
+func (q *Queries) DeleteAlertByID(ctx context.Context, arg DeleteAlertByIDParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, deleteAlertByID,
+		arg.DeletedAt,
+		arg.DeletedBy,
+		arg.ID,
+		arg.Version,
+	)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
 }
 
 const getAlertByExternalID = `-- name: GetAlertByExternalID :one
-select id, external_id, created_at, updated_at, message
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and external_id = $2
+  and deleted_at is null
+`
+
+type GetAlertByExternalIDParams struct {
+	OrgID      int32
+	ExternalID uuid.UUID
+}
+
+func (q *Queries) GetAlertByExternalID(ctx context.Context, arg GetAlertByExternalIDParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, getAlertByExternalID, arg.OrgID, arg.ExternalID)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const getAlertByExternalIDIncludeDeleted = `-- name: GetAlertByExternalIDIncludeDeleted :one
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and external_id = $2
+`
+
+type GetAlertByExternalIDIncludeDeletedParams struct {
+	OrgID      int32
+	ExternalID uuid.UUID
+}
+
+func (q *Queries) GetAlertByExternalIDIncludeDeleted(ctx context.Context, arg GetAlertByExternalIDIncludeDeletedParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, getAlertByExternalIDIncludeDeleted, arg.OrgID, arg.ExternalID)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const getAlertByIDForUpdate = `-- name: GetAlertByIDForUpdate :one
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where id = $1
+for update
+`
+
+func (q *Queries) GetAlertByIDForUpdate(ctx context.Context, id int32) (*Alert, error) {
+	row := q.db.QueryRow(ctx, getAlertByIDForUpdate, id)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const getUnresolvedAlertByFingerprint = `-- name: GetUnresolvedAlertByFingerprint :one
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and fingerprint = $2
+  and status <> 'resolved'
+  and deleted_at is null
+`
+
+type GetUnresolvedAlertByFingerprintParams struct {
+	OrgID       int32
+	Fingerprint string
+}
+
+func (q *Queries) GetUnresolvedAlertByFingerprint(ctx context.Context, arg GetUnresolvedAlertByFingerprintParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, getUnresolvedAlertByFingerprint, arg.OrgID, arg.Fingerprint)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const listAlerts = `-- name: ListAlerts :many
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and ($2::boolean or deleted_at is null)
+  and ($3::timestamptz is null or created_at >= $3)
+  and ($4::timestamptz is null or created_at < $4)
+  and ($5::timestamptz is null or updated_at >= $5)
+  and ($6::timestamptz is null or updated_at < $6)
+  and ($7::text is null or message ilike '%' || $7 || '%')
+  and ($8::integer is null or case
+        when $9::text = 'updated_at' and $10::boolean
+            then (updated_at, id) < ($11::timestamptz, $8)
+        when $9 = 'updated_at'
+            then (updated_at, id) > ($11, $8)
+        when $10
+            then (created_at, id) < ($11, $8)
+        else (created_at, id) > ($11, $8)
+    end)
+  and ($12::text is null or severity = $12)
+  and ($13::text is null or status = $13)
+  and ($14::jsonb is null or labels @> $14)
+  -- regular expression matchers are applied by the caller, with Go semantics
+  and not exists (select 1
+                  from unnest($15::text[], $16::text[], $17::text[]) as m(name, op, value)
+                  where not case m.op
+                      when '!=' then coalesce(labels ->> m.name, '') <> m.value
+                      else coalesce(labels ->> m.name, '') = m.value
+                  end)
+order by case when $9 = 'updated_at' and not $10 then updated_at end,
+         case when $9 = 'updated_at' and $10 then updated_at end desc,
+         case when $9 <> 'updated_at' and not $10 then created_at end,
+         case when $9 <> 'updated_at' and $10 then created_at end desc,
+         case when not $10 then id end,
+         case when $10 then id end desc
+limit $18
+`
+
+type ListAlertsParams struct {
+	OrgID          int32
+	IncludeDeleted bool
+	CreatedAfter   pgtype.Timestamptz
+	CreatedBefore  pgtype.Timestamptz
+	UpdatedAfter   pgtype.Timestamptz
+	UpdatedBefore  pgtype.Timestamptz
+	Message        pgtype.Text
+	CursorID       pgtype.Int4
+	SortBy         string
+	SortDesc       bool
+	CursorTime     pgtype.Timestamptz
+	Severity       pgtype.Text
+	Status         pgtype.Text
+	LabelsContain  []byte
+	MatcherNames   []string
+	MatcherTypes   []string
+	MatcherValues  []string
+	PageSize       int32
+}
+
+func (q *Queries) ListAlerts(ctx context.Context, arg ListAlertsParams) ([]*Alert, error) {
+	rows, err := q.db.Query(ctx, listAlerts,
+		arg.OrgID,
+		arg.IncludeDeleted,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.Message,
+		arg.CursorID,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.CursorTime,
+		arg.Severity,
+		arg.Status,
+		arg.LabelsContain,
+		arg.MatcherNames,
+		arg.MatcherTypes,
+		arg.MatcherValues,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Message,
+			&i.Severity,
+			&i.Status,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedBy,
+			&i.AcknowledgedNote,
+			&i.ResolvedAt,
+			&i.ResolvedBy,
+			&i.ResolvedNote,
+			&i.Source,
+			&i.Fingerprint,
+			&i.Occurrences,
+			&i.LastSeenAt,
+			&i.Labels,
+			&i.Annotations,
+			&i.SilenceID,
+			&i.SilencedUntil,
+			&i.IncidentID,
+			&i.InhibitedBy,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Version,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAlertsByExternalIDs = `-- name: ListAlertsByExternalIDs :many
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and external_id = any ($2::uuid[])
+order by id
+`
+
+type ListAlertsByExternalIDsParams struct {
+	OrgID       int32
+	ExternalIds []uuid.UUID
+}
+
+func (q *Queries) ListAlertsByExternalIDs(ctx context.Context, arg ListAlertsByExternalIDsParams) ([]*Alert, error) {
+	rows, err := q.db.Query(ctx, listAlertsByExternalIDs, arg.OrgID, arg.ExternalIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Message,
+			&i.Severity,
+			&i.Status,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedBy,
+			&i.AcknowledgedNote,
+			&i.ResolvedAt,
+			&i.ResolvedBy,
+			&i.ResolvedNote,
+			&i.Source,
+			&i.Fingerprint,
+			&i.Occurrences,
+			&i.LastSeenAt,
+			&i.Labels,
+			&i.Annotations,
+			&i.SilenceID,
+			&i.SilencedUntil,
+			&i.IncidentID,
+			&i.InhibitedBy,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Version,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAlertsInhibitedByForUpdate = `-- name: ListAlertsInhibitedByForUpdate :many
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where inhibited_by = $1
+order by id
+for update
+`
+
+func (q *Queries) ListAlertsInhibitedByForUpdate(ctx context.Context, inhibitedBy pgtype.UUID) ([]*Alert, error) {
+	rows, err := q.db.Query(ctx, listAlertsInhibitedByForUpdate, inhibitedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Message,
+			&i.Severity,
+			&i.Status,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedBy,
+			&i.AcknowledgedNote,
+			&i.ResolvedAt,
+			&i.ResolvedBy,
+			&i.ResolvedNote,
+			&i.Source,
+			&i.Fingerprint,
+			&i.Occurrences,
+			&i.LastSeenAt,
+			&i.Labels,
+			&i.Annotations,
+			&i.SilenceID,
+			&i.SilencedUntil,
+			&i.IncidentID,
+			&i.InhibitedBy,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Version,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnresolvedAlertFingerprints = `-- name: ListUnresolvedAlertFingerprints :many
+select fingerprint
+from alert
+where org_id = $1
+  and fingerprint = any ($2::text[])
+  and status <> 'resolved'
+  and deleted_at is null
+`
+
+type ListUnresolvedAlertFingerprintsParams struct {
+	OrgID        int32
+	Fingerprints []string
+}
+
+func (q *Queries) ListUnresolvedAlertFingerprints(ctx context.Context, arg ListUnresolvedAlertFingerprintsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUnresolvedAlertFingerprints, arg.OrgID, arg.Fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var fingerprint string
+		if err := rows.Scan(&fingerprint); err != nil {
+			return nil, err
+		}
+		items = append(items, fingerprint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnresolvedAlertsMatching = `-- name: ListUnresolvedAlertsMatching :many
+select id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+from alert
+where org_id = $1
+  and status <> 'resolved'
+  and deleted_at is null
+  and id <> all ($2::integer[])
+  -- regular expression matchers are applied by the caller, with Go semantics
+  and not exists (select 1
+                  from unnest($3::text[], $4::text[], $5::text[]) as m(name, op, value)
+                  where not case m.op
+                      when '!=' then coalesce(labels ->> m.name, '') <> m.value
+                      else coalesce(labels ->> m.name, '') = m.value
+                  end)
+order by id
+`
+
+type ListUnresolvedAlertsMatchingParams struct {
+	OrgID         int32
+	ExcludeIds    []int32
+	MatcherNames  []string
+	MatcherTypes  []string
+	MatcherValues []string
+}
+
+func (q *Queries) ListUnresolvedAlertsMatching(ctx context.Context, arg ListUnresolvedAlertsMatchingParams) ([]*Alert, error) {
+	rows, err := q.db.Query(ctx, listUnresolvedAlertsMatching,
+		arg.OrgID,
+		arg.ExcludeIds,
+		arg.MatcherNames,
+		arg.MatcherTypes,
+		arg.MatcherValues,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Message,
+			&i.Severity,
+			&i.Status,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedBy,
+			&i.AcknowledgedNote,
+			&i.ResolvedAt,
+			&i.ResolvedBy,
+			&i.ResolvedNote,
+			&i.Source,
+			&i.Fingerprint,
+			&i.Occurrences,
+			&i.LastSeenAt,
+			&i.Labels,
+			&i.Annotations,
+			&i.SilenceID,
+			&i.SilencedUntil,
+			&i.IncidentID,
+			&i.InhibitedBy,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Version,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedAlerts = `-- name: PurgeDeletedAlerts :execrows
+delete
 from alert
-where external_id = $1
+where id in (select id
+             from alert
+             where deleted_at < $1::timestamptz
+             order by deleted_at
+             limit $2)
+`
+
+type PurgeDeletedAlertsParams struct {
+	DeletedBefore time.Time
+	BatchSize     int32
+}
+
+func (q *Queries) PurgeDeletedAlerts(ctx context.Context, arg PurgeDeletedAlertsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedAlerts, arg.DeletedBefore, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resolveAlertByID = `-- name: ResolveAlertByID :one
+update alert
+set status = 'resolved',
+    resolved_at = $1::timestamptz,
+    resolved_by = $2::text,
+    resolved_note = $3::text,
+    updated_at = $1,
+    version = version + 1
+where id = $4
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+`
+
+type ResolveAlertByIDParams struct {
+	ResolvedAt   time.Time
+	ResolvedBy   string
+	ResolvedNote pgtype.Text
+	ID           int32
+}
+
+func (q *Queries) ResolveAlertByID(ctx context.Context, arg ResolveAlertByIDParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, resolveAlertByID,
+		arg.ResolvedAt,
+		arg.ResolvedBy,
+		arg.ResolvedNote,
+		arg.ID,
+	)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const restoreAlertByID = `-- name: RestoreAlertByID :one
+update alert
+set deleted_at = null,
+    deleted_by = null,
+    version = version + 1
+where id = $1
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
+`
+
+func (q *Queries) RestoreAlertByID(ctx context.Context, id int32) (*Alert, error) {
+	row := q.db.QueryRow(ctx, restoreAlertByID, id)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
+	)
+	return &i, err
+}
+
+const setAlertInhibitedByID = `-- name: SetAlertInhibitedByID :one
+update alert
+set inhibited_by = $1,
+    version = version + 1
+where id = $2
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
 `
 
-func (q *Queries) GetAlertByExternalID(ctx context.Context, externalID uuid.UUID) (*Alert, error) {
-	row := q.db.QueryRow(ctx, getAlertByExternalID, externalID)
+type SetAlertInhibitedByIDParams struct {
+	InhibitedBy pgtype.UUID
+	ID          int32
+}
+
+func (q *Queries) SetAlertInhibitedByID(ctx context.Context, arg SetAlertInhibitedByIDParams) (*Alert, error) {
+	row := q.db.QueryRow(ctx, setAlertInhibitedByID, arg.InhibitedBy, arg.ID)
 	var i Alert
 	err := row.Scan(
 		&i.ID,
@@ -73,6 +927,28 @@ func (q *Queries) GetAlertByExternalID(ctx context.Context, externalID uuid.UUID
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
 	)
 	return &i, err
 }
@@ -80,19 +956,46 @@ func (q *Queries) GetAlertByExternalID(ctx context.Context, externalID uuid.UUID
 const updateAlertByID = `-- name: UpdateAlertByID :one
 update alert
 set message = $1,
-    updated_at = $2
-where id = $3
-returning id, external_id, created_at, updated_at, message
+    updated_at = $2,
+    severity = coalesce($3::text, severity),
+    status = coalesce($4::text, status),
+    labels = coalesce($5::jsonb, labels),
+    annotations = coalesce($6::jsonb, annotations),
+    -- reopening clears the actions taken on the resolved alert
+    acknowledged_at = case when status = 'resolved' and $4::text = 'open' then null else acknowledged_at end,
+    acknowledged_by = case when status = 'resolved' and $4::text = 'open' then null else acknowledged_by end,
+    acknowledged_note = case when status = 'resolved' and $4::text = 'open' then null else acknowledged_note end,
+    resolved_at = case when status = 'resolved' and $4::text = 'open' then null else resolved_at end,
+    resolved_by = case when status = 'resolved' and $4::text = 'open' then null else resolved_by end,
+    resolved_note = case when status = 'resolved' and $4::text = 'open' then null else resolved_note end,
+    version = version + 1
+where id = $7
+  and ($8::integer is null or version = $8)
+returning id, external_id, created_at, updated_at, message, severity, status, acknowledged_at, acknowledged_by, acknowledged_note, resolved_at, resolved_by, resolved_note, source, fingerprint, occurrences, last_seen_at, labels, annotations, silence_id, silenced_until, incident_id, inhibited_by, deleted_at, deleted_by, version, org_id
 `
 
 type UpdateAlertByIDParams struct {
-	Message   string
-	UpdatedAt time.Time
-	ID        int32
+	Message     string
+	UpdatedAt   time.Time
+	Severity    pgtype.Text
+	Status      pgtype.Text
+	Labels      []byte
+	Annotations []byte
+	ID          int32
+	Version     pgtype.Int4
 }
 
 func (q *Queries) UpdateAlertByID(ctx context.Context, arg UpdateAlertByIDParams) (*Alert, error) {
-	row := q.db.QueryRow(ctx, updateAlertByID, arg.Message, arg.UpdatedAt, arg.ID)
+	row := q.db.QueryRow(ctx, updateAlertByID,
+		arg.Message,
+		arg.UpdatedAt,
+		arg.Severity,
+		arg.Status,
+		arg.Labels,
+		arg.Annotations,
+		arg.ID,
+		arg.Version,
+	)
 	var i Alert
 	err := row.Scan(
 		&i.ID,
@@ -100,6 +1003,45 @@ func (q *Queries) UpdateAlertByID(ctx context.Context, arg UpdateAlertByIDParams
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Message,
+		&i.Severity,
+		&i.Status,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedNote,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.ResolvedNote,
+		&i.Source,
+		&i.Fingerprint,
+		&i.Occurrences,
+		&i.LastSeenAt,
+		&i.Labels,
+		&i.Annotations,
+		&i.SilenceID,
+		&i.SilencedUntil,
+		&i.IncidentID,
+		&i.InhibitedBy,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Version,
+		&i.OrgID,
 	)
 	return &i, err
 }
+
+const updateAlertSilenceBySilenceID = `-- name: UpdateAlertSilenceBySilenceID :exec
+update alert
+set silenced_until = $1,
+    version = version + 1
+where silence_id = $2
+`
+
+type UpdateAlertSilenceBySilenceIDParams struct {
+	SilencedUntil pgtype.Timestamptz
+	SilenceID     pgtype.Int4
+}
+
+func (q *Queries) UpdateAlertSilenceBySilenceID(ctx context.Context, arg UpdateAlertSilenceBySilenceIDParams) error {
+	_, err := q.db.Exec(ctx, updateAlertSilenceBySilenceID, arg.SilencedUntil, arg.SilenceID)
+	return err
+}
@@ -8,12 +8,218 @@ import (
 	"time"
 
 	uuid "github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Alert struct {
+	ID               int32
+	ExternalID       uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Message          string
+	Severity         string
+	Status           string
+	AcknowledgedAt   pgtype.Timestamptz
+	AcknowledgedBy   pgtype.Text
+	AcknowledgedNote pgtype.Text
+	ResolvedAt       pgtype.Timestamptz
+	ResolvedBy       pgtype.Text
+	ResolvedNote     pgtype.Text
+	Source           string
+	Fingerprint      string
+	Occurrences      int32
+	LastSeenAt       time.Time
+	Labels           []byte
+	Annotations      []byte
+	SilenceID        pgtype.Int4
+	SilencedUntil    pgtype.Timestamptz
+	IncidentID       pgtype.Int4
+	InhibitedBy      pgtype.UUID
+	DeletedAt        pgtype.Timestamptz
+	DeletedBy        pgtype.Text
+	Version          int32
+	OrgID            int32
+}
+
+type AlertEscalation struct {
+	AlertID          int32
+	PolicyID         int32
+	Step             int32
+	NextEscalationAt pgtype.Timestamptz
+	LastEscalatedAt  pgtype.Timestamptz
+	CreatedAt        time.Time
+	OrgID            int32
+}
+
+type AlertEvent struct {
+	ID              int64
+	ExternalID      uuid.UUID
+	AlertID         int32
+	AlertExternalID uuid.UUID
+	EventType       string
+	Payload         []byte
+	CreatedAt       time.Time
+	PublishedAt     pgtype.Timestamptz
+	Sequence        pgtype.Int8
+	Detail          []byte
+	OrgID           int32
+}
+
+type AlertHistory struct {
+	ID              int64
+	AlertExternalID uuid.UUID
+	CreatedAt       time.Time
+	Actor           string
+	Action          string
+	Before          []byte
+	After           []byte
+	OrgID           int32
+}
+
+type ApiKey struct {
+	ID         int32
+	ExternalID uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	CreatedAt  time.Time
+	CreatedBy  string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	OrgID      int32
+}
+
+type ApiUser struct {
+	ID           int32
+	Username     string
+	PasswordHash string
+	CreatedAt    time.Time
+	DisabledAt   pgtype.Timestamptz
+	Role         string
+	OrgID        int32
+}
+
+type EscalationPolicy struct {
+	ID         int32
+	ExternalID uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	Matchers   []byte
+	Severities []string
+	Steps      []byte
+	OrgID      int32
+}
+
+type IdempotencyKey struct {
+	Actor           string
+	Key             string
+	RequestHash     string
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	ResponseStatus  pgtype.Int4
+	ResponseHeaders []byte
+	ResponseBody    []byte
+	OrgID           int32
+}
+
+type Incident struct {
+	ID          int32
+	ExternalID  uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	GroupKey    string
+	GroupLabels []byte
+	Status      string
+	LastAlertAt time.Time
+	ResolvedAt  pgtype.Timestamptz
+	ResolvedBy  pgtype.Text
+	OrgID       int32
+}
+
+type IncidentTimeline struct {
+	ID         int64
+	IncidentID int32
+	CreatedAt  time.Time
+	EntryType  string
+	AlertID    pgtype.Int4
+	Actor      pgtype.Text
+	Note       pgtype.Text
+	OrgID      int32
+}
+
+type Organization struct {
+	ID         int32
+	ExternalID uuid.UUID
+	Slug       string
+	Name       string
+	CreatedAt  time.Time
+}
+
+type Schedule struct {
+	ID         int32
+	ExternalID uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	TimeZone   string
+	Layers     []byte
+	OrgID      int32
+}
+
+type ScheduleOverride struct {
+	ID         int32
+	ExternalID uuid.UUID
+	ScheduleID int32
+	CreatedAt  time.Time
+	StartsAt   time.Time
+	EndsAt     time.Time
+	UserName   string
+	CreatedBy  string
+	OrgID      int32
+}
+
+type Silence struct {
+	ID         int32
+	ExternalID uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	StartsAt   time.Time
+	EndsAt     time.Time
+	Matchers   []byte
+	Severities []string
+	CreatedBy  string
+	Comment    string
+	OrgID      int32
+}
+
+type WebhookDelivery struct {
+	ID             int32
+	ExternalID     uuid.UUID
+	SubscriptionID int32
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  pgtype.Timestamptz
+	ResponseStatus pgtype.Int4
+	LastError      pgtype.Text
+	CreatedAt      time.Time
+	DeliveredAt    pgtype.Timestamptz
+	OrgID          int32
+}
+
+type WebhookSubscription struct {
 	ID         int32
 	ExternalID uuid.UUID
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
-	Message    string
+	Url        string
+	EventTypes []string
+	Secret     string
+	Active     bool
+	OrgID      int32
 }
@@ -6,15 +6,100 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	uuid "github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AcknowledgeAlertByID(ctx context.Context, arg AcknowledgeAlertByIDParams) (*Alert, error)
+	AdvanceAlertEscalation(ctx context.Context, arg AdvanceAlertEscalationParams) error
+	ClaimDueAlertEscalations(ctx context.Context, arg ClaimDueAlertEscalationsParams) ([]*AlertEscalation, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]*WebhookDelivery, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CopyAlerts(ctx context.Context, arg []CopyAlertsParams) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (*ApiKey, error)
 	CreateAlert(ctx context.Context, arg CreateAlertParams) (*Alert, error)
-	DeleteAlertByID(ctx context.Context, id int32) error
-	GetAlertByExternalID(ctx context.Context, externalID uuid.UUID) (*Alert, error)
+	CreateAlertEscalation(ctx context.Context, arg CreateAlertEscalationParams) error
+	CreateAlertEvent(ctx context.Context, arg CreateAlertEventParams) error
+	CreateAlertHistory(ctx context.Context, arg CreateAlertHistoryParams) error
+	CreateEscalationPolicy(ctx context.Context, arg CreateEscalationPolicyParams) (*EscalationPolicy, error)
+	CreateIncident(ctx context.Context, arg CreateIncidentParams) (*Incident, error)
+	CreateIncidentTimelineEntry(ctx context.Context, arg CreateIncidentTimelineEntryParams) error
+	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (*Schedule, error)
+	CreateScheduleOverride(ctx context.Context, arg CreateScheduleOverrideParams) (*ScheduleOverride, error)
+	CreateSilence(ctx context.Context, arg CreateSilenceParams) (*Silence, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (*WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (*WebhookSubscription, error)
+	DeleteAlertByID(ctx context.Context, arg DeleteAlertByIDParams) (*Alert, error)
+	DeleteEscalationPolicyByID(ctx context.Context, arg DeleteEscalationPolicyByIDParams) error
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteScheduleByID(ctx context.Context, arg DeleteScheduleByIDParams) error
+	DeleteScheduleOverrideByID(ctx context.Context, arg DeleteScheduleOverrideByIDParams) error
+	DeleteSilenceByID(ctx context.Context, arg DeleteSilenceByIDParams) error
+	DeleteWebhookSubscriptionByID(ctx context.Context, arg DeleteWebhookSubscriptionByIDParams) error
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*ApiKey, error)
+	GetAPIUserByUsername(ctx context.Context, username string) (*ApiUser, error)
+	GetAlertByExternalID(ctx context.Context, arg GetAlertByExternalIDParams) (*Alert, error)
+	GetAlertByExternalIDIncludeDeleted(ctx context.Context, arg GetAlertByExternalIDIncludeDeletedParams) (*Alert, error)
+	GetAlertByIDForUpdate(ctx context.Context, id int32) (*Alert, error)
+	GetEscalationPolicyByExternalID(ctx context.Context, arg GetEscalationPolicyByExternalIDParams) (*EscalationPolicy, error)
+	GetEscalationPolicyByID(ctx context.Context, id int32) (*EscalationPolicy, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (*IdempotencyKey, error)
+	GetIncidentByExternalID(ctx context.Context, arg GetIncidentByExternalIDParams) (*Incident, error)
+	GetIncidentByIDForUpdate(ctx context.Context, arg GetIncidentByIDForUpdateParams) (*Incident, error)
+	GetOpenIncidentByGroupKey(ctx context.Context, arg GetOpenIncidentByGroupKeyParams) (*Incident, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*Organization, error)
+	GetScheduleByExternalID(ctx context.Context, arg GetScheduleByExternalIDParams) (*Schedule, error)
+	GetScheduleOverrideByExternalID(ctx context.Context, arg GetScheduleOverrideByExternalIDParams) (*ScheduleOverride, error)
+	GetSilenceByExternalID(ctx context.Context, arg GetSilenceByExternalIDParams) (*Silence, error)
+	GetSilenceByIDForUpdate(ctx context.Context, arg GetSilenceByIDForUpdateParams) (*Silence, error)
+	GetUnresolvedAlertByFingerprint(ctx context.Context, arg GetUnresolvedAlertByFingerprintParams) (*Alert, error)
+	GetWebhookSubscriptionByExternalID(ctx context.Context, arg GetWebhookSubscriptionByExternalIDParams) (*WebhookSubscription, error)
+	GetWebhookSubscriptionByID(ctx context.Context, id int32) (*WebhookSubscription, error)
+	ListAPIKeys(ctx context.Context, orgID int32) ([]*ApiKey, error)
+	ListActiveSilences(ctx context.Context, arg ListActiveSilencesParams) ([]*Silence, error)
+	ListActiveWebhookSubscriptionsForEvent(ctx context.Context, arg ListActiveWebhookSubscriptionsForEventParams) ([]*WebhookSubscription, error)
+	ListAlertExternalIDsByIncidentID(ctx context.Context, incidentID pgtype.Int4) ([]uuid.UUID, error)
+	ListAlertHistoryByExternalID(ctx context.Context, arg ListAlertHistoryByExternalIDParams) ([]*AlertHistory, error)
+	ListAlerts(ctx context.Context, arg ListAlertsParams) ([]*Alert, error)
+	ListAlertsByExternalIDs(ctx context.Context, arg ListAlertsByExternalIDsParams) ([]*Alert, error)
+	ListAlertsInhibitedByForUpdate(ctx context.Context, inhibitedBy pgtype.UUID) ([]*Alert, error)
+	ListEscalationPolicies(ctx context.Context, orgID int32) ([]*EscalationPolicy, error)
+	ListIncidentTimeline(ctx context.Context, incidentID int32) ([]*ListIncidentTimelineRow, error)
+	ListIncidents(ctx context.Context, arg ListIncidentsParams) ([]*Incident, error)
+	ListPublishedAlertEventsAfterSequence(ctx context.Context, arg ListPublishedAlertEventsAfterSequenceParams) ([]*AlertEvent, error)
+	ListScheduleOverrides(ctx context.Context, arg ListScheduleOverridesParams) ([]*ScheduleOverride, error)
+	ListScheduleOverridesAt(ctx context.Context, arg ListScheduleOverridesAtParams) ([]*ScheduleOverride, error)
+	ListSchedules(ctx context.Context, orgID int32) ([]*Schedule, error)
+	ListSilences(ctx context.Context, arg ListSilencesParams) ([]*Silence, error)
+	ListUnpublishedAlertEventsForUpdate(ctx context.Context, batchSize int32) ([]*AlertEvent, error)
+	ListUnresolvedAlertFingerprints(ctx context.Context, arg ListUnresolvedAlertFingerprintsParams) ([]string, error)
+	ListUnresolvedAlertsByIncidentIDForUpdate(ctx context.Context, incidentID pgtype.Int4) ([]*Alert, error)
+	ListUnresolvedAlertsMatching(ctx context.Context, arg ListUnresolvedAlertsMatchingParams) ([]*Alert, error)
+	ListWebhookDeliveriesBySubscriptionID(ctx context.Context, arg ListWebhookDeliveriesBySubscriptionIDParams) ([]*WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, orgID int32) ([]*WebhookSubscription, error)
+	LockIncidentGroup(ctx context.Context, arg LockIncidentGroupParams) error
+	MarkAlertEventPublished(ctx context.Context, arg MarkAlertEventPublishedParams) (int64, error)
+	PurgeDeletedAlerts(ctx context.Context, arg PurgeDeletedAlertsParams) (int64, error)
+	PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) error
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (*IdempotencyKey, error)
+	ResolveAlertByID(ctx context.Context, arg ResolveAlertByIDParams) (*Alert, error)
+	ResolveIncidentByID(ctx context.Context, arg ResolveIncidentByIDParams) (*Incident, error)
+	RestoreAlertByID(ctx context.Context, id int32) (*Alert, error)
+	RevokeAPIKeyByExternalID(ctx context.Context, arg RevokeAPIKeyByExternalIDParams) (*ApiKey, error)
+	SetAlertIncidentByID(ctx context.Context, arg SetAlertIncidentByIDParams) (*Alert, error)
+	SetAlertInhibitedByID(ctx context.Context, arg SetAlertInhibitedByIDParams) (*Alert, error)
+	SetOrgContext(ctx context.Context, arg SetOrgContextParams) error
+	TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error
+	TouchIncident(ctx context.Context, arg TouchIncidentParams) error
 	UpdateAlertByID(ctx context.Context, arg UpdateAlertByIDParams) (*Alert, error)
+	UpdateAlertSilenceBySilenceID(ctx context.Context, arg UpdateAlertSilenceBySilenceIDParams) error
+	UpdateEscalationPolicyByID(ctx context.Context, arg UpdateEscalationPolicyByIDParams) (*EscalationPolicy, error)
+	UpdateScheduleByID(ctx context.Context, arg UpdateScheduleByIDParams) (*Schedule, error)
+	UpdateSilenceByID(ctx context.Context, arg UpdateSilenceByIDParams) (*Silence, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+// EscalationStep notifies Target and then waits Wait before the policy moves
+// on to the next step.
+type EscalationStep struct {
+	Target      string `json:"target"`
+	WaitMinutes int32  `json:"waitMinutes"`
+}
+
+func (step EscalationStep) Wait() time.Duration {
+	return time.Duration(step.WaitMinutes) * time.Minute
+}
+
+// EscalationDetail is stored with an alert.escalated event.
+type EscalationDetail struct {
+	Policy     uuid.UUID `json:"policy"`
+	PolicyName string    `json:"policyName"`
+	Step       int32     `json:"step"`
+	Target     string    `json:"target"`
+	OnCall     string    `json:"onCall,omitempty"`
+	Final      bool      `json:"final"`
+}
+
+func EscalationSteps(policy *domain.EscalationPolicy) ([]EscalationStep, error) {
+	var steps []EscalationStep
+	if err := json.Unmarshal(policy.Steps, &steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+func EscalationPolicyMatchers(policy *domain.EscalationPolicy) (labels.Matchers, error) {
+	return decodeMatchers(policy.Matchers)
+}
+
+// EscalationPolicyMatches reports whether a policy applies to an alert with
+// the given severity and labels.
+func EscalationPolicyMatches(policy *domain.EscalationPolicy, severity string, ls map[string]string) (bool, error) {
+	return matchAlert(policy.Matchers, policy.Severities, severity, ls)
+}
+
+// escalateAlert starts a new alert on the oldest escalation policy of its
+// organization that matches it. The first step is due straight away.
+func escalateAlert(ctx context.Context, qtx *domain.Queries, alert *domain.Alert) error {
+	policies, err := qtx.ListEscalationPolicies(ctx, alert.OrgID)
+	if err != nil {
+		return err
+	}
+
+	policy, err := oldestEscalationPolicy(policies, alert)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	now := time.Now()
+	return qtx.CreateAlertEscalation(ctx, domain.CreateAlertEscalationParams{
+		AlertID:          alert.ID,
+		PolicyID:         policy.ID,
+		NextEscalationAt: pgtype.Timestamptz{Time: now, Valid: true},
+		CreatedAt:        now,
+		OrgID:            alert.OrgID,
+	})
+}
+
+// oldestEscalationPolicy returns the first of policies, ordered by id, that
+// belongs to the organization of alert and matches it.
+func oldestEscalationPolicy(policies []*domain.EscalationPolicy, alert *domain.Alert) (*domain.EscalationPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	ls, err := decodeLabels(alert.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, policy := range policies {
+		if policy.OrgID != alert.OrgID {
+			continue
+		}
+
+		ok, err := EscalationPolicyMatches(policy, alert.Severity, ls)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
+
+// advanceEscalation runs the due step of an alert's escalation: it records an
+// alert.escalated event for the step's target and schedules the next step.
+func advanceEscalation(
+	ctx context.Context,
+	qtx *domain.Queries,
+	escalation *domain.AlertEscalation,
+	policy *domain.EscalationPolicy,
+	now time.Time,
+) error {
+
+	// lock the alert so that an acknowledgement cannot slip in between the
+	// claim and the event
+	alert, err := qtx.GetAlertByIDForUpdate(ctx, escalation.AlertID)
+	if err != nil {
+		return err
+	}
+
+	steps, err := EscalationSteps(policy)
+	if err != nil {
+		return err
+	}
+
+	arg := domain.AdvanceAlertEscalationParams{
+		AlertID:         escalation.AlertID,
+		Step:            escalation.Step,
+		LastEscalatedAt: now,
+	}
+
+	// the policy may have lost steps since the alert started on it
+	if alert.Status != StatusOpen || int(escalation.Step) >= len(steps) {
+		return qtx.AdvanceAlertEscalation(ctx, arg)
+	}
+
+	step := steps[escalation.Step]
+	arg.Step++
+	final := int(arg.Step) >= len(steps)
+	if !final {
+		arg.NextEscalationAt = pgtype.Timestamptz{Time: now.Add(step.Wait()), Valid: true}
+	}
+
+	detail := EscalationDetail{
+		Policy:     policy.ExternalID,
+		PolicyName: policy.Name,
+		Step:       arg.Step,
+		Target:     step.Target,
+		Final:      final,
+	}
+	if detail.OnCall, err = escalationOnCall(ctx, qtx, policy.OrgID, step.Target, now); err != nil {
+		return err
+	}
+	if err = recordAlertEventDetail(ctx, qtx, EventAlertEscalated, alert, detail); err != nil {
+		return err
+	}
+
+	return qtx.AdvanceAlertEscalation(ctx, arg)
+}
+
+// escalationOnCall resolves who is on call for a step that targets a
+// schedule of the organization orgID. A schedule that has been deleted since
+// the policy was written, or that belongs to another organization, leaves
+// nobody on call rather than stalling the escalation.
+func escalationOnCall(ctx context.Context, qtx *domain.Queries, orgID int32, target string, at time.Time) (string, error) {
+	id, ok := ScheduleTarget(target)
+	if !ok {
+		return "", nil
+	}
+
+	schedule, err := qtx.GetScheduleByExternalID(ctx, domain.GetScheduleByExternalIDParams{
+		OrgID:      orgID,
+		ExternalID: id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	onCall, err := ScheduleOnCall(ctx, qtx, schedule, at)
+	if err != nil || onCall == nil {
+		return "", err
+	}
+	return onCall.User, nil
+}
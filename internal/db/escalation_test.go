@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestEscalationPolicyMatches(t *testing.T) {
+	policy := &domain.EscalationPolicy{
+		Matchers:   []byte(`[{"name":"team","type":"=","value":"db"}]`),
+		Severities: []string{SeverityCritical, SeverityHigh},
+	}
+
+	ok, err := EscalationPolicyMatches(policy, SeverityCritical, map[string]string{"team": "db"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = EscalationPolicyMatches(policy, SeverityWarning, map[string]string{"team": "db"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = EscalationPolicyMatches(policy, SeverityCritical, map[string]string{"team": "web"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	catchAll := &domain.EscalationPolicy{Matchers: []byte(`[]`)}
+	ok, err = EscalationPolicyMatches(catchAll, SeverityInfo, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestEscalationSteps(t *testing.T) {
+	policy := &domain.EscalationPolicy{
+		Steps: []byte(`[{"target":"primary-oncall","waitMinutes":15},{"target":"db-lead"}]`),
+	}
+
+	steps, err := EscalationSteps(policy)
+	require.NoError(t, err)
+	require.Equal(t, []EscalationStep{
+		{Target: "primary-oncall", WaitMinutes: 15},
+		{Target: "db-lead"},
+	}, steps)
+	require.Equal(t, 15*time.Minute, steps[0].Wait())
+	require.Zero(t, steps[1].Wait())
+}
+
+func TestOldestEscalationPolicy(t *testing.T) {
+	catchAll := []byte(`[]`)
+	alert := &domain.Alert{Severity: SeverityCritical, Labels: []byte(`{"team":"db"}`), OrgID: DefaultOrgID}
+
+	policies := []*domain.EscalationPolicy{
+		{ID: 1, Matchers: catchAll, OrgID: 2},
+		{ID: 2, Matchers: []byte(`[{"name":"team","type":"=","value":"web"}]`), OrgID: DefaultOrgID},
+		{ID: 3, Matchers: catchAll, OrgID: DefaultOrgID},
+	}
+
+	policy, err := oldestEscalationPolicy(policies, alert)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), policy.ID)
+
+	// a policy of another organization never escalates the alert
+	policy, err = oldestEscalationPolicy(policies[:1], alert)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// Alert change event types, as recorded in the alert_event outbox and
+// delivered to webhook subscribers.
+const (
+	EventAlertCreated      = "alert.created"
+	EventAlertUpdated      = "alert.updated"
+	EventAlertDeleted      = "alert.deleted"
+	EventAlertRestored     = "alert.restored"
+	EventAlertAcknowledged = "alert.acknowledged"
+	EventAlertResolved     = "alert.resolved"
+	EventAlertEscalated    = "alert.escalated"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// recordAlertEvent writes an outbox event for the alert. It must be called
+// with the Queries of the transaction that changed the alert so that the event
+// is committed, or rolled back, together with the change.
+func recordAlertEvent(ctx context.Context, qtx *domain.Queries, eventType string, alert *domain.Alert) error {
+	return recordAlertEventDetail(ctx, qtx, eventType, alert, nil)
+}
+
+// recordAlertEventDetail is recordAlertEvent for event types that carry
+// detail beyond the alert itself. A nil detail is stored as null.
+func recordAlertEventDetail(ctx context.Context, qtx *domain.Queries, eventType string, alert *domain.Alert, detail any) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if detail != nil {
+		if data, err = json.Marshal(detail); err != nil {
+			return err
+		}
+	}
+
+	return qtx.CreateAlertEvent(ctx, domain.CreateAlertEventParams{
+		ExternalID:      uuid.Must(uuid.NewV4()),
+		AlertID:         alert.ID,
+		AlertExternalID: alert.ExternalID,
+		EventType:       eventType,
+		Payload:         payload,
+		CreatedAt:       time.Now(),
+		Detail:          data,
+		OrgID:           alert.OrgID,
+	})
+}
+
+// EventAlert decodes the snapshot of the alert stored with an event. For
+// deletes this is the alert as it was immediately before it was removed.
+func EventAlert(event *domain.AlertEvent) (*domain.Alert, error) {
+	var alert domain.Alert
+	if err := json.Unmarshal(event.Payload, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
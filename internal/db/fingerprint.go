@@ -0,0 +1,21 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+// Fingerprint identifies repeats of the same alert. It is a SHA-256 over the
+// source, the canonical label set and the message, separated by NUL bytes so
+// that neighbouring fields cannot run into each other.
+func Fingerprint(source string, ls map[string]string, message string) string {
+	h := sha256.New()
+	h.Write([]byte(source))
+	h.Write([]byte{0})
+	h.Write([]byte(labels.Canonical(ls)))
+	h.Write([]byte{0})
+	h.Write([]byte(message))
+	return hex.EncodeToString(h.Sum(nil))
+}
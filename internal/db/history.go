@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// Alert history actions.
+const (
+	ActionCreate      = "create"
+	ActionUpdate      = "update"
+	ActionDelete      = "delete"
+	ActionRestore     = "restore"
+	ActionAcknowledge = "acknowledge"
+	ActionResolve     = "resolve"
+)
+
+// recordAlertHistory writes an audit entry for a change made by actor. before
+// is nil for creates. Like recordAlertEvent it
+// must run in the transaction that made the change.
+func recordAlertHistory(
+	ctx context.Context,
+	qtx *domain.Queries,
+	action string,
+	actor string,
+	before *domain.Alert,
+	after *domain.Alert,
+) error {
+	arg := domain.CreateAlertHistoryParams{
+		CreatedAt: time.Now(),
+		Actor:     actor,
+		Action:    action,
+	}
+
+	var err error
+	if before != nil {
+		arg.AlertExternalID = before.ExternalID
+		arg.OrgID = before.OrgID
+		if arg.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		arg.AlertExternalID = after.ExternalID
+		arg.OrgID = after.OrgID
+		if arg.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	return qtx.CreateAlertHistory(ctx, arg)
+}
+
+// HistoryAlerts decodes the snapshots stored with a history entry. Either may
+// be nil.
+func HistoryAlerts(entry *domain.AlertHistory) (before, after *domain.Alert, err error) {
+	if len(entry.Before) > 0 {
+		before = new(domain.Alert)
+		if err = json.Unmarshal(entry.Before, before); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(entry.After) > 0 {
+		after = new(domain.Alert)
+		if err = json.Unmarshal(entry.After, after); err != nil {
+			return nil, nil, err
+		}
+	}
+	return before, after, nil
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// Incident timeline entry types.
+const (
+	TimelineIncidentOpened    = "incident.opened"
+	TimelineAlertAdded        = "alert.added"
+	TimelineAlertAcknowledged = "alert.acknowledged"
+	TimelineAlertResolved     = "alert.resolved"
+	TimelineIncidentResolved  = "incident.resolved"
+)
+
+// groupAlert adds a new alert to the open incident for its group, opening one
+// if the group has none or its last alert is older than the group window.
+// Alerts that carry none of the group-by labels are left ungrouped.
+func groupAlert(ctx context.Context, qtx *domain.Queries, alert *domain.Alert, grouping config.IncidentConfig) (*domain.Alert, error) {
+	key, groupLabels, err := incidentGroup(alert, grouping.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return alert, nil
+	}
+
+	// serialise alerts of the same group so that they cannot open two
+	// incidents between them
+	err = qtx.LockIncidentGroup(ctx, domain.LockIncidentGroupParams{OrgID: alert.OrgID, GroupKey: key})
+	if err != nil {
+		return nil, err
+	}
+
+	now := alert.CreatedAt
+	incident, err := qtx.GetOpenIncidentByGroupKey(ctx, domain.GetOpenIncidentByGroupKeyParams{
+		OrgID:    alert.OrgID,
+		GroupKey: key,
+		Since:    now.Add(-grouping.GroupWindow),
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		incident, err = qtx.CreateIncident(ctx, domain.CreateIncidentParams{
+			ExternalID:  uuid.Must(uuid.NewV4()),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			GroupKey:    key,
+			GroupLabels: groupLabels,
+			LastAlertAt: now,
+			OrgID:       alert.OrgID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = recordIncidentTimeline(ctx, qtx, incident.OrgID, incident.ID, TimelineIncidentOpened, nil, "", nil); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		err = qtx.TouchIncident(ctx, domain.TouchIncidentParams{LastAlertAt: now, ID: incident.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = recordIncidentTimeline(ctx, qtx, incident.OrgID, incident.ID, TimelineAlertAdded, alert, "", nil); err != nil {
+		return nil, err
+	}
+
+	return qtx.SetAlertIncidentByID(ctx, domain.SetAlertIncidentByIDParams{
+		IncidentID: pgtype.Int4{Int32: incident.ID, Valid: true},
+		ID:         alert.ID,
+	})
+}
+
+// incidentGroup returns the key and labels of the group of alert, or an empty
+// key if it carries none of the groupBy labels. Groups never span
+// organizations, so the key starts with the organization of the alert.
+func incidentGroup(alert *domain.Alert, groupBy []string) (string, []byte, error) {
+	if len(groupBy) == 0 {
+		return "", nil, nil
+	}
+
+	ls, err := decodeLabels(alert.Labels)
+	if err != nil {
+		return "", nil, err
+	}
+
+	group := make(map[string]string)
+	for _, name := range groupBy {
+		if value, ok := ls[name]; ok {
+			group[name] = value
+		}
+	}
+	if len(group) == 0 {
+		return "", nil, nil
+	}
+
+	// maps are encoded with sorted keys, so the encoding doubles as the key
+	groupLabels, err := json.Marshal(group)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("%d:%s", alert.OrgID, groupLabels), groupLabels, nil
+}
+
+// recordAlertTimeline adds an entry about an alert to the timeline of its
+// incident, if it has one.
+func recordAlertTimeline(ctx context.Context, qtx *domain.Queries, entryType string, alert *domain.Alert, actor string, note pgtype.Text) error {
+	if !alert.IncidentID.Valid {
+		return nil
+	}
+	return recordIncidentTimeline(ctx, qtx, alert.OrgID, alert.IncidentID.Int32, entryType, alert, actor, &note)
+}
+
+func recordIncidentTimeline(
+	ctx context.Context,
+	qtx *domain.Queries,
+	orgID int32,
+	incidentID int32,
+	entryType string,
+	alert *domain.Alert,
+	actor string,
+	note *pgtype.Text,
+) error {
+	arg := domain.CreateIncidentTimelineEntryParams{
+		IncidentID: incidentID,
+		CreatedAt:  time.Now(),
+		OrgID:      orgID,
+		EntryType:  entryType,
+		Actor:      pgtype.Text{String: actor, Valid: actor != ""},
+	}
+	if alert != nil {
+		arg.AlertID = pgtype.Int4{Int32: alert.ID, Valid: true}
+	}
+	if note != nil {
+		arg.Note = *note
+	}
+	return qtx.CreateIncidentTimelineEntry(ctx, arg)
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestIncidentGroup(t *testing.T) {
+	groupBy := []string{"alertname", "cluster"}
+
+	key, groupLabels, err := incidentGroup(&domain.Alert{
+		Labels: []byte(`{"alertname":"DiskFull","cluster":"eu-1","host":"db-1"}`),
+		OrgID:  DefaultOrgID,
+	}, groupBy)
+	require.NoError(t, err)
+	require.Equal(t, `1:{"alertname":"DiskFull","cluster":"eu-1"}`, key)
+	require.JSONEq(t, `{"alertname":"DiskFull","cluster":"eu-1"}`, string(groupLabels))
+
+	// the same labels in another organization make another group
+	otherKey, _, err := incidentGroup(&domain.Alert{
+		Labels: []byte(`{"alertname":"DiskFull","cluster":"eu-1","host":"db-2"}`),
+		OrgID:  2,
+	}, groupBy)
+	require.NoError(t, err)
+	require.NotEqual(t, key, otherKey)
+
+	key, _, err = incidentGroup(&domain.Alert{Labels: []byte(`{"host":"db-1"}`), OrgID: DefaultOrgID}, groupBy)
+	require.NoError(t, err)
+	require.Empty(t, key)
+
+	key, _, err = incidentGroup(&domain.Alert{Labels: []byte(`{"alertname":"DiskFull"}`)}, nil)
+	require.NoError(t, err)
+	require.Empty(t, key)
+}
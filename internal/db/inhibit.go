@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+var errEmptyInhibitMatchers = errors.New("source and target matchers are required")
+
+// InhibitRule lets an unresolved source alert hold back target alerts that
+// share its values for the Equal labels.
+type InhibitRule struct {
+	SourceMatchers labels.Matchers
+	TargetMatchers labels.Matchers
+	Equal          []string
+}
+
+func ParseInhibitRules(rules []config.InhibitRuleConfig) ([]InhibitRule, error) {
+	out := make([]InhibitRule, 0, len(rules))
+	for i, rule := range rules {
+		if len(rule.SourceMatchers) == 0 || len(rule.TargetMatchers) == 0 {
+			return nil, fmt.Errorf("inhibit rule %d: %w", i, errEmptyInhibitMatchers)
+		}
+
+		source, err := labels.ParseMatchers(rule.SourceMatchers)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit rule %d: source: %w", i, err)
+		}
+		target, err := labels.ParseMatchers(rule.TargetMatchers)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit rule %d: target: %w", i, err)
+		}
+
+		out = append(out, InhibitRule{SourceMatchers: source, TargetMatchers: target, Equal: rule.Equal})
+	}
+	return out, nil
+}
+
+// Inhibits reports whether an alert with the source labels inhibits one with
+// the target labels. A label missing from both sides counts as equal.
+func (rule InhibitRule) Inhibits(source, target map[string]string) bool {
+	if !rule.SourceMatchers.Matches(source) || !rule.TargetMatchers.Matches(target) {
+		return false
+	}
+	for _, name := range rule.Equal {
+		if source[name] != target[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// AlertInhibited reports whether an alert is held back by an inhibition rule.
+func AlertInhibited(alert *domain.Alert) bool {
+	return alert.InhibitedBy.Valid
+}
+
+// inhibitAlert applies the inhibition rules to a new alert in both
+// directions: it is held back by the oldest unresolved alert that inhibits
+// it, and it holds back the unresolved alerts it inhibits that are not held
+// back already.
+func inhibitAlert(ctx context.Context, qtx *domain.Queries, alert *domain.Alert, rules []InhibitRule) (*domain.Alert, error) {
+	if len(rules) == 0 {
+		return alert, nil
+	}
+
+	ls, err := decodeLabels(alert.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := findInhibitor(ctx, qtx, alert.OrgID, rules, ls, []int32{alert.ID})
+	if err != nil {
+		return nil, err
+	}
+	if source != nil {
+		alert, err = qtx.SetAlertInhibitedByID(ctx, domain.SetAlertInhibitedByIDParams{
+			InhibitedBy: pgtype.UUID{Bytes: source.ExternalID, Valid: true},
+			ID:          alert.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range rules {
+		if !rule.SourceMatchers.Matches(ls) {
+			continue
+		}
+
+		targets, err := listUnresolvedAlertsMatching(ctx, qtx, alert.OrgID, rule.TargetMatchers, []int32{alert.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range targets {
+			// never let two alerts hold each other back
+			if AlertInhibited(target) || (source != nil && target.ID == source.ID) {
+				continue
+			}
+
+			tls, err := decodeLabels(target.Labels)
+			if err != nil {
+				return nil, err
+			}
+			if !rule.Inhibits(ls, tls) {
+				continue
+			}
+
+			_, err = qtx.SetAlertInhibitedByID(ctx, domain.SetAlertInhibitedByIDParams{
+				InhibitedBy: pgtype.UUID{Bytes: alert.ExternalID, Valid: true},
+				ID:          target.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return alert, nil
+}
+
+// releaseInhibited lets go of the alerts a resolved or deleted source alert
+// held back. Each one moves over to another unresolved alert that still
+// inhibits it, if there is one.
+func releaseInhibited(ctx context.Context, qtx *domain.Queries, source *domain.Alert, rules []InhibitRule) error {
+	targets, err := qtx.ListAlertsInhibitedByForUpdate(ctx, pgtype.UUID{Bytes: source.ExternalID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		tls, err := decodeLabels(target.Labels)
+		if err != nil {
+			return err
+		}
+
+		other, err := findInhibitor(ctx, qtx, source.OrgID, rules, tls, []int32{target.ID, source.ID})
+		if err != nil {
+			return err
+		}
+
+		arg := domain.SetAlertInhibitedByIDParams{ID: target.ID}
+		if other != nil {
+			arg.InhibitedBy = pgtype.UUID{Bytes: other.ExternalID, Valid: true}
+		}
+		if _, err = qtx.SetAlertInhibitedByID(ctx, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findInhibitor returns the oldest unresolved alert of the organization,
+// other than the excluded ones, that inhibits an alert with the given labels.
+func findInhibitor(
+	ctx context.Context,
+	qtx *domain.Queries,
+	orgID int32,
+	rules []InhibitRule,
+	target map[string]string,
+	exclude []int32,
+) (*domain.Alert, error) {
+	var found *domain.Alert
+	for _, rule := range rules {
+		if !rule.TargetMatchers.Matches(target) {
+			continue
+		}
+
+		sources, err := listUnresolvedAlertsMatching(ctx, qtx, orgID, rule.SourceMatchers, exclude)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, source := range sources {
+			if found != nil && found.ID < source.ID {
+				break
+			}
+
+			sls, err := decodeLabels(source.Labels)
+			if err != nil {
+				return nil, err
+			}
+			if rule.Inhibits(sls, target) {
+				found = source
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// listUnresolvedAlertsMatching narrows the unresolved alerts of the
+// organization down in the database by the matchers other than regular
+// expressions; the caller still checks each one against all the matchers.
+func listUnresolvedAlertsMatching(
+	ctx context.Context,
+	qtx *domain.Queries,
+	orgID int32,
+	ms labels.Matchers,
+	exclude []int32,
+) ([]*domain.Alert, error) {
+	arg := domain.ListUnresolvedAlertsMatchingParams{
+		OrgID:         orgID,
+		ExcludeIds:    exclude,
+		MatcherNames:  make([]string, 0, len(ms)),
+		MatcherTypes:  make([]string, 0, len(ms)),
+		MatcherValues: make([]string, 0, len(ms)),
+	}
+	for _, m := range ms {
+		if m.IsRegexp() {
+			continue
+		}
+		arg.MatcherNames = append(arg.MatcherNames, m.Name)
+		arg.MatcherTypes = append(arg.MatcherTypes, string(m.Type))
+		arg.MatcherValues = append(arg.MatcherValues, m.Value)
+	}
+	return qtx.ListUnresolvedAlertsMatching(ctx, arg)
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/config"
+)
+
+func TestParseInhibitRules(t *testing.T) {
+	rules, err := ParseInhibitRules([]config.InhibitRuleConfig{{
+		SourceMatchers: []string{`alertname="DatacenterDown"`},
+		TargetMatchers: []string{`alertname=~"Host.*"`},
+		Equal:          []string{"datacenter"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	_, err = ParseInhibitRules([]config.InhibitRuleConfig{{
+		TargetMatchers: []string{`alertname="HostDown"`},
+	}})
+	require.ErrorIs(t, err, errEmptyInhibitMatchers)
+
+	_, err = ParseInhibitRules([]config.InhibitRuleConfig{{
+		SourceMatchers: []string{"not a matcher"},
+		TargetMatchers: []string{`alertname="HostDown"`},
+	}})
+	require.Error(t, err)
+}
+
+func TestInhibitRuleInhibits(t *testing.T) {
+	rules, err := ParseInhibitRules([]config.InhibitRuleConfig{{
+		SourceMatchers: []string{`alertname="DatacenterDown"`},
+		TargetMatchers: []string{`alertname="HostDown"`},
+		Equal:          []string{"datacenter"},
+	}})
+	require.NoError(t, err)
+	rule := rules[0]
+
+	source := map[string]string{"alertname": "DatacenterDown", "datacenter": "ams1"}
+
+	require.True(t, rule.Inhibits(source, map[string]string{"alertname": "HostDown", "datacenter": "ams1", "host": "web-1"}))
+	require.False(t, rule.Inhibits(source, map[string]string{"alertname": "HostDown", "datacenter": "fra1"}))
+	require.False(t, rule.Inhibits(source, map[string]string{"alertname": "HostDown"}))
+	require.False(t, rule.Inhibits(source, map[string]string{"alertname": "DiskFull", "datacenter": "ams1"}))
+	require.False(t, rule.Inhibits(
+		map[string]string{"alertname": "HostDown", "datacenter": "ams1"},
+		map[string]string{"alertname": "HostDown", "datacenter": "ams1"},
+	))
+
+	// a label missing on both sides counts as equal
+	require.True(t, rule.Inhibits(
+		map[string]string{"alertname": "DatacenterDown"},
+		map[string]string{"alertname": "HostDown"},
+	))
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+func decodeMatchers(data []byte) (labels.Matchers, error) {
+	var ms labels.Matchers
+	if err := json.Unmarshal(data, &ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func decodeLabels(data []byte) (map[string]string, error) {
+	ls := make(map[string]string)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &ls); err != nil {
+			return nil, err
+		}
+	}
+	return ls, nil
+}
+
+// matchAlert reports whether stored matchers and severities select an alert.
+// An empty severity list matches any severity.
+func matchAlert(matchers []byte, severities []string, severity string, ls map[string]string) (bool, error) {
+	if len(severities) > 0 {
+		found := false
+		for _, s := range severities {
+			if s == severity {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	ms, err := decodeMatchers(matchers)
+	if err != nil {
+		return false, err
+	}
+	return ms.Matches(ls), nil
+}
+
+// matchScanPages is how many pages of alerts listAlertsMatching reads at most
+// for one request, so that rare matches cannot make it read the whole table.
+const matchScanPages = 10
+
+// listAlertsMatching reads pages of alerts from list, keeping those whose
+// labels match ms, until arg.PageSize alerts match, the alerts run out or
+// matchScanPages pages have been read. Each further page starts just past the
+// last alert read, whether it matched or not. When it stops at the limit it
+// also returns that last alert, from which the caller can carry on.
+func listAlertsMatching(
+	ctx context.Context,
+	list func(context.Context, domain.ListAlertsParams) ([]*domain.Alert, error),
+	arg domain.ListAlertsParams,
+	ms labels.Matchers,
+) ([]*domain.Alert, *domain.Alert, error) {
+	if len(ms) == 0 {
+		alerts, err := list(ctx, arg)
+		return alerts, nil, err
+	}
+
+	var matched []*domain.Alert
+	for page := 1; ; page++ {
+		alerts, err := list(ctx, arg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, alert := range alerts {
+			ls, err := decodeLabels(alert.Labels)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ms.Matches(ls) {
+				continue
+			}
+			if matched = append(matched, alert); len(matched) == int(arg.PageSize) {
+				return matched, nil, nil
+			}
+		}
+
+		if len(alerts) < int(arg.PageSize) {
+			return matched, nil, nil
+		}
+
+		last := alerts[len(alerts)-1]
+		if page == matchScanPages {
+			return matched, last, nil
+		}
+		arg.CursorID = pgtype.Int4{Int32: last.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: last.CreatedAt, Valid: true}
+		if arg.SortBy == "updated_at" {
+			arg.CursorTime.Time = last.UpdatedAt
+		}
+	}
+}
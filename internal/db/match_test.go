@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+func TestListAlertsMatching(t *testing.T) {
+	// RE2 syntax that Postgres regular expressions do not share
+	m, err := labels.NewMatcher(labels.MatchRegexp, "service", `(?P<name>api)-\pL+`)
+	require.NoError(t, err)
+	ms := labels.Matchers{m}
+
+	now := time.Now()
+	alerts := make([]*domain.Alert, 0, 7)
+	for i, service := range []string{"api-a", "db", "api-b", "web", "db", "api-c", "api-1"} {
+		alerts = append(alerts, &domain.Alert{
+			ID:        int32(i + 1),
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+			Labels:    []byte(fmt.Sprintf(`{"service":%q}`, service)),
+		})
+	}
+
+	// pages through alerts after the cursor, like ListAlerts
+	var calls int
+	list := func(_ context.Context, arg domain.ListAlertsParams) ([]*domain.Alert, error) {
+		calls++
+		page := alerts
+		if arg.CursorID.Valid {
+			page = alerts[arg.CursorID.Int32:]
+			require.True(t, arg.CursorTime.Time.Equal(alerts[arg.CursorID.Int32-1].CreatedAt))
+		}
+		return page[:min(len(page), int(arg.PageSize))], nil
+	}
+
+	got, scanned, err := listAlertsMatching(context.Background(), list, domain.ListAlertsParams{PageSize: 2, SortBy: "created_at"}, ms)
+	require.NoError(t, err)
+	require.Nil(t, scanned)
+	require.Len(t, got, 2)
+	require.Equal(t, []int32{1, 3}, []int32{got[0].ID, got[1].ID})
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	got, scanned, err = listAlertsMatching(context.Background(), list, domain.ListAlertsParams{PageSize: 5, SortBy: "created_at"}, ms)
+	require.NoError(t, err)
+	require.Nil(t, scanned)
+	require.Len(t, got, 3)
+	require.Equal(t, int32(6), got[2].ID)
+	require.Equal(t, 2, calls)
+
+	// without matchers the page is passed through untouched
+	calls = 0
+	got, scanned, err = listAlertsMatching(context.Background(), list, domain.ListAlertsParams{PageSize: 2}, nil)
+	require.NoError(t, err)
+	require.Nil(t, scanned)
+	require.Len(t, got, 2)
+	require.Equal(t, 1, calls)
+}
+
+func TestListAlertsMatchingScanLimit(t *testing.T) {
+	m, err := labels.NewMatcher(labels.MatchRegexp, "service", "api-.*")
+	require.NoError(t, err)
+	ms := labels.Matchers{m}
+
+	// one match up front, then nothing but misses
+	now := time.Now()
+	alerts := make([]*domain.Alert, 0, 50)
+	for i := range 50 {
+		service := "db"
+		if i == 0 {
+			service = "api-a"
+		}
+		alerts = append(alerts, &domain.Alert{
+			ID:        int32(i + 1),
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+			Labels:    []byte(fmt.Sprintf(`{"service":%q}`, service)),
+		})
+	}
+
+	var calls int
+	list := func(_ context.Context, arg domain.ListAlertsParams) ([]*domain.Alert, error) {
+		calls++
+		page := alerts
+		if arg.CursorID.Valid {
+			page = alerts[arg.CursorID.Int32:]
+		}
+		return page[:min(len(page), int(arg.PageSize))], nil
+	}
+
+	got, scanned, err := listAlertsMatching(context.Background(), list, domain.ListAlertsParams{PageSize: 3}, ms)
+	require.NoError(t, err)
+	require.Equal(t, matchScanPages, calls)
+	require.Len(t, got, 1)
+	require.Equal(t, int32(1), got[0].ID)
+	// the next page starts after the last alert read, not the last match
+	require.NotNil(t, scanned)
+	require.Equal(t, int32(3*matchScanPages), scanned.ID)
+}
@@ -0,0 +1,2 @@
+drop index if exists alert_updated_at_id_idx;
+drop index if exists alert_created_at_id_idx;
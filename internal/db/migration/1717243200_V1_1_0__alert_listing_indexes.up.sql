@@ -0,0 +1,2 @@
+create index alert_created_at_id_idx on alert (created_at, id);
+create index alert_updated_at_id_idx on alert (updated_at, id);
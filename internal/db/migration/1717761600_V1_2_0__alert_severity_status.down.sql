@@ -0,0 +1,5 @@
+drop index if exists alert_status_idx;
+
+alter table alert
+    drop column if exists status,
+    drop column if exists severity;
@@ -0,0 +1,7 @@
+alter table alert
+    add column severity text not null default 'warning'
+        check (severity in ('critical', 'high', 'warning', 'info')),
+    add column status   text not null default 'open'
+        check (status in ('open', 'acknowledged', 'resolved'));
+
+create index alert_status_idx on alert (status);
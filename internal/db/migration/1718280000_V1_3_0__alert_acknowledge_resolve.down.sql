@@ -0,0 +1,7 @@
+alter table alert
+    drop column if exists resolved_note,
+    drop column if exists resolved_by,
+    drop column if exists resolved_at,
+    drop column if exists acknowledged_note,
+    drop column if exists acknowledged_by,
+    drop column if exists acknowledged_at;
@@ -0,0 +1,7 @@
+alter table alert
+    add column acknowledged_at   timestamptz,
+    add column acknowledged_by   text,
+    add column acknowledged_note text,
+    add column resolved_at       timestamptz,
+    add column resolved_by       text,
+    add column resolved_note     text;
@@ -0,0 +1,7 @@
+drop index if exists alert_open_fingerprint_idx;
+
+alter table alert
+    drop column if exists last_seen_at,
+    drop column if exists occurrences,
+    drop column if exists fingerprint,
+    drop column if exists source;
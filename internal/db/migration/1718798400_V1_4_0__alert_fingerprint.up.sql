@@ -0,0 +1,17 @@
+alter table alert
+    add column source       text    not null default '',
+    add column fingerprint  text,
+    add column occurrences  integer not null default 1,
+    add column last_seen_at timestamptz;
+
+-- existing alerts keep a unique fingerprint so that duplicates created before
+-- deduplication existed do not collide on the index below
+update alert
+set fingerprint  = external_id::text,
+    last_seen_at = updated_at;
+
+alter table alert
+    alter column fingerprint set not null,
+    alter column last_seen_at set not null;
+
+create unique index alert_open_fingerprint_idx on alert (fingerprint) where status <> 'resolved';
@@ -0,0 +1,5 @@
+drop index if exists alert_labels_idx;
+
+alter table alert
+    drop column if exists annotations,
+    drop column if exists labels;
@@ -0,0 +1,5 @@
+alter table alert
+    add column labels      jsonb not null default '{}',
+    add column annotations jsonb not null default '{}';
+
+create index alert_labels_idx on alert using gin (labels jsonb_path_ops);
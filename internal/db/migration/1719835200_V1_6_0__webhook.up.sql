@@ -0,0 +1,34 @@
+create table webhook_subscription
+(
+    id          integer generated always as identity primary key,
+    external_id uuid        not null,
+    created_at  timestamptz not null,
+    updated_at  timestamptz not null,
+    url         text        not null,
+    event_types text[]      not null,
+    secret      text        not null,
+    active      boolean     not null default true,
+    unique (external_id)
+);
+
+create table webhook_delivery
+(
+    id              integer generated always as identity primary key,
+    external_id     uuid        not null,
+    subscription_id integer     not null references webhook_subscription (id) on delete cascade,
+    event_type      text        not null,
+    payload         jsonb       not null,
+    status          text        not null default 'pending'
+        check (status in ('pending', 'succeeded', 'failed')),
+    attempts        integer     not null default 0,
+    next_attempt_at timestamptz not null,
+    last_attempt_at timestamptz,
+    response_status integer,
+    last_error      text,
+    created_at      timestamptz not null,
+    delivered_at    timestamptz,
+    unique (external_id)
+);
+
+create index webhook_delivery_pending_idx on webhook_delivery (next_attempt_at) where status = 'pending';
+create index webhook_delivery_subscription_idx on webhook_delivery (subscription_id, id);
@@ -0,0 +1,14 @@
+create table alert_event
+(
+    id                bigint generated always as identity primary key,
+    external_id       uuid        not null,
+    alert_id          integer     not null,
+    alert_external_id uuid        not null,
+    event_type        text        not null,
+    payload           jsonb       not null,
+    created_at        timestamptz not null,
+    published_at      timestamptz,
+    unique (external_id)
+);
+
+create index alert_event_unpublished_idx on alert_event (id) where published_at is null;
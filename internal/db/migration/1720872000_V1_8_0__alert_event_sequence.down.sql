@@ -0,0 +1,6 @@
+drop index if exists alert_event_sequence_idx;
+
+alter table alert_event
+    drop column if exists sequence;
+
+drop sequence if exists alert_event_sequence;
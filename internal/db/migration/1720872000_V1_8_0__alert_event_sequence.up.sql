@@ -0,0 +1,8 @@
+-- events are numbered as they are published, rather than by id, so that the
+-- sequence only ever grows even when transactions commit out of id order
+create sequence alert_event_sequence;
+
+alter table alert_event
+    add column sequence bigint;
+
+create unique index alert_event_sequence_idx on alert_event (sequence);
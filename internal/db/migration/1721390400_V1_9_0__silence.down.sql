@@ -0,0 +1,5 @@
+alter table alert
+    drop column if exists silenced_until,
+    drop column if exists silence_id;
+
+drop table if exists silence;
@@ -0,0 +1,25 @@
+create table silence
+(
+    id          integer generated always as identity primary key,
+    external_id uuid        not null,
+    created_at  timestamptz not null,
+    updated_at  timestamptz not null,
+    starts_at   timestamptz not null,
+    ends_at     timestamptz not null,
+    matchers    jsonb       not null default '[]',
+    severities  text[]      not null default '{}',
+    created_by  text        not null,
+    comment     text        not null,
+    unique (external_id),
+    check (ends_at > starts_at)
+);
+
+create index silence_ends_at_idx on silence (ends_at);
+
+-- silenced_until is copied from the silence so that reads need no join; it is
+-- kept in step when the silence is changed or removed
+alter table alert
+    add column silence_id     integer references silence (id) on delete set null,
+    add column silenced_until timestamptz;
+
+create index alert_silence_idx on alert (silence_id) where silence_id is not null;
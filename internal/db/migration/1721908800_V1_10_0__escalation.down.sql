@@ -0,0 +1,5 @@
+alter table alert_event
+    drop column if exists detail;
+
+drop table if exists alert_escalation;
+drop table if exists escalation_policy;
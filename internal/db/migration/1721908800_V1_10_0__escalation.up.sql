@@ -0,0 +1,32 @@
+create table escalation_policy
+(
+    id          integer generated always as identity primary key,
+    external_id uuid        not null,
+    created_at  timestamptz not null,
+    updated_at  timestamptz not null,
+    name        text        not null,
+    matchers    jsonb       not null default '[]',
+    severities  text[]      not null default '{}',
+    steps       jsonb       not null,
+    unique (external_id)
+);
+
+-- one row per escalating alert; step is the next step to run, and
+-- next_escalation_at is null once the policy has run out of steps
+create table alert_escalation
+(
+    alert_id           integer     not null primary key references alert (id) on delete cascade,
+    policy_id          integer     not null references escalation_policy (id) on delete cascade,
+    step               integer     not null default 0,
+    next_escalation_at timestamptz,
+    last_escalated_at  timestamptz,
+    created_at         timestamptz not null
+);
+
+create index alert_escalation_due_idx on alert_escalation (next_escalation_at) where next_escalation_at is not null;
+create index alert_escalation_policy_idx on alert_escalation (policy_id);
+
+-- event-specific data, such as the escalation step that raised an
+-- alert.escalated event
+alter table alert_event
+    add column detail jsonb;
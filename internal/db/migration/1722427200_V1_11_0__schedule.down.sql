@@ -0,0 +1,2 @@
+drop table if exists schedule_override;
+drop table if exists schedule;
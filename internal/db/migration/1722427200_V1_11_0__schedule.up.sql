@@ -0,0 +1,29 @@
+-- layers is an ordered list of rotations; a later layer takes precedence over
+-- the ones before it once it has started
+create table schedule
+(
+    id          integer generated always as identity primary key,
+    external_id uuid        not null,
+    created_at  timestamptz not null,
+    updated_at  timestamptz not null,
+    name        text        not null,
+    time_zone   text        not null,
+    layers      jsonb       not null,
+    unique (external_id)
+);
+
+create table schedule_override
+(
+    id          integer generated always as identity primary key,
+    external_id uuid        not null,
+    schedule_id integer     not null references schedule (id) on delete cascade,
+    created_at  timestamptz not null,
+    starts_at   timestamptz not null,
+    ends_at     timestamptz not null,
+    user_name   text        not null,
+    created_by  text        not null,
+    check (ends_at > starts_at),
+    unique (external_id)
+);
+
+create index schedule_override_schedule_idx on schedule_override (schedule_id, ends_at);
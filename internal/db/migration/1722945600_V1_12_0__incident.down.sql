@@ -0,0 +1,7 @@
+drop index if exists alert_incident_idx;
+
+alter table alert
+    drop column if exists incident_id;
+
+drop table if exists incident_timeline;
+drop table if exists incident;
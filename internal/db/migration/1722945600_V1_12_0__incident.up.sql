@@ -0,0 +1,37 @@
+-- group_key identifies the group-by label values shared by the member alerts;
+-- an open incident takes new alerts while last_alert_at is within the window
+create table incident
+(
+    id            integer generated always as identity primary key,
+    external_id   uuid        not null,
+    created_at    timestamptz not null,
+    updated_at    timestamptz not null,
+    group_key     text        not null,
+    group_labels  jsonb       not null,
+    status        text        not null default 'open'
+        check (status in ('open', 'resolved')),
+    last_alert_at timestamptz not null,
+    resolved_at   timestamptz,
+    resolved_by   text,
+    unique (external_id)
+);
+
+create index incident_open_group_idx on incident (group_key, last_alert_at) where status = 'open';
+
+create table incident_timeline
+(
+    id          bigint generated always as identity primary key,
+    incident_id integer     not null references incident (id) on delete cascade,
+    created_at  timestamptz not null,
+    entry_type  text        not null,
+    alert_id    integer references alert (id) on delete set null,
+    actor       text,
+    note        text
+);
+
+create index incident_timeline_incident_idx on incident_timeline (incident_id, id);
+
+alter table alert
+    add column incident_id integer references incident (id) on delete set null;
+
+create index alert_incident_idx on alert (incident_id) where incident_id is not null;
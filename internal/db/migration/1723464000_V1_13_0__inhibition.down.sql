@@ -0,0 +1,4 @@
+drop index if exists alert_inhibited_by_idx;
+
+alter table alert
+    drop column if exists inhibited_by;
@@ -0,0 +1,5 @@
+-- the firing alert that holds this one back under an inhibition rule
+alter table alert
+    add column inhibited_by uuid references alert (external_id) on delete set null;
+
+create index alert_inhibited_by_idx on alert (inhibited_by) where inhibited_by is not null;
@@ -0,0 +1,14 @@
+-- keyed by the external id rather than a foreign key so that the history of
+-- an alert outlives the alert
+create table alert_history
+(
+    id                bigint generated always as identity primary key,
+    alert_external_id uuid        not null,
+    created_at        timestamptz not null,
+    actor             text        not null,
+    action            text        not null,
+    before            jsonb,
+    after             jsonb
+);
+
+create index alert_history_alert_idx on alert_history (alert_external_id, id);
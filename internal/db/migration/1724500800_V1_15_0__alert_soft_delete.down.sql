@@ -0,0 +1,12 @@
+drop index if exists alert_deleted_at_idx;
+
+-- deleted alerts could collide with open ones on the narrower index
+delete from alert
+where deleted_at is not null;
+
+drop index if exists alert_open_fingerprint_idx;
+create unique index alert_open_fingerprint_idx on alert (fingerprint) where status <> 'resolved';
+
+alter table alert
+    drop column if exists deleted_by,
+    drop column if exists deleted_at;
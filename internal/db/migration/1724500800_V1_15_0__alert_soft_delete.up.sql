@@ -0,0 +1,11 @@
+-- deleted alerts are kept, hidden, until they are purged after the retention
+-- period
+alter table alert
+    add column deleted_at timestamptz,
+    add column deleted_by text;
+
+-- a deleted alert must not hold on to its fingerprint
+drop index if exists alert_open_fingerprint_idx;
+create unique index alert_open_fingerprint_idx on alert (fingerprint) where status <> 'resolved' and deleted_at is null;
+
+create index alert_deleted_at_idx on alert (deleted_at) where deleted_at is not null;
@@ -0,0 +1,2 @@
+alter table alert
+    drop column if exists version;
@@ -0,0 +1,4 @@
+-- bumped by every change made through the API so that writes can be made
+-- conditional on the version the client last saw
+alter table alert
+    add column version integer not null default 1;
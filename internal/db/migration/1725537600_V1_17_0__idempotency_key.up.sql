@@ -0,0 +1,17 @@
+-- the response to a request made with an Idempotency-Key, replayed when the
+-- request is retried. The response columns stay null while the first request
+-- is still being handled.
+create table idempotency_key
+(
+    actor            text        not null,
+    key              text        not null,
+    request_hash     text        not null,
+    created_at       timestamptz not null,
+    expires_at       timestamptz not null,
+    response_status  integer,
+    response_headers jsonb,
+    response_body    bytea,
+    primary key (actor, key)
+);
+
+create index idempotency_key_expires_at_idx on idempotency_key (expires_at);
@@ -0,0 +1,11 @@
+-- API users kept in the database rather than in the config. password_hash is
+-- a bcrypt or argon2id hash, as printed by cmd/hash-password.
+create table api_user
+(
+    id            serial primary key,
+    username      text        not null unique,
+    password_hash text        not null,
+    admin         boolean     not null default false,
+    created_at    timestamptz not null default now(),
+    disabled_at   timestamptz
+);
@@ -0,0 +1,16 @@
+-- API keys for integrations. Only a SHA-256 hash of the key is kept; prefix
+-- is the public part of the key that finds its row.
+create table api_key
+(
+    id           serial primary key,
+    external_id  uuid        not null unique,
+    name         text        not null,
+    prefix       text        not null unique,
+    key_hash     text        not null,
+    scopes       text[]      not null,
+    created_at   timestamptz not null,
+    created_by   text        not null,
+    expires_at   timestamptz,
+    last_used_at timestamptz,
+    revoked_at   timestamptz
+);
@@ -0,0 +1,8 @@
+alter table api_user
+    add column admin boolean not null default false;
+
+update api_user
+set admin = role = 'admin';
+
+alter table api_user
+    drop column role;
@@ -0,0 +1,12 @@
+-- API users get one of the viewer, responder and admin roles in place of the
+-- admin flag. Existing users keep what they could do: admins stay admins and
+-- everyone else becomes a responder.
+alter table api_user
+    add column role text not null default 'viewer'
+        check (role in ('viewer', 'responder', 'admin'));
+
+update api_user
+set role = case when admin then 'admin' else 'responder' end;
+
+alter table api_user
+    drop column admin;
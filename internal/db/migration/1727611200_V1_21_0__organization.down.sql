@@ -0,0 +1,152 @@
+drop policy if exists incident_timeline_org_isolation on incident_timeline;
+
+alter table incident_timeline
+    no force row level security,
+    disable row level security;
+
+drop policy if exists alert_escalation_org_isolation on alert_escalation;
+
+alter table alert_escalation
+    no force row level security,
+    disable row level security;
+
+drop policy if exists alert_event_org_isolation on alert_event;
+
+alter table alert_event
+    no force row level security,
+    disable row level security;
+
+drop policy if exists idempotency_key_org_isolation on idempotency_key;
+
+alter table idempotency_key
+    no force row level security,
+    disable row level security;
+
+drop policy if exists webhook_delivery_org_isolation on webhook_delivery;
+
+alter table webhook_delivery
+    no force row level security,
+    disable row level security;
+
+drop policy if exists webhook_subscription_org_isolation on webhook_subscription;
+
+alter table webhook_subscription
+    no force row level security,
+    disable row level security;
+
+drop policy if exists schedule_override_org_isolation on schedule_override;
+
+alter table schedule_override
+    no force row level security,
+    disable row level security;
+
+drop policy if exists schedule_org_isolation on schedule;
+
+alter table schedule
+    no force row level security,
+    disable row level security;
+
+drop policy if exists escalation_policy_org_isolation on escalation_policy;
+
+alter table escalation_policy
+    no force row level security,
+    disable row level security;
+
+drop policy if exists silence_org_isolation on silence;
+
+alter table silence
+    no force row level security,
+    disable row level security;
+
+drop policy if exists incident_org_isolation on incident;
+
+alter table incident
+    no force row level security,
+    disable row level security;
+
+drop policy if exists alert_history_org_isolation on alert_history;
+
+alter table alert_history
+    no force row level security,
+    disable row level security;
+
+drop policy if exists alert_org_isolation on alert;
+
+alter table alert
+    no force row level security,
+    disable row level security;
+
+drop function if exists org_visible(integer);
+
+alter table incident_timeline
+    drop column org_id;
+
+alter table alert_escalation
+    drop column org_id;
+
+drop index if exists alert_event_org_sequence_idx;
+
+alter table alert_event
+    drop column org_id;
+
+-- the same actor and key may have been used in several organizations
+delete
+from idempotency_key
+where org_id <> 1;
+
+alter table idempotency_key
+    drop constraint idempotency_key_pkey,
+    add primary key (actor, key);
+
+alter table idempotency_key
+    drop column org_id;
+
+alter table api_key
+    drop column org_id;
+
+alter table webhook_delivery
+    drop column org_id;
+
+alter table webhook_subscription
+    drop column org_id;
+
+drop index if exists silence_org_id_idx;
+
+alter table silence
+    drop column org_id;
+
+alter table schedule_override
+    drop column org_id;
+
+alter table schedule
+    drop column org_id;
+
+alter table escalation_policy
+    drop column org_id;
+
+drop index if exists incident_open_group_idx;
+create index incident_open_group_idx on incident (group_key, last_alert_at) where status = 'open';
+
+update incident
+set group_key = substr(group_key, strpos(group_key, ':') + 1);
+
+alter table incident
+    drop column org_id;
+
+alter table api_user
+    drop column org_id;
+
+drop index if exists alert_history_alert_idx;
+create index alert_history_alert_idx on alert_history (alert_external_id, id);
+
+alter table alert_history
+    drop column org_id;
+
+drop index if exists alert_org_id_idx;
+drop index if exists alert_open_fingerprint_idx;
+create unique index alert_open_fingerprint_idx on alert (fingerprint) where status <> 'resolved' and deleted_at is null;
+
+alter table alert
+    drop column org_id;
+
+drop table if exists organization;
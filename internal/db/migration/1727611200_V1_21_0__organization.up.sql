@@ -0,0 +1,250 @@
+-- Organizations let several teams share one deployment. Every alert belongs
+-- to one, and so does every API user and API key, whose requests only see the
+-- alerts of their organization.
+create table organization
+(
+    id          serial primary key,
+    external_id uuid        not null unique,
+    slug        text        not null unique,
+    name        text        not null,
+    created_at  timestamptz not null default now()
+);
+
+-- what there is already moves to the default organization, which keeps id 1
+insert into organization (id, external_id, slug, name)
+values (1, gen_random_uuid(), 'default', 'Default');
+
+select setval('organization_id_seq', 1);
+
+alter table alert
+    add column org_id integer not null default 1 references organization (id);
+
+alter table alert
+    alter column org_id drop default;
+
+-- fingerprints deduplicate within an organization only
+drop index if exists alert_open_fingerprint_idx;
+create unique index alert_open_fingerprint_idx on alert (org_id, fingerprint) where status <> 'resolved' and deleted_at is null;
+
+create index alert_org_id_idx on alert (org_id, created_at);
+
+-- history outlives purged alerts, so it keeps the organization itself
+alter table alert_history
+    add column org_id integer references organization (id);
+
+update alert_history h
+set org_id = coalesce((select a.org_id from alert a where a.external_id = h.alert_external_id), 1);
+
+alter table alert_history
+    alter column org_id set not null;
+
+drop index if exists alert_history_alert_idx;
+create index alert_history_alert_idx on alert_history (org_id, alert_external_id, id);
+
+-- incidents group the alerts of one organization; the group key starts with
+-- the organization so that the advisory lock of a group is per organization
+alter table incident
+    add column org_id integer not null default 1 references organization (id);
+
+alter table incident
+    alter column org_id drop default;
+
+update incident
+set group_key = '1:' || group_key;
+
+drop index if exists incident_open_group_idx;
+create index incident_open_group_idx on incident (org_id, group_key, last_alert_at) where status = 'open';
+
+alter table escalation_policy
+    add column org_id integer not null default 1 references organization (id);
+
+alter table escalation_policy
+    alter column org_id drop default;
+
+alter table schedule
+    add column org_id integer not null default 1 references organization (id);
+
+alter table schedule
+    alter column org_id drop default;
+
+alter table schedule_override
+    add column org_id integer not null default 1 references organization (id);
+
+alter table schedule_override
+    alter column org_id drop default;
+
+alter table silence
+    add column org_id integer not null default 1 references organization (id);
+
+alter table silence
+    alter column org_id drop default;
+
+create index silence_org_id_idx on silence (org_id, ends_at);
+
+alter table webhook_subscription
+    add column org_id integer not null default 1 references organization (id);
+
+alter table webhook_subscription
+    alter column org_id drop default;
+
+alter table webhook_delivery
+    add column org_id integer not null default 1 references organization (id);
+
+alter table webhook_delivery
+    alter column org_id drop default;
+
+alter table api_user
+    add column org_id integer not null default 1 references organization (id);
+
+alter table api_key
+    add column org_id integer not null default 1 references organization (id);
+
+alter table api_key
+    alter column org_id drop default;
+
+-- usernames may repeat across organizations, so idempotency keys are kept per
+-- organization as well as per actor
+alter table idempotency_key
+    add column org_id integer not null default 1 references organization (id);
+
+alter table idempotency_key
+    alter column org_id drop default;
+
+alter table idempotency_key
+    drop constraint idempotency_key_pkey,
+    add primary key (org_id, actor, key);
+
+-- like history, outbox events are kept after their alert is purged
+alter table alert_event
+    add column org_id integer references organization (id);
+
+update alert_event e
+set org_id = coalesce((select a.org_id from alert a where a.id = e.alert_id), 1);
+
+alter table alert_event
+    alter column org_id set not null;
+
+create index alert_event_org_sequence_idx on alert_event (org_id, sequence);
+
+alter table alert_escalation
+    add column org_id integer references organization (id);
+
+update alert_escalation e
+set org_id = (select a.org_id from alert a where a.id = e.alert_id);
+
+alter table alert_escalation
+    alter column org_id set not null;
+
+alter table incident_timeline
+    add column org_id integer references organization (id);
+
+update incident_timeline t
+set org_id = (select i.org_id from incident i where i.id = t.incident_id);
+
+alter table incident_timeline
+    alter column org_id set not null;
+
+-- Row level security keeps organizations apart even if a query forgets to.
+-- The store sets app.org_id for each transaction, or app.all_orgs for the
+-- background jobs that work across organizations; a transaction with neither
+-- sees no rows at all. It is forced so that it holds for the table owner
+-- too.
+create function org_visible(org_id integer) returns boolean
+    language sql
+    stable
+as
+$$
+select current_setting('app.all_orgs', true) = 'on'
+    or org_id = nullif(current_setting('app.org_id', true), '')::integer
+$$;
+
+alter table alert
+    enable row level security,
+    force row level security;
+
+create policy alert_org_isolation on alert
+    using (org_visible(org_id));
+
+alter table alert_history
+    enable row level security,
+    force row level security;
+
+create policy alert_history_org_isolation on alert_history
+    using (org_visible(org_id));
+
+alter table incident
+    enable row level security,
+    force row level security;
+
+create policy incident_org_isolation on incident
+    using (org_visible(org_id));
+
+alter table silence
+    enable row level security,
+    force row level security;
+
+create policy silence_org_isolation on silence
+    using (org_visible(org_id));
+
+alter table escalation_policy
+    enable row level security,
+    force row level security;
+
+create policy escalation_policy_org_isolation on escalation_policy
+    using (org_visible(org_id));
+
+alter table schedule
+    enable row level security,
+    force row level security;
+
+create policy schedule_org_isolation on schedule
+    using (org_visible(org_id));
+
+alter table schedule_override
+    enable row level security,
+    force row level security;
+
+create policy schedule_override_org_isolation on schedule_override
+    using (org_visible(org_id));
+
+alter table webhook_subscription
+    enable row level security,
+    force row level security;
+
+create policy webhook_subscription_org_isolation on webhook_subscription
+    using (org_visible(org_id));
+
+alter table webhook_delivery
+    enable row level security,
+    force row level security;
+
+create policy webhook_delivery_org_isolation on webhook_delivery
+    using (org_visible(org_id));
+
+alter table idempotency_key
+    enable row level security,
+    force row level security;
+
+create policy idempotency_key_org_isolation on idempotency_key
+    using (org_visible(org_id));
+
+alter table alert_event
+    enable row level security,
+    force row level security;
+
+create policy alert_event_org_isolation on alert_event
+    using (org_visible(org_id));
+
+alter table alert_escalation
+    enable row level security,
+    force row level security;
+
+create policy alert_escalation_org_isolation on alert_escalation
+    using (org_visible(org_id));
+
+alter table incident_timeline
+    enable row level security,
+    force row level security;
+
+create policy incident_timeline_org_isolation on incident_timeline
+    using (org_visible(org_id));
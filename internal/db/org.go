@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// DefaultOrgID is the organization that held everything before there were
+// organizations. Users and tokens that name no other belong to it.
+const DefaultOrgID int32 = 1
+
+// allOrgs scopes a context to every organization.
+const allOrgs int32 = -1
+
+type orgKey struct{}
+
+// WithOrg scopes the rows that the store reads and writes with ctx to the
+// organization orgID.
+func WithOrg(ctx context.Context, orgID int32) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// WithAllOrgs lets the store see the rows of every organization with ctx, for
+// the background jobs that work across them.
+func WithAllOrgs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, orgKey{}, allOrgs)
+}
+
+// OrgFrom returns the organization ctx is scoped to, if it is scoped to one.
+func OrgFrom(ctx context.Context) (int32, bool) {
+	orgID, ok := ctx.Value(orgKey{}).(int32)
+	return orgID, ok && orgID != allOrgs
+}
+
+// beginOrgTx starts a transaction in which the row security policies let
+// through the rows of the organization of ctx, or those of all organizations.
+// A context scoped to neither sees no alerts, incidents, silences, policies,
+// schedules or webhooks at all.
+func beginOrgTx(ctx context.Context, pool *pgxpool.Pool) (pgx.Tx, error) {
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var arg domain.SetOrgContextParams
+	switch orgID, _ := ctx.Value(orgKey{}).(int32); {
+	case orgID == allOrgs:
+		arg.AllOrgs = "on"
+	case orgID > 0:
+		arg.OrgID = strconv.Itoa(int(orgID))
+	}
+
+	if err = domain.New(tx).SetOrgContext(ctx, arg); err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// orgScopedDB runs the statements made outside of a transaction with a
+// context scoped to an organization in a transaction of their own, so that
+// set_config can scope them. Statements with an unscoped context run as they
+// are.
+type orgScopedDB struct {
+	pool *pgxpool.Pool
+}
+
+func scoped(ctx context.Context) bool {
+	_, ok := ctx.Value(orgKey{}).(int32)
+	return ok
+}
+
+func (d orgScopedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if !scoped(ctx) {
+		return d.pool.Exec(ctx, sql, args...)
+	}
+
+	tx, err := beginOrgTx(ctx, d.pool)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return tag, err
+	}
+
+	return tag, tx.Commit(context.Background())
+}
+
+func (d orgScopedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if !scoped(ctx) {
+		return d.pool.Query(ctx, sql, args...)
+	}
+
+	tx, err := beginOrgTx(ctx, d.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, err
+	}
+
+	return &orgScopedRows{Rows: rows, tx: tx}, nil
+}
+
+func (d orgScopedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if !scoped(ctx) {
+		return d.pool.QueryRow(ctx, sql, args...)
+	}
+	return orgScopedRow{pool: d.pool, ctx: ctx, sql: sql, args: args}
+}
+
+func (d orgScopedDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	if !scoped(ctx) {
+		return d.pool.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	}
+
+	tx, err := beginOrgTx(ctx, d.pool)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	n, err := tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	if err != nil {
+		return n, err
+	}
+
+	return n, tx.Commit(context.Background())
+}
+
+// orgScopedRows ends the transaction of a query when its rows are closed.
+type orgScopedRows struct {
+	pgx.Rows
+	tx     pgx.Tx
+	closed bool
+	err    error
+}
+
+func (r *orgScopedRows) Close() {
+	r.Rows.Close()
+	if r.closed {
+		return
+	}
+	r.closed = true
+
+	if r.Rows.Err() != nil {
+		_ = r.tx.Rollback(context.Background())
+		return
+	}
+	r.err = r.tx.Commit(context.Background())
+}
+
+func (r *orgScopedRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Rows.Err()
+}
+
+// orgScopedRow runs its query when it is scanned, as pgx rows do.
+type orgScopedRow struct {
+	pool *pgxpool.Pool
+	ctx  context.Context
+	sql  string
+	args []interface{}
+}
+
+func (r orgScopedRow) Scan(dest ...any) error {
+	tx, err := beginOrgTx(r.ctx, r.pool)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	if err = tx.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := OrgFrom(ctx)
+	require.False(t, ok)
+	require.False(t, scoped(ctx))
+
+	orgID, ok := OrgFrom(WithOrg(ctx, 2))
+	require.True(t, ok)
+	require.Equal(t, int32(2), orgID)
+	require.True(t, scoped(WithOrg(ctx, 2)))
+
+	_, ok = OrgFrom(WithAllOrgs(ctx))
+	require.False(t, ok)
+	require.True(t, scoped(WithAllOrgs(ctx)))
+}
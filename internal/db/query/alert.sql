@@ -3,23 +3,224 @@ insert into alert (
                      external_id,
                      created_at,
                      updated_at,
-                     message
+                     message,
+                     severity,
+                     source,
+                     fingerprint,
+                     last_seen_at,
+                     labels,
+                     annotations,
+                     silence_id,
+                     silenced_until,
+                     org_id
 )
-values ($1, $2, $3, $4)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+on conflict (org_id, fingerprint) where status <> 'resolved' and deleted_at is null
+    do update set occurrences    = alert.occurrences + 1,
+                  last_seen_at   = excluded.last_seen_at,
+                  updated_at     = excluded.updated_at,
+                  silence_id     = excluded.silence_id,
+                  silenced_until = excluded.silenced_until,
+                  version        = alert.version + 1
 returning *;
 
 -- name: GetAlertByExternalID :one
 select *
 from alert
-where external_id = $1;
+where org_id = @org_id
+  and external_id = @external_id
+  and deleted_at is null;
+
+-- name: GetAlertByExternalIDIncludeDeleted :one
+select *
+from alert
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: GetUnresolvedAlertByFingerprint :one
+select *
+from alert
+where org_id = @org_id
+  and fingerprint = @fingerprint
+  and status <> 'resolved'
+  and deleted_at is null;
+
+-- name: GetAlertByIDForUpdate :one
+select *
+from alert
+where id = $1
+for update;
 
 -- name: UpdateAlertByID :one
 update alert
-set message = $1,
-    updated_at = $2
-where id = $3
+set message = @message,
+    updated_at = @updated_at,
+    severity = coalesce(sqlc.narg('severity')::text, severity),
+    status = coalesce(sqlc.narg('status')::text, status),
+    labels = coalesce(sqlc.narg('labels')::jsonb, labels),
+    annotations = coalesce(sqlc.narg('annotations')::jsonb, annotations),
+    -- reopening clears the actions taken on the resolved alert
+    acknowledged_at = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else acknowledged_at end,
+    acknowledged_by = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else acknowledged_by end,
+    acknowledged_note = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else acknowledged_note end,
+    resolved_at = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else resolved_at end,
+    resolved_by = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else resolved_by end,
+    resolved_note = case when status = 'resolved' and sqlc.narg('status')::text = 'open' then null else resolved_note end,
+    version = version + 1
+where id = @id
+  and (sqlc.narg('version')::integer is null or version = sqlc.narg('version'))
+returning *;
+
+-- name: DeleteAlertByID :one
+update alert
+set deleted_at = @deleted_at::timestamptz,
+    deleted_by = @deleted_by::text,
+    version = version + 1
+where id = @id
+  and (sqlc.narg('version')::integer is null or version = sqlc.narg('version'))
 returning *;
 
--- name: DeleteAlertByID :exec
-delete from alert
-where id = $1;
+-- name: RestoreAlertByID :one
+update alert
+set deleted_at = null,
+    deleted_by = null,
+    version = version + 1
+where id = @id
+returning *;
+
+-- name: PurgeDeletedAlerts :execrows
+delete
+from alert
+where id in (select id
+             from alert
+             where deleted_at < @deleted_before::timestamptz
+             order by deleted_at
+             limit @batch_size);
+
+-- name: ListAlerts :many
+select *
+from alert
+where org_id = @org_id
+  and (@include_deleted::boolean or deleted_at is null)
+  and (sqlc.narg('created_after')::timestamptz is null or created_at >= sqlc.narg('created_after'))
+  and (sqlc.narg('created_before')::timestamptz is null or created_at < sqlc.narg('created_before'))
+  and (sqlc.narg('updated_after')::timestamptz is null or updated_at >= sqlc.narg('updated_after'))
+  and (sqlc.narg('updated_before')::timestamptz is null or updated_at < sqlc.narg('updated_before'))
+  and (sqlc.narg('message')::text is null or message ilike '%' || sqlc.narg('message') || '%')
+  and (sqlc.narg('cursor_id')::integer is null or case
+        when @sort_by::text = 'updated_at' and @sort_desc::boolean
+            then (updated_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id'))
+        when @sort_by = 'updated_at'
+            then (updated_at, id) > (sqlc.narg('cursor_time'), sqlc.narg('cursor_id'))
+        when @sort_desc
+            then (created_at, id) < (sqlc.narg('cursor_time'), sqlc.narg('cursor_id'))
+        else (created_at, id) > (sqlc.narg('cursor_time'), sqlc.narg('cursor_id'))
+    end)
+  and (sqlc.narg('severity')::text is null or severity = sqlc.narg('severity'))
+  and (sqlc.narg('status')::text is null or status = sqlc.narg('status'))
+  and (sqlc.narg('labels_contain')::jsonb is null or labels @> sqlc.narg('labels_contain'))
+  -- regular expression matchers are applied by the caller, with Go semantics
+  and not exists (select 1
+                  from unnest(@matcher_names::text[], @matcher_types::text[], @matcher_values::text[]) as m(name, op, value)
+                  where not case m.op
+                      when '!=' then coalesce(labels ->> m.name, '') <> m.value
+                      else coalesce(labels ->> m.name, '') = m.value
+                  end)
+order by case when @sort_by = 'updated_at' and not @sort_desc then updated_at end,
+         case when @sort_by = 'updated_at' and @sort_desc then updated_at end desc,
+         case when @sort_by <> 'updated_at' and not @sort_desc then created_at end,
+         case when @sort_by <> 'updated_at' and @sort_desc then created_at end desc,
+         case when not @sort_desc then id end,
+         case when @sort_desc then id end desc
+limit @page_size;
+
+-- name: AcknowledgeAlertByID :one
+update alert
+set status = 'acknowledged',
+    acknowledged_at = @acknowledged_at::timestamptz,
+    acknowledged_by = @acknowledged_by::text,
+    acknowledged_note = sqlc.narg('acknowledged_note')::text,
+    updated_at = @acknowledged_at,
+    version = version + 1
+where id = @id
+returning *;
+
+-- name: ResolveAlertByID :one
+update alert
+set status = 'resolved',
+    resolved_at = @resolved_at::timestamptz,
+    resolved_by = @resolved_by::text,
+    resolved_note = sqlc.narg('resolved_note')::text,
+    updated_at = @resolved_at,
+    version = version + 1
+where id = @id
+returning *;
+
+-- name: UpdateAlertSilenceBySilenceID :exec
+update alert
+set silenced_until = @silenced_until,
+    version = version + 1
+where silence_id = @silence_id;
+
+-- name: ListUnresolvedAlertsMatching :many
+select *
+from alert
+where org_id = @org_id
+  and status <> 'resolved'
+  and deleted_at is null
+  and id <> all (@exclude_ids::integer[])
+  -- regular expression matchers are applied by the caller, with Go semantics
+  and not exists (select 1
+                  from unnest(@matcher_names::text[], @matcher_types::text[], @matcher_values::text[]) as m(name, op, value)
+                  where not case m.op
+                      when '!=' then coalesce(labels ->> m.name, '') <> m.value
+                      else coalesce(labels ->> m.name, '') = m.value
+                  end)
+order by id;
+
+-- name: ListAlertsInhibitedByForUpdate :many
+select *
+from alert
+where inhibited_by = @inhibited_by
+order by id
+for update;
+
+-- name: SetAlertInhibitedByID :one
+update alert
+set inhibited_by = @inhibited_by,
+    version = version + 1
+where id = @id
+returning *;
+
+-- name: CopyAlerts :copyfrom
+insert into alert (
+                     external_id,
+                     created_at,
+                     updated_at,
+                     message,
+                     severity,
+                     source,
+                     fingerprint,
+                     last_seen_at,
+                     labels,
+                     annotations,
+                     silence_id,
+                     silenced_until,
+                     org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+
+-- name: ListAlertsByExternalIDs :many
+select *
+from alert
+where org_id = @org_id
+  and external_id = any (@external_ids::uuid[])
+order by id;
+
+-- name: ListUnresolvedAlertFingerprints :many
+select fingerprint
+from alert
+where org_id = @org_id
+  and fingerprint = any (@fingerprints::text[])
+  and status <> 'resolved'
+  and deleted_at is null;
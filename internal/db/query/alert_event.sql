@@ -0,0 +1,35 @@
+-- name: CreateAlertEvent :exec
+insert into alert_event (
+                         external_id,
+                         alert_id,
+                         alert_external_id,
+                         event_type,
+                         payload,
+                         created_at,
+                         detail,
+                         org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListUnpublishedAlertEventsForUpdate :many
+select *
+from alert_event
+where published_at is null
+order by id
+limit @batch_size
+for update;
+
+-- name: MarkAlertEventPublished :one
+update alert_event
+set published_at = @published_at,
+    sequence     = nextval('alert_event_sequence')
+where id = @id
+returning sequence::bigint;
+
+-- name: ListPublishedAlertEventsAfterSequence :many
+select *
+from alert_event
+where org_id = @org_id
+  and sequence > @after_sequence::bigint
+order by sequence
+limit @page_size;
@@ -0,0 +1,18 @@
+-- name: CreateAlertHistory :exec
+insert into alert_history (
+                           alert_external_id,
+                           created_at,
+                           actor,
+                           action,
+                           before,
+                           after,
+                           org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAlertHistoryByExternalID :many
+select *
+from alert_history
+where org_id = @org_id
+  and alert_external_id = @alert_external_id
+order by id;
@@ -0,0 +1,40 @@
+-- name: CreateAPIKey :one
+insert into api_key (
+                     external_id,
+                     name,
+                     prefix,
+                     key_hash,
+                     scopes,
+                     created_at,
+                     created_by,
+                     expires_at,
+                     org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning *;
+
+-- name: GetAPIKeyByPrefix :one
+select *
+from api_key
+where prefix = $1
+  and revoked_at is null;
+
+-- name: ListAPIKeys :many
+select *
+from api_key
+where org_id = $1
+  and revoked_at is null
+order by created_at desc, id desc;
+
+-- name: RevokeAPIKeyByExternalID :one
+update api_key
+set revoked_at = @revoked_at
+where org_id = @org_id
+  and external_id = @external_id
+  and revoked_at is null
+returning *;
+
+-- name: TouchAPIKey :exec
+update api_key
+set last_used_at = @last_used_at
+where id = @id;
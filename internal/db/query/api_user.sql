@@ -0,0 +1,5 @@
+-- name: GetAPIUserByUsername :one
+select *
+from api_user
+where username = $1
+  and disabled_at is null;
@@ -0,0 +1,78 @@
+-- name: CreateEscalationPolicy :one
+insert into escalation_policy (
+                               external_id,
+                               created_at,
+                               updated_at,
+                               name,
+                               matchers,
+                               severities,
+                               steps,
+                               org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning *;
+
+-- name: GetEscalationPolicyByExternalID :one
+select *
+from escalation_policy
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: GetEscalationPolicyByID :one
+select *
+from escalation_policy
+where id = $1;
+
+-- name: ListEscalationPolicies :many
+select *
+from escalation_policy
+where org_id = $1
+order by id;
+
+-- name: UpdateEscalationPolicyByID :one
+update escalation_policy
+set updated_at = @updated_at,
+    name       = @name,
+    matchers   = @matchers,
+    severities = @severities,
+    steps      = @steps
+where org_id = @org_id
+  and id = @id
+returning *;
+
+-- name: DeleteEscalationPolicyByID :exec
+delete
+from escalation_policy
+where org_id = @org_id
+  and id = @id;
+
+-- name: CreateAlertEscalation :exec
+insert into alert_escalation (
+                              alert_id,
+                              policy_id,
+                              next_escalation_at,
+                              created_at,
+                              org_id
+)
+values ($1, $2, $3, $4, $5)
+on conflict (alert_id) do nothing;
+
+-- name: ClaimDueAlertEscalations :many
+select e.*
+from alert_escalation e
+         join alert a on a.id = e.alert_id
+where e.next_escalation_at <= @now
+  and a.status = 'open'
+  and (a.silenced_until is null or a.silenced_until <= @now)
+  and a.inhibited_by is null
+  and a.deleted_at is null
+order by e.next_escalation_at, e.alert_id
+limit @batch_size
+for update of e skip locked;
+
+-- name: AdvanceAlertEscalation :exec
+update alert_escalation
+set step               = @step,
+    next_escalation_at = sqlc.narg('next_escalation_at'),
+    last_escalated_at  = @last_escalated_at::timestamptz
+where alert_id = @alert_id;
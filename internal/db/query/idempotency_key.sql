@@ -0,0 +1,48 @@
+-- name: ReserveIdempotencyKey :one
+-- takes over an expired key, but returns no row while the key is live
+insert into idempotency_key (
+                             actor,
+                             key,
+                             request_hash,
+                             created_at,
+                             expires_at,
+                             org_id
+)
+values ($1, $2, $3, $4, $5, $6)
+on conflict (org_id, actor, key) do update
+    set request_hash     = excluded.request_hash,
+        created_at       = excluded.created_at,
+        expires_at       = excluded.expires_at,
+        response_status  = null,
+        response_headers = null,
+        response_body    = null
+    where idempotency_key.expires_at <= excluded.created_at
+returning *;
+
+-- name: GetIdempotencyKey :one
+select *
+from idempotency_key
+where org_id = $1
+  and actor = $2
+  and key = $3;
+
+-- name: CompleteIdempotencyKey :exec
+update idempotency_key
+set response_status  = @response_status::integer,
+    response_headers = @response_headers::jsonb,
+    response_body    = @response_body::bytea
+where org_id = @org_id
+  and actor = @actor
+  and key = @key;
+
+-- name: DeleteIdempotencyKey :exec
+delete
+from idempotency_key
+where org_id = $1
+  and actor = $2
+  and key = $3;
+
+-- name: PurgeExpiredIdempotencyKeys :execrows
+delete
+from idempotency_key
+where expires_at < @now;
@@ -0,0 +1,104 @@
+-- name: LockIncidentGroup :exec
+select pg_advisory_xact_lock(@org_id::integer, hashtext(@group_key::text));
+
+-- name: GetOpenIncidentByGroupKey :one
+select *
+from incident
+where org_id = @org_id
+  and group_key = @group_key
+  and status = 'open'
+  and last_alert_at >= @since
+order by last_alert_at desc, id desc
+limit 1;
+
+-- name: CreateIncident :one
+insert into incident (
+                      external_id,
+                      created_at,
+                      updated_at,
+                      group_key,
+                      group_labels,
+                      last_alert_at,
+                      org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7)
+returning *;
+
+-- name: TouchIncident :exec
+update incident
+set last_alert_at = @last_alert_at,
+    updated_at    = @last_alert_at
+where id = @id;
+
+-- name: GetIncidentByExternalID :one
+select *
+from incident
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: GetIncidentByIDForUpdate :one
+select *
+from incident
+where org_id = @org_id
+  and id = @id
+for update;
+
+-- name: ListIncidents :many
+select *
+from incident
+where org_id = @org_id
+  and (sqlc.narg('status')::text is null or status = sqlc.narg('status'))
+order by last_alert_at desc, id desc
+limit @page_size;
+
+-- name: ResolveIncidentByID :one
+update incident
+set status      = 'resolved',
+    resolved_at = @resolved_at::timestamptz,
+    resolved_by = @resolved_by::text,
+    updated_at  = @resolved_at
+where org_id = @org_id
+  and id = @id
+returning *;
+
+-- name: SetAlertIncidentByID :one
+update alert
+set incident_id = @incident_id,
+    version = version + 1
+where id = @id
+returning *;
+
+-- name: ListAlertExternalIDsByIncidentID :many
+select external_id
+from alert
+where incident_id = @incident_id
+  and deleted_at is null
+order by id;
+
+-- name: ListUnresolvedAlertsByIncidentIDForUpdate :many
+select *
+from alert
+where incident_id = @incident_id
+  and status <> 'resolved'
+  and deleted_at is null
+order by id
+for update;
+
+-- name: CreateIncidentTimelineEntry :exec
+insert into incident_timeline (
+                               incident_id,
+                               created_at,
+                               entry_type,
+                               alert_id,
+                               actor,
+                               note,
+                               org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListIncidentTimeline :many
+select t.*, a.external_id as alert_external_id
+from incident_timeline t
+         left join alert a on a.id = t.alert_id
+where t.incident_id = @incident_id
+order by t.id;
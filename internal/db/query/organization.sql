@@ -0,0 +1,8 @@
+-- name: GetOrganizationBySlug :one
+select *
+from organization
+where slug = $1;
+
+-- name: SetOrgContext :exec
+select set_config('app.org_id', @org_id::text, true),
+       set_config('app.all_orgs', @all_orgs::text, true);
@@ -0,0 +1,81 @@
+-- name: CreateSchedule :one
+insert into schedule (
+                      external_id,
+                      created_at,
+                      updated_at,
+                      name,
+                      time_zone,
+                      layers,
+                      org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7)
+returning *;
+
+-- name: GetScheduleByExternalID :one
+select *
+from schedule
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: ListSchedules :many
+select *
+from schedule
+where org_id = $1
+order by id;
+
+-- name: UpdateScheduleByID :one
+update schedule
+set updated_at = @updated_at,
+    name       = @name,
+    time_zone  = @time_zone,
+    layers     = @layers
+where org_id = @org_id
+  and id = @id
+returning *;
+
+-- name: DeleteScheduleByID :exec
+delete
+from schedule
+where org_id = @org_id
+  and id = @id;
+
+-- name: CreateScheduleOverride :one
+insert into schedule_override (
+                               external_id,
+                               schedule_id,
+                               created_at,
+                               starts_at,
+                               ends_at,
+                               user_name,
+                               created_by,
+                               org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning *;
+
+-- name: GetScheduleOverrideByExternalID :one
+select *
+from schedule_override
+where schedule_id = @schedule_id
+  and external_id = @external_id;
+
+-- name: ListScheduleOverrides :many
+select *
+from schedule_override
+where schedule_id = @schedule_id
+  and ends_at > @ends_after
+order by starts_at, id;
+
+-- name: ListScheduleOverridesAt :many
+select *
+from schedule_override
+where schedule_id = @schedule_id
+  and starts_at <= @at
+  and ends_at > @at
+order by created_at desc, id desc;
+
+-- name: DeleteScheduleOverrideByID :exec
+delete
+from schedule_override
+where org_id = @org_id
+  and id = @id;
@@ -0,0 +1,64 @@
+-- name: CreateSilence :one
+insert into silence (
+                     external_id,
+                     created_at,
+                     updated_at,
+                     starts_at,
+                     ends_at,
+                     matchers,
+                     severities,
+                     created_by,
+                     comment,
+                     org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+returning *;
+
+-- name: GetSilenceByExternalID :one
+select *
+from silence
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: GetSilenceByIDForUpdate :one
+select *
+from silence
+where org_id = @org_id
+  and id = @id
+for update;
+
+-- name: ListSilences :many
+select *
+from silence
+where org_id = @org_id
+  and (sqlc.narg('state')::text is null
+    or (sqlc.narg('state') = 'pending' and starts_at > @now)
+    or (sqlc.narg('state') = 'active' and starts_at <= @now and ends_at > @now)
+    or (sqlc.narg('state') = 'expired' and ends_at <= @now))
+order by starts_at desc, id desc;
+
+-- name: ListActiveSilences :many
+select *
+from silence
+where org_id = @org_id
+  and starts_at <= @now
+  and ends_at > @now
+order by ends_at desc, id;
+
+-- name: UpdateSilenceByID :one
+update silence
+set updated_at = @updated_at,
+    starts_at  = @starts_at,
+    ends_at    = @ends_at,
+    matchers   = @matchers,
+    severities = @severities,
+    comment    = @comment
+where org_id = @org_id
+  and id = @id
+returning *;
+
+-- name: DeleteSilenceByID :exec
+delete
+from silence
+where org_id = @org_id
+  and id = @id;
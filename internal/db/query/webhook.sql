@@ -0,0 +1,86 @@
+-- name: CreateWebhookSubscription :one
+insert into webhook_subscription (
+                                  external_id,
+                                  created_at,
+                                  updated_at,
+                                  url,
+                                  event_types,
+                                  secret,
+                                  org_id
+)
+values ($1, $2, $3, $4, $5, $6, $7)
+returning *;
+
+-- name: GetWebhookSubscriptionByExternalID :one
+select *
+from webhook_subscription
+where org_id = @org_id
+  and external_id = @external_id;
+
+-- name: GetWebhookSubscriptionByID :one
+select *
+from webhook_subscription
+where id = $1;
+
+-- name: ListWebhookSubscriptions :many
+select *
+from webhook_subscription
+where org_id = $1
+order by id;
+
+-- name: ListActiveWebhookSubscriptionsForEvent :many
+select *
+from webhook_subscription
+where org_id = @org_id
+  and active
+  and @event_type::text = any (event_types)
+order by id;
+
+-- name: DeleteWebhookSubscriptionByID :exec
+delete from webhook_subscription
+where org_id = @org_id
+  and id = @id;
+
+-- name: CreateWebhookDelivery :one
+insert into webhook_delivery (
+                              external_id,
+                              subscription_id,
+                              event_type,
+                              payload,
+                              next_attempt_at,
+                              created_at,
+                              org_id
+)
+values ($1, $2, $3, $4, $5, $5, $6)
+returning *;
+
+-- name: ClaimDueWebhookDeliveries :many
+update webhook_delivery
+set next_attempt_at = @lease_until
+where id in (select id
+             from webhook_delivery
+             where status = 'pending'
+               and next_attempt_at <= @now
+             order by next_attempt_at, id
+             limit @batch_size for update skip locked)
+returning *;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+update webhook_delivery
+set status          = @status,
+    attempts        = attempts + 1,
+    next_attempt_at = @next_attempt_at,
+    last_attempt_at = @attempted_at::timestamptz,
+    response_status = sqlc.narg('response_status'),
+    last_error      = sqlc.narg('last_error'),
+    delivered_at    = case when @status = 'succeeded' then @attempted_at end
+where id = @id;
+
+-- name: ListWebhookDeliveriesBySubscriptionID :many
+select *
+from webhook_delivery
+where subscription_id = @subscription_id
+  and (sqlc.narg('status')::text is null or status = sqlc.narg('status'))
+  and (sqlc.narg('before_id')::integer is null or id < sqlc.narg('before_id'))
+order by id desc
+limit @page_size;
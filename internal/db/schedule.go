@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+)
+
+const (
+	LayerStartLayout  = "2006-01-02"
+	HandoffTimeLayout = "15:04"
+)
+
+// scheduleTargetPrefix marks an escalation step target that pages whoever is
+// on call for a schedule.
+const scheduleTargetPrefix = "schedule:"
+
+// ScheduleLayer hands the shift to the next of Users every day or every week
+// at HandoffTime, starting on the Start date. Dates and times are in the time
+// zone of the schedule, and a weekly rotation hands off on the weekday of
+// Start.
+type ScheduleLayer struct {
+	Name        string   `json:"name"`
+	Rotation    string   `json:"rotation"`
+	Start       string   `json:"start"`
+	HandoffTime string   `json:"handoffTime"`
+	Users       []string `json:"users"`
+}
+
+// OnCall is the user on call for a schedule at a point in time, and the shift
+// or override that put them there.
+type OnCall struct {
+	User     string
+	Layer    string
+	Override *domain.ScheduleOverride
+	Start    time.Time
+	End      time.Time
+}
+
+func ScheduleLayers(schedule *domain.Schedule) ([]ScheduleLayer, error) {
+	var layers []ScheduleLayer
+	if err := json.Unmarshal(schedule.Layers, &layers); err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
+// first returns the first handoff of the layer.
+func (layer ScheduleLayer) first(loc *time.Location) (time.Time, error) {
+	start, err := time.ParseInLocation(LayerStartLayout, layer.Start, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	handoff, err := time.Parse(HandoffTimeLayout, layer.HandoffTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(start.Year(), start.Month(), start.Day(), handoff.Hour(), handoff.Minute(), 0, 0, loc), nil
+}
+
+// Shift returns the user on call for the layer at the given time along with
+// the start and end of their shift. ok is false before the layer has started.
+//
+// Handoffs are counted in calendar days rather than elapsed hours, so they
+// stay at the same wall clock time across daylight saving changes.
+func (layer ScheduleLayer) Shift(at time.Time, loc *time.Location) (user string, start, end time.Time, ok bool, err error) {
+	first, err := layer.first(loc)
+	if err != nil || at.Before(first) || len(layer.Users) == 0 {
+		return "", time.Time{}, time.Time{}, false, err
+	}
+
+	length := 1
+	if layer.Rotation == RotationWeekly {
+		length = 7
+	}
+
+	local := at.In(loc)
+	days := civilDays(first, local)
+	if local.Before(time.Date(local.Year(), local.Month(), local.Day(), first.Hour(), first.Minute(), 0, 0, loc)) {
+		days--
+	}
+
+	shift := days / length
+	start = time.Date(first.Year(), first.Month(), first.Day()+shift*length, first.Hour(), first.Minute(), 0, 0, loc)
+	end = time.Date(first.Year(), first.Month(), first.Day()+(shift+1)*length, first.Hour(), first.Minute(), 0, 0, loc)
+	return layer.Users[shift%len(layer.Users)], start, end, true, nil
+}
+
+// civilDays counts the calendar days from the date of a to the date of b.
+func civilDays(a, b time.Time) int {
+	from := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// ResolveOnCall works out who is on call for a schedule at the given time.
+// The most recently created override covering the time wins, then the last
+// layer that has started. It returns nil when nobody is on call.
+func ResolveOnCall(schedule *domain.Schedule, overrides []*domain.ScheduleOverride, at time.Time) (*OnCall, error) {
+	for _, override := range overrides {
+		if !at.Before(override.StartsAt) && at.Before(override.EndsAt) {
+			return &OnCall{
+				User:     override.UserName,
+				Override: override,
+				Start:    override.StartsAt,
+				End:      override.EndsAt,
+			}, nil
+		}
+	}
+
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := ScheduleLayers(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		user, start, end, ok, err := layers[i].Shift(at, loc)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		// a layer above this one that has yet to start cuts the shift short
+		for _, above := range layers[i+1:] {
+			if first, err := above.first(loc); err == nil && first.Before(end) {
+				end = first
+			}
+		}
+
+		return &OnCall{User: user, Layer: layers[i].Name, Start: start, End: end}, nil
+	}
+	return nil, nil
+}
+
+// ScheduleOnCall loads the overrides of a schedule and resolves who is on call
+// at the given time.
+func ScheduleOnCall(ctx context.Context, q domain.Querier, schedule *domain.Schedule, at time.Time) (*OnCall, error) {
+	overrides, err := q.ListScheduleOverridesAt(ctx, domain.ListScheduleOverridesAtParams{
+		ScheduleID: schedule.ID,
+		At:         at,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ResolveOnCall(schedule, overrides, at)
+}
+
+// ScheduleTarget returns the schedule an escalation step target refers to, in
+// the form schedule:<externalId>.
+func ScheduleTarget(target string) (uuid.UUID, bool) {
+	if !strings.HasPrefix(target, scheduleTargetPrefix) {
+		return uuid.Nil, false
+	}
+	id, err := uuid.FromString(strings.TrimPrefix(target, scheduleTargetPrefix))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestScheduleLayerShift(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	daily := ScheduleLayer{
+		Name:        "primary",
+		Rotation:    RotationDaily,
+		Start:       "2024-03-08",
+		HandoffTime: "09:00",
+		Users:       []string{"alice", "bob", "carol"},
+	}
+
+	testCases := []struct {
+		name  string
+		layer ScheduleLayer
+		at    time.Time
+		user  string
+		start time.Time
+		end   time.Time
+		ok    bool
+	}{
+		{
+			name:  "before the first handoff",
+			layer: daily,
+			at:    time.Date(2024, 3, 8, 8, 59, 0, 0, loc),
+		},
+		{
+			name:  "first shift",
+			layer: daily,
+			at:    time.Date(2024, 3, 8, 9, 0, 0, 0, loc),
+			user:  "alice",
+			start: time.Date(2024, 3, 8, 9, 0, 0, 0, loc),
+			end:   time.Date(2024, 3, 9, 9, 0, 0, 0, loc),
+			ok:    true,
+		},
+		{
+			name:  "before the handoff of the day",
+			layer: daily,
+			at:    time.Date(2024, 3, 9, 8, 0, 0, 0, loc),
+			user:  "alice",
+			start: time.Date(2024, 3, 8, 9, 0, 0, 0, loc),
+			end:   time.Date(2024, 3, 9, 9, 0, 0, 0, loc),
+			ok:    true,
+		},
+		{
+			// clocks go forward on 2024-03-10, so this shift is 23 hours long
+			name:  "across daylight saving",
+			layer: daily,
+			at:    time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC),
+			user:  "carol",
+			start: time.Date(2024, 3, 10, 9, 0, 0, 0, loc),
+			end:   time.Date(2024, 3, 11, 9, 0, 0, 0, loc),
+			ok:    true,
+		},
+		{
+			name:  "wraps around",
+			layer: daily,
+			at:    time.Date(2024, 3, 11, 10, 0, 0, 0, loc),
+			user:  "alice",
+			start: time.Date(2024, 3, 11, 9, 0, 0, 0, loc),
+			end:   time.Date(2024, 3, 12, 9, 0, 0, 0, loc),
+			ok:    true,
+		},
+		{
+			name: "weekly",
+			layer: ScheduleLayer{
+				Rotation:    RotationWeekly,
+				Start:       "2024-07-01",
+				HandoffTime: "10:00",
+				Users:       []string{"alice", "bob"},
+			},
+			at:    time.Date(2024, 7, 15, 9, 0, 0, 0, loc),
+			user:  "bob",
+			start: time.Date(2024, 7, 8, 10, 0, 0, 0, loc),
+			end:   time.Date(2024, 7, 15, 10, 0, 0, 0, loc),
+			ok:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			user, start, end, ok, err := testCase.layer.Shift(testCase.at, loc)
+			require.NoError(t, err)
+			require.Equal(t, testCase.ok, ok)
+			require.Equal(t, testCase.user, user)
+			require.True(t, testCase.start.Equal(start), "start %s", start)
+			require.True(t, testCase.end.Equal(end), "end %s", end)
+		})
+	}
+}
+
+func TestResolveOnCall(t *testing.T) {
+	schedule := &domain.Schedule{
+		TimeZone: "Europe/London",
+		Layers: []byte(`[
+			{"name":"primary","rotation":"weekly","start":"2024-07-01","handoffTime":"09:00","users":["alice","bob"]},
+			{"name":"cover","rotation":"daily","start":"2024-07-10","handoffTime":"09:00","users":["dave"]}
+		]`),
+	}
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	require.NoError(t, err)
+
+	t.Run("lower layer cut short by a later layer", func(t *testing.T) {
+		onCall, err := ResolveOnCall(schedule, nil, time.Date(2024, 7, 9, 12, 0, 0, 0, loc))
+		require.NoError(t, err)
+		require.Equal(t, "bob", onCall.User)
+		require.Equal(t, "primary", onCall.Layer)
+		require.True(t, time.Date(2024, 7, 10, 9, 0, 0, 0, loc).Equal(onCall.End))
+	})
+
+	t.Run("later layer takes precedence", func(t *testing.T) {
+		onCall, err := ResolveOnCall(schedule, nil, time.Date(2024, 7, 10, 12, 0, 0, 0, loc))
+		require.NoError(t, err)
+		require.Equal(t, "dave", onCall.User)
+		require.Equal(t, "cover", onCall.Layer)
+	})
+
+	t.Run("override takes precedence", func(t *testing.T) {
+		override := &domain.ScheduleOverride{
+			StartsAt: time.Date(2024, 7, 10, 0, 0, 0, 0, loc),
+			EndsAt:   time.Date(2024, 7, 11, 0, 0, 0, 0, loc),
+			UserName: "erin",
+		}
+		onCall, err := ResolveOnCall(schedule, []*domain.ScheduleOverride{override}, time.Date(2024, 7, 10, 12, 0, 0, 0, loc))
+		require.NoError(t, err)
+		require.Equal(t, "erin", onCall.User)
+		require.Equal(t, override, onCall.Override)
+	})
+
+	t.Run("nobody on call", func(t *testing.T) {
+		onCall, err := ResolveOnCall(schedule, nil, time.Date(2024, 6, 1, 0, 0, 0, 0, loc))
+		require.NoError(t, err)
+		require.Nil(t, onCall)
+	})
+}
+
+func TestScheduleTarget(t *testing.T) {
+	_, ok := ScheduleTarget("primary-oncall")
+	require.False(t, ok)
+
+	_, ok = ScheduleTarget("schedule:not-a-uuid")
+	require.False(t, ok)
+
+	id, ok := ScheduleTarget("schedule:0b6e3a5e-3f7a-4a7c-9d2e-0c6f1f1b6a10")
+	require.True(t, ok)
+	require.Equal(t, "0b6e3a5e-3f7a-4a7c-9d2e-0c6f1f1b6a10", id.String())
+}
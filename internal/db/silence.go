@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
+)
+
+// SilenceMatchers decodes the label matchers stored with a silence.
+func SilenceMatchers(silence *domain.Silence) (labels.Matchers, error) {
+	return decodeMatchers(silence.Matchers)
+}
+
+// SilenceMatches reports whether a silence applies to an alert with the given
+// severity and labels. A silence with no severities matches any severity.
+func SilenceMatches(silence *domain.Silence, severity string, ls map[string]string) (bool, error) {
+	return matchAlert(silence.Matchers, silence.Severities, severity, ls)
+}
+
+// AlertSilenced reports whether the alert was silenced at the given time.
+func AlertSilenced(alert *domain.Alert, at time.Time) bool {
+	return alert.SilencedUntil.Valid && at.Before(alert.SilencedUntil.Time)
+}
+
+// silenceAlert points a new alert at the active silence of its organization
+// that matches it and lasts longest, if any.
+func silenceAlert(ctx context.Context, qtx *domain.Queries, arg *domain.CreateAlertParams) error {
+	silences, err := qtx.ListActiveSilences(ctx, domain.ListActiveSilencesParams{
+		OrgID: arg.OrgID,
+		Now:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	silence, err := longestSilence(silences, arg)
+	if err != nil || silence == nil {
+		return err
+	}
+
+	arg.SilenceID = pgtype.Int4{Int32: silence.ID, Valid: true}
+	arg.SilencedUntil = pgtype.Timestamptz{Time: silence.EndsAt, Valid: true}
+	return nil
+}
+
+// longestSilence returns the first of silences, ordered by ends_at desc, that
+// belongs to the organization of the alert and matches it.
+func longestSilence(silences []*domain.Silence, arg *domain.CreateAlertParams) (*domain.Silence, error) {
+	if len(silences) == 0 {
+		return nil, nil
+	}
+
+	ls, err := decodeLabels(arg.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, silence := range silences {
+		if silence.OrgID != arg.OrgID {
+			continue
+		}
+
+		ok, err := SilenceMatches(silence, arg.Severity, ls)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return silence, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestSilenceMatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		silence  *domain.Silence
+		severity string
+		labels   map[string]string
+		want     bool
+	}{
+		{
+			name:     "matching labels",
+			silence:  &domain.Silence{Matchers: []byte(`[{"name":"env","type":"=","value":"prod"}]`)},
+			severity: SeverityCritical,
+			labels:   map[string]string{"env": "prod", "host": "db-1"},
+			want:     true,
+		},
+		{
+			name:     "non-matching labels",
+			silence:  &domain.Silence{Matchers: []byte(`[{"name":"env","type":"=","value":"prod"}]`)},
+			severity: SeverityCritical,
+			labels:   map[string]string{"env": "staging"},
+			want:     false,
+		},
+		{
+			name:     "regexp matcher",
+			silence:  &domain.Silence{Matchers: []byte(`[{"name":"host","type":"=~","value":"db-.*"}]`)},
+			severity: SeverityWarning,
+			labels:   map[string]string{"host": "db-7"},
+			want:     true,
+		},
+		{
+			name:     "severity only",
+			silence:  &domain.Silence{Matchers: []byte(`[]`), Severities: []string{SeverityInfo, SeverityWarning}},
+			severity: SeverityWarning,
+			want:     true,
+		},
+		{
+			name:     "severity not silenced",
+			silence:  &domain.Silence{Matchers: []byte(`[{"name":"env","type":"=","value":"prod"}]`), Severities: []string{SeverityInfo}},
+			severity: SeverityCritical,
+			labels:   map[string]string{"env": "prod"},
+			want:     false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := SilenceMatches(testCase.silence, testCase.severity, testCase.labels)
+			require.NoError(t, err)
+			require.Equal(t, testCase.want, got)
+		})
+	}
+
+	_, err := SilenceMatches(&domain.Silence{Matchers: []byte(`[{"name":"env","type":"=~","value":"("}]`)}, SeverityInfo, nil)
+	require.Error(t, err)
+}
+
+func TestAlertSilenced(t *testing.T) {
+	now := time.Now()
+
+	require.False(t, AlertSilenced(&domain.Alert{}, now))
+
+	alert := &domain.Alert{SilencedUntil: pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true}}
+	require.True(t, AlertSilenced(alert, now))
+	require.False(t, AlertSilenced(alert, now.Add(time.Hour)))
+}
+
+func TestLongestSilence(t *testing.T) {
+	matchers := []byte(`[{"name":"env","type":"=","value":"prod"}]`)
+	arg := &domain.CreateAlertParams{
+		Severity: SeverityCritical,
+		Labels:   []byte(`{"env":"prod"}`),
+		OrgID:    DefaultOrgID,
+	}
+
+	// ordered by ends_at desc, as ListActiveSilences returns them
+	silences := []*domain.Silence{
+		{ID: 1, Matchers: matchers, OrgID: 2},
+		{ID: 2, Matchers: []byte(`[{"name":"env","type":"=","value":"staging"}]`), OrgID: DefaultOrgID},
+		{ID: 3, Matchers: matchers, OrgID: DefaultOrgID},
+	}
+
+	silence, err := longestSilence(silences, arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), silence.ID)
+
+	// a silence of another organization never matches
+	silence, err = longestSilence(silences[:1], arg)
+	require.NoError(t, err)
+	require.Nil(t, silence)
+
+	silence, err = longestSilence(nil, arg)
+	require.NoError(t, err)
+	require.Nil(t, silence)
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+const (
+	StatusOpen         = "open"
+	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
+)
+
+var (
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	// ErrStatusRequiresAction is returned when an update tries to acknowledge
+	// or resolve an alert, which only the actions of the same name may do.
+	ErrStatusRequiresAction = fmt.Errorf("%w: alerts are acknowledged and resolved with their actions", ErrInvalidStatusTransition)
+)
+
+// statusTransitions lists the statuses an alert may move to from each status.
+// Resolved alerts can only be reopened.
+var statusTransitions = map[string][]string{
+	StatusOpen:         {StatusAcknowledged, StatusResolved},
+	StatusAcknowledged: {StatusResolved},
+	StatusResolved:     {StatusOpen},
+}
+
+// CheckStatusTransition returns ErrInvalidStatusTransition if an alert in the
+// from status may not be moved to the to status. Staying in the same status is
+// always allowed.
+func CheckStatusTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, from, to)
+}
+
+// checkActionTransition is CheckStatusTransition for the acknowledge and
+// resolve actions, which must not be repeated: doing so would overwrite who
+// performed the original action and when.
+func checkActionTransition(from, to string) error {
+	if from == to {
+		return fmt.Errorf("%w: alert is already %s", ErrInvalidStatusTransition, to)
+	}
+	return CheckStatusTransition(from, to)
+}
+
+// checkUpdateTransition is CheckStatusTransition for updates, which may only
+// reopen resolved alerts. Acknowledging and resolving go through their
+// actions so that who performed them and when is recorded.
+func checkUpdateTransition(from, to string) error {
+	if from != to && (to == StatusAcknowledged || to == StatusResolved) {
+		return ErrStatusRequiresAction
+	}
+	return CheckStatusTransition(from, to)
+}
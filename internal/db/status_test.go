@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUpdateTransition(t *testing.T) {
+	testCases := []struct {
+		name string
+		from string
+		to   string
+		err  error
+	}{
+		{name: "reopen", from: StatusResolved, to: StatusOpen},
+		{name: "unchanged", from: StatusAcknowledged, to: StatusAcknowledged},
+		{name: "acknowledge", from: StatusOpen, to: StatusAcknowledged, err: ErrStatusRequiresAction},
+		{name: "resolve", from: StatusAcknowledged, to: StatusResolved, err: ErrStatusRequiresAction},
+		{name: "open acknowledged", from: StatusAcknowledged, to: StatusOpen, err: ErrInvalidStatusTransition},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkUpdateTransition(testCase.from, testCase.to)
+			if testCase.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, testCase.err)
+			require.ErrorIs(t, err, ErrInvalidStatusTransition)
+		})
+	}
+}
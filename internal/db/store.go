@@ -3,38 +3,87 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
-	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/josephlbailey/alert-service/config"
 	"github.com/josephlbailey/alert-service/internal/db/domain"
+	"github.com/josephlbailey/alert-service/internal/pkg/labels"
 )
 
 var (
-	ErrAlertNotExists = errors.New("alert for the given external id not found")
+	ErrAlertNotExists     = errors.New("alert for the given external id not found")
+	ErrDuplicateOpenAlert = errors.New("an unresolved alert with the same fingerprint already exists")
+	ErrAlertNotDeleted    = errors.New("alert is not deleted")
+	ErrAlertVersionStale  = errors.New("alert has been changed since the given version")
+
+	ErrWebhookSubscriptionNotExists = errors.New("webhook subscription for the given external id not found")
+
+	ErrSilenceNotExists = errors.New("silence for the given external id not found")
+
+	ErrEscalationPolicyNotExists = errors.New("escalation policy for the given external id not found")
+
+	ErrScheduleNotExists         = errors.New("schedule for the given external id not found")
+	ErrScheduleOverrideNotExists = errors.New("schedule override for the given external id not found")
+
+	ErrIncidentNotExists = errors.New("incident for the given external id not found")
+
+	ErrIdempotencyKeyExists    = errors.New("idempotency key is already in use")
+	ErrIdempotencyKeyNotExists = errors.New("idempotency key not found")
+
+	ErrAPIUserNotExists = errors.New("api user not found")
+	ErrAPIKeyNotExists  = errors.New("api key not found")
+
+	ErrOrganizationNotExists = errors.New("organization not found")
 )
 
 type Store interface {
 	domain.Querier
-	CreateAlertTX(ctx context.Context, arg domain.CreateAlertParams) (*domain.Alert, error)
-	UpdateAlertByIDTX(ctx context.Context, arg domain.UpdateAlertByIDParams) (*domain.Alert, error)
-	DeleteAlertByIDTX(ctx context.Context, id int32) error
+	CreateAlertTX(ctx context.Context, arg domain.CreateAlertParams, actor string) (*domain.Alert, error)
+	UpdateAlertByIDTX(ctx context.Context, arg domain.UpdateAlertByIDParams, actor string) (*domain.Alert, error)
+	DeleteAlertByIDTX(ctx context.Context, arg domain.DeleteAlertByIDParams) (*domain.Alert, error)
+	RestoreAlertByIDTX(ctx context.Context, id int32, actor string) (*domain.Alert, error)
+	ApplyAlertBatchTX(ctx context.Context, orgID int32, ops []AlertBatchOp, atomic bool, actor string) ([]AlertBatchResult, error)
+	AcknowledgeAlertByIDTX(ctx context.Context, arg domain.AcknowledgeAlertByIDParams) (*domain.Alert, error)
+	ResolveAlertByIDTX(ctx context.Context, arg domain.ResolveAlertByIDParams) (*domain.Alert, error)
+	ListAlertsMatching(ctx context.Context, arg domain.ListAlertsParams, ms labels.Matchers) ([]*domain.Alert, *domain.Alert, error)
+	ResolveIncidentByIDTX(ctx context.Context, arg domain.ResolveIncidentByIDParams, note pgtype.Text) (*domain.Incident, error)
+	UpdateSilenceByIDTX(ctx context.Context, arg domain.UpdateSilenceByIDParams) (*domain.Silence, error)
+	DeleteSilenceByIDTX(ctx context.Context, arg domain.DeleteSilenceByIDParams) error
+	EscalateDueAlertsTX(ctx context.Context, now time.Time, batchSize int32) (int, error)
+	PublishAlertEventsTX(ctx context.Context, batchSize int32, publish func(context.Context, *domain.AlertEvent) error) ([]*domain.AlertEvent, error)
 }
 
 type AlertServiceStore struct {
 	*domain.Queries
-	db *pgxpool.Pool
+	db           *pgxpool.Pool
+	incidents    config.IncidentConfig
+	inhibitRules []InhibitRule
 }
 
-func NewAlertServiceStore(db *pgxpool.Pool) Store {
+func NewAlertServiceStore(db *pgxpool.Pool, config config.Config) Store {
+	inhibitRules, err := ParseInhibitRules(config.InhibitRules)
+	if err != nil {
+		log.Fatalf("Unable to parse inhibit rules: %v\n", err)
+	}
+
 	return &AlertServiceStore{
-		db:      db,
-		Queries: domain.New(db),
+		db:           db,
+		Queries:      domain.New(orgScopedDB{pool: db}),
+		incidents:    config.Incidents,
+		inhibitRules: inhibitRules,
 	}
 }
 
-func (store *AlertServiceStore) GetAlertByExternalID(ctx context.Context, externalID uuid.UUID) (*domain.Alert, error) {
-	alert, err := store.Queries.GetAlertByExternalID(ctx, externalID)
+func (store *AlertServiceStore) GetAlertByExternalID(ctx context.Context, arg domain.GetAlertByExternalIDParams) (*domain.Alert, error) {
+	alert, err := store.Queries.GetAlertByExternalID(ctx, arg)
 
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -51,12 +100,275 @@ func (store *AlertServiceStore) GetAlertByExternalID(ctx context.Context, extern
 	return alert, nil
 }
 
+// GetAlertByExternalIDIncludeDeleted is GetAlertByExternalID for callers that
+// may also see soft deleted alerts.
+func (store *AlertServiceStore) GetAlertByExternalIDIncludeDeleted(
+	ctx context.Context,
+	arg domain.GetAlertByExternalIDIncludeDeletedParams,
+) (*domain.Alert, error) {
+	alert, err := store.Queries.GetAlertByExternalIDIncludeDeleted(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertNotExists
+		}
+
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (store *AlertServiceStore) GetUnresolvedAlertByFingerprint(
+	ctx context.Context,
+	arg domain.GetUnresolvedAlertByFingerprintParams,
+) (*domain.Alert, error) {
+	alert, err := store.Queries.GetUnresolvedAlertByFingerprint(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertNotExists
+		}
+
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// ListAlertsMatching is ListAlerts that also keeps only the alerts whose labels
+// match ms. The matchers are applied here rather than in the database, whose
+// regular expressions differ from the RE2 syntax ms were checked against. As
+// only so many alerts are read for one call, it may return fewer than a page
+// of alerts along with the last alert it read, after which the next page
+// starts.
+func (store *AlertServiceStore) ListAlertsMatching(
+	ctx context.Context,
+	arg domain.ListAlertsParams,
+	ms labels.Matchers,
+) ([]*domain.Alert, *domain.Alert, error) {
+	return listAlertsMatching(ctx, store.Queries.ListAlerts, arg, ms)
+}
+
+func (store *AlertServiceStore) GetWebhookSubscriptionByExternalID(
+	ctx context.Context,
+	arg domain.GetWebhookSubscriptionByExternalIDParams,
+) (*domain.WebhookSubscription, error) {
+	subscription, err := store.Queries.GetWebhookSubscriptionByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookSubscriptionNotExists
+		}
+
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (store *AlertServiceStore) GetSilenceByExternalID(
+	ctx context.Context,
+	arg domain.GetSilenceByExternalIDParams,
+) (*domain.Silence, error) {
+	silence, err := store.Queries.GetSilenceByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSilenceNotExists
+		}
+
+		return nil, err
+	}
+
+	return silence, nil
+}
+
+func (store *AlertServiceStore) GetEscalationPolicyByExternalID(
+	ctx context.Context,
+	arg domain.GetEscalationPolicyByExternalIDParams,
+) (*domain.EscalationPolicy, error) {
+	policy, err := store.Queries.GetEscalationPolicyByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEscalationPolicyNotExists
+		}
+
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (store *AlertServiceStore) GetScheduleByExternalID(
+	ctx context.Context,
+	arg domain.GetScheduleByExternalIDParams,
+) (*domain.Schedule, error) {
+	schedule, err := store.Queries.GetScheduleByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduleNotExists
+		}
+
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (store *AlertServiceStore) GetScheduleOverrideByExternalID(
+	ctx context.Context,
+	arg domain.GetScheduleOverrideByExternalIDParams,
+) (*domain.ScheduleOverride, error) {
+	override, err := store.Queries.GetScheduleOverrideByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduleOverrideNotExists
+		}
+
+		return nil, err
+	}
+
+	return override, nil
+}
+
+func (store *AlertServiceStore) GetIncidentByExternalID(
+	ctx context.Context,
+	arg domain.GetIncidentByExternalIDParams,
+) (*domain.Incident, error) {
+	incident, err := store.Queries.GetIncidentByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncidentNotExists
+		}
+
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// ReserveIdempotencyKey claims a key for a new request. It fails with
+// ErrIdempotencyKeyExists while an earlier request holds the key.
+func (store *AlertServiceStore) ReserveIdempotencyKey(
+	ctx context.Context,
+	arg domain.ReserveIdempotencyKeyParams,
+) (*domain.IdempotencyKey, error) {
+	key, err := store.Queries.ReserveIdempotencyKey(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIdempotencyKeyExists
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (store *AlertServiceStore) GetIdempotencyKey(
+	ctx context.Context,
+	arg domain.GetIdempotencyKeyParams,
+) (*domain.IdempotencyKey, error) {
+	key, err := store.Queries.GetIdempotencyKey(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIdempotencyKeyNotExists
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIUserByUsername finds an enabled API user, failing with
+// ErrAPIUserNotExists if there is none.
+func (store *AlertServiceStore) GetAPIUserByUsername(ctx context.Context, username string) (*domain.ApiUser, error) {
+	user, err := store.Queries.GetAPIUserByUsername(ctx, username)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAPIUserNotExists
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetAPIKeyByPrefix finds the unrevoked API key with the given prefix, failing
+// with ErrAPIKeyNotExists if there is none.
+func (store *AlertServiceStore) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.ApiKey, error) {
+	key, err := store.Queries.GetAPIKeyByPrefix(ctx, prefix)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAPIKeyNotExists
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetOrganizationBySlug finds an organization, failing with
+// ErrOrganizationNotExists if there is none.
+func (store *AlertServiceStore) GetOrganizationBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	org, err := store.Queries.GetOrganizationBySlug(ctx, slug)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganizationNotExists
+		}
+
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// RevokeAPIKeyByExternalID revokes an API key, failing with
+// ErrAPIKeyNotExists if there is no such key or it is already revoked.
+func (store *AlertServiceStore) RevokeAPIKeyByExternalID(
+	ctx context.Context,
+	arg domain.RevokeAPIKeyByExternalIDParams,
+) (*domain.ApiKey, error) {
+	key, err := store.Queries.RevokeAPIKeyByExternalID(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAPIKeyNotExists
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		pgErr.Code == pgerrcode.UniqueViolation &&
+		pgErr.ConstraintName == constraint
+}
+
 func (store *AlertServiceStore) CreateAlertTX(
 	ctx context.Context,
 	arg domain.CreateAlertParams,
+	actor string,
 ) (*domain.Alert, error) {
 
-	tx, err := store.db.Begin(context.Background())
+	tx, err := beginOrgTx(ctx, store.db)
 	if err != nil {
 		return nil, err
 	}
@@ -65,11 +377,12 @@ func (store *AlertServiceStore) CreateAlertTX(
 
 	qtx := store.Queries.WithTx(tx)
 
-	alert, err := qtx.CreateAlert(ctx, arg)
+	alert, err := store.createAlert(ctx, qtx, arg, actor)
 
 	if err != nil {
 		return nil, err
 	}
+
 	if err = tx.Commit(context.Background()); err != nil {
 		return nil, err
 	}
@@ -77,12 +390,15 @@ func (store *AlertServiceStore) CreateAlertTX(
 	return alert, nil
 }
 
+// UpdateAlertByIDTX updates an alert. A valid arg.Version must match the
+// current version of the alert.
 func (store *AlertServiceStore) UpdateAlertByIDTX(
 	ctx context.Context,
 	arg domain.UpdateAlertByIDParams,
+	actor string,
 ) (*domain.Alert, error) {
 
-	tx, err := store.db.Begin(context.Background())
+	tx, err := beginOrgTx(ctx, store.db)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +407,7 @@ func (store *AlertServiceStore) UpdateAlertByIDTX(
 
 	qtx := store.Queries.WithTx(tx)
 
-	alert, err := qtx.UpdateAlertByID(ctx, arg)
+	alert, err := store.updateAlert(ctx, qtx, arg, actor)
 
 	if err != nil {
 		return nil, err
@@ -104,25 +420,625 @@ func (store *AlertServiceStore) UpdateAlertByIDTX(
 	return alert, nil
 }
 
+// DeleteAlertByIDTX soft deletes an alert. It is hidden from reads until it is
+// restored or purged, and lets go of the alerts it held back. A valid
+// arg.Version must match the current version of the alert.
 func (store *AlertServiceStore) DeleteAlertByIDTX(
 	ctx context.Context,
-	id int32,
-) error {
+	arg domain.DeleteAlertByIDParams,
+) (*domain.Alert, error) {
 
-	tx, err := store.db.Begin(context.Background())
+	tx, err := beginOrgTx(ctx, store.db)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer tx.Rollback(context.Background())
 
 	qtx := store.Queries.WithTx(tx)
 
-	err = qtx.DeleteAlertByID(ctx, id)
+	alert, err := store.deleteAlert(ctx, qtx, arg)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return tx.Commit(context.Background())
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// createAlert silences and inserts an alert, folding it into the unresolved
+// alert with the same fingerprint if there is one.
+func (store *AlertServiceStore) createAlert(
+	ctx context.Context,
+	qtx *domain.Queries,
+	arg domain.CreateAlertParams,
+	actor string,
+) (*domain.Alert, error) {
+	if err := silenceAlert(ctx, qtx, &arg); err != nil {
+		return nil, err
+	}
+
+	return store.insertAlert(ctx, qtx, arg, actor)
+}
+
+// insertAlert is createAlert for an alert that has already been silenced.
+func (store *AlertServiceStore) insertAlert(
+	ctx context.Context,
+	qtx *domain.Queries,
+	arg domain.CreateAlertParams,
+	actor string,
+) (*domain.Alert, error) {
+	alert, err := qtx.CreateAlert(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a deduplicated alert only bumps its occurrence count, which is not
+	// worth an event of its own
+	if alert.Occurrences > 1 {
+		return alert, nil
+	}
+
+	return store.raiseAlert(ctx, qtx, alert, actor)
+}
+
+// raiseAlert does the work that follows the insert of a new alert: grouping,
+// inhibition, the created event and history entry, and escalation.
+func (store *AlertServiceStore) raiseAlert(
+	ctx context.Context,
+	qtx *domain.Queries,
+	alert *domain.Alert,
+	actor string,
+) (*domain.Alert, error) {
+	alert, err := groupAlert(ctx, qtx, alert, store.incidents)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert, err = inhibitAlert(ctx, qtx, alert, store.inhibitRules); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertCreated, alert); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertHistory(ctx, qtx, ActionCreate, actor, nil, alert); err != nil {
+		return nil, err
+	}
+
+	if err = escalateAlert(ctx, qtx, alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (store *AlertServiceStore) updateAlert(
+	ctx context.Context,
+	qtx *domain.Queries,
+	arg domain.UpdateAlertByIDParams,
+	actor string,
+) (*domain.Alert, error) {
+	current, err := qtx.GetAlertByIDForUpdate(ctx, arg.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.Version.Valid && arg.Version.Int32 != current.Version {
+		return nil, ErrAlertVersionStale
+	}
+
+	if arg.Status.Valid {
+		if err = checkUpdateTransition(current.Status, arg.Status.String); err != nil {
+			return nil, err
+		}
+	}
+
+	alert, err := qtx.UpdateAlertByID(ctx, arg)
+
+	if err != nil {
+		// reopening an alert collides with any newer unresolved alert that
+		// has since been raised for the same fingerprint
+		if isUniqueViolation(err, "alert_open_fingerprint_idx") {
+			return nil, ErrDuplicateOpenAlert
+		}
+		return nil, err
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertUpdated, alert); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertHistory(ctx, qtx, ActionUpdate, actor, current, alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (store *AlertServiceStore) deleteAlert(
+	ctx context.Context,
+	qtx *domain.Queries,
+	arg domain.DeleteAlertByIDParams,
+) (*domain.Alert, error) {
+	current, err := qtx.GetAlertByIDForUpdate(ctx, arg.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.Version.Valid && arg.Version.Int32 != current.Version {
+		return nil, ErrAlertVersionStale
+	}
+
+	alert, err := qtx.DeleteAlertByID(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = releaseInhibited(ctx, qtx, alert, store.inhibitRules); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertDeleted, alert); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertHistory(ctx, qtx, ActionDelete, arg.DeletedBy, current, alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// RestoreAlertByIDTX brings back a soft deleted alert. An unresolved alert is
+// held back again if an inhibition rule applies, and picks up its escalation
+// where it stopped.
+func (store *AlertServiceStore) RestoreAlertByIDTX(
+	ctx context.Context,
+	id int32,
+	actor string,
+) (*domain.Alert, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	current, err := qtx.GetAlertByIDForUpdate(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !current.DeletedAt.Valid {
+		return nil, ErrAlertNotDeleted
+	}
+
+	alert, err := qtx.RestoreAlertByID(ctx, id)
+
+	if err != nil {
+		// a new alert may have been raised for the fingerprint meanwhile
+		if isUniqueViolation(err, "alert_open_fingerprint_idx") {
+			return nil, ErrDuplicateOpenAlert
+		}
+		return nil, err
+	}
+
+	if alert.Status != StatusResolved {
+		if alert, err = inhibitAlert(ctx, qtx, alert, store.inhibitRules); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertRestored, alert); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertHistory(ctx, qtx, ActionRestore, actor, current, alert); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (store *AlertServiceStore) AcknowledgeAlertByIDTX(
+	ctx context.Context,
+	arg domain.AcknowledgeAlertByIDParams,
+) (*domain.Alert, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	current, err := qtx.GetAlertByIDForUpdate(ctx, arg.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkActionTransition(current.Status, StatusAcknowledged); err != nil {
+		return nil, err
+	}
+
+	alert, err := qtx.AcknowledgeAlertByID(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertAcknowledged, alert); err != nil {
+		return nil, err
+	}
+
+	err = recordAlertHistory(ctx, qtx, ActionAcknowledge, arg.AcknowledgedBy, current, alert)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordAlertTimeline(ctx, qtx, TimelineAlertAcknowledged, alert, arg.AcknowledgedBy, arg.AcknowledgedNote)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (store *AlertServiceStore) ResolveAlertByIDTX(
+	ctx context.Context,
+	arg domain.ResolveAlertByIDParams,
+) (*domain.Alert, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	current, err := qtx.GetAlertByIDForUpdate(ctx, arg.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkActionTransition(current.Status, StatusResolved); err != nil {
+		return nil, err
+	}
+
+	alert, err := qtx.ResolveAlertByID(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertEvent(ctx, qtx, EventAlertResolved, alert); err != nil {
+		return nil, err
+	}
+
+	if err = recordAlertHistory(ctx, qtx, ActionResolve, arg.ResolvedBy, current, alert); err != nil {
+		return nil, err
+	}
+
+	if err = releaseInhibited(ctx, qtx, alert, store.inhibitRules); err != nil {
+		return nil, err
+	}
+
+	err = recordAlertTimeline(ctx, qtx, TimelineAlertResolved, alert, arg.ResolvedBy, arg.ResolvedNote)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// ResolveIncidentByIDTX resolves an incident together with its member alerts
+// that are still open or acknowledged. The note is kept on each alert it
+// resolves and on the incident timeline. Incidents only group the alerts of
+// their own organization, so every member is visible to the transaction.
+func (store *AlertServiceStore) ResolveIncidentByIDTX(
+	ctx context.Context,
+	arg domain.ResolveIncidentByIDParams,
+	note pgtype.Text,
+) (*domain.Incident, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	current, err := qtx.GetIncidentByIDForUpdate(ctx, domain.GetIncidentByIDForUpdateParams{
+		OrgID: arg.OrgID,
+		ID:    arg.ID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status == StatusResolved {
+		return nil, fmt.Errorf("%w: incident is already resolved", ErrInvalidStatusTransition)
+	}
+
+	alerts, err := qtx.ListUnresolvedAlertsByIncidentIDForUpdate(ctx, pgtype.Int4{Int32: arg.ID, Valid: true})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, current := range alerts {
+		alert, err := qtx.ResolveAlertByID(ctx, domain.ResolveAlertByIDParams{
+			ResolvedAt:   arg.ResolvedAt,
+			ResolvedBy:   arg.ResolvedBy,
+			ResolvedNote: note,
+			ID:           current.ID,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = recordAlertEvent(ctx, qtx, EventAlertResolved, alert); err != nil {
+			return nil, err
+		}
+
+		if err = recordAlertHistory(ctx, qtx, ActionResolve, arg.ResolvedBy, current, alert); err != nil {
+			return nil, err
+		}
+
+		if err = releaseInhibited(ctx, qtx, alert, store.inhibitRules); err != nil {
+			return nil, err
+		}
+	}
+
+	incident, err := qtx.ResolveIncidentByID(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordIncidentTimeline(ctx, qtx, incident.OrgID, incident.ID, TimelineIncidentResolved, nil, arg.ResolvedBy, &note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// UpdateSilenceByIDTX updates a silence and carries its new end time over to
+// the alerts it silences, so shortening a silence takes effect at once.
+func (store *AlertServiceStore) UpdateSilenceByIDTX(
+	ctx context.Context,
+	arg domain.UpdateSilenceByIDParams,
+) (*domain.Silence, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	_, err = qtx.GetSilenceByIDForUpdate(ctx, domain.GetSilenceByIDForUpdateParams{
+		OrgID: arg.OrgID,
+		ID:    arg.ID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	silence, err := qtx.UpdateSilenceByID(ctx, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.UpdateAlertSilenceBySilenceID(ctx, domain.UpdateAlertSilenceBySilenceIDParams{
+		SilencedUntil: pgtype.Timestamptz{Time: silence.EndsAt, Valid: true},
+		SilenceID:     pgtype.Int4{Int32: silence.ID, Valid: true},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return silence, nil
+}
+
+// DeleteSilenceByIDTX removes a silence and unsilences its alerts.
+func (store *AlertServiceStore) DeleteSilenceByIDTX(
+	ctx context.Context,
+	arg domain.DeleteSilenceByIDParams,
+) error {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	// silence_id itself is cleared by the foreign key
+	err = qtx.UpdateAlertSilenceBySilenceID(ctx, domain.UpdateAlertSilenceBySilenceIDParams{
+		SilenceID: pgtype.Int4{Int32: arg.ID, Valid: true},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = qtx.DeleteSilenceByID(ctx, arg)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// EscalateDueAlertsTX runs the due step of up to batchSize escalating alerts.
+// Escalations are claimed with skip locked, so several schedulers can run at
+// once, and their state is saved in the same transaction as the events they
+// raise.
+func (store *AlertServiceStore) EscalateDueAlertsTX(
+	ctx context.Context,
+	now time.Time,
+	batchSize int32,
+) (int, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	escalations, err := qtx.ClaimDueAlertEscalations(ctx, domain.ClaimDueAlertEscalationsParams{
+		Now:       now,
+		BatchSize: batchSize,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	policies := make(map[int32]*domain.EscalationPolicy)
+	for _, escalation := range escalations {
+		policy, ok := policies[escalation.PolicyID]
+		if !ok {
+			policy, err = qtx.GetEscalationPolicyByID(ctx, escalation.PolicyID)
+			if err != nil {
+				return 0, err
+			}
+			policies[escalation.PolicyID] = policy
+		}
+
+		if err = advanceEscalation(ctx, qtx, escalation, policy, now); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return 0, err
+	}
+
+	return len(escalations), nil
+}
+
+// PublishAlertEventsTX hands the oldest unpublished events to publish in order.
+// Each event is numbered from alert_event_sequence just before it is handed
+// over, so the sequence reflects publication order. The events stay locked
+// until the transaction ends, so concurrent relays never publish the same
+// event twice or out of order. It returns the events that were published and
+// committed. If publish fails the events published so far are still committed
+// and returned along with the error; the failed event is retried on the next
+// call.
+func (store *AlertServiceStore) PublishAlertEventsTX(
+	ctx context.Context,
+	batchSize int32,
+	publish func(context.Context, *domain.AlertEvent) error,
+) ([]*domain.AlertEvent, error) {
+
+	tx, err := beginOrgTx(ctx, store.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	qtx := store.Queries.WithTx(tx)
+
+	events, err := qtx.ListUnpublishedAlertEventsForUpdate(ctx, batchSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]*domain.AlertEvent, 0, len(events))
+	var publishErr error
+	for _, event := range events {
+		// the savepoint undoes the mark if publish fails
+		sp, err := tx.Begin(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		event.PublishedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		sequence, err := store.Queries.WithTx(sp).MarkAlertEventPublished(ctx, domain.MarkAlertEventPublishedParams{
+			PublishedAt: event.PublishedAt.Time,
+			ID:          event.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		event.Sequence = pgtype.Int8{Int64: sequence, Valid: true}
+
+		if publishErr = publish(ctx, event); publishErr != nil {
+			if err = sp.Rollback(context.Background()); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		if err = sp.Commit(context.Background()); err != nil {
+			return nil, err
+		}
+		published = append(published, event)
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return published, publishErr
 }
@@ -0,0 +1,86 @@
+package escalation
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+)
+
+const (
+	defaultPollInterval = 15 * time.Second
+	defaultBatchSize    = 50
+)
+
+// Scheduler advances open alerts through their escalation policies. All of
+// its state lives in the alert_escalation table, so a restart picks up where
+// the last run left off and any step that fell due in the meantime runs on
+// the first tick.
+type Scheduler struct {
+	store  db.Store
+	logger *zap.Logger
+	config config.EscalationConfig
+	now    func() time.Time
+}
+
+func NewScheduler(config config.Config, logger *zap.Logger, store db.Store) *Scheduler {
+	c := config.Escalation
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+
+	return &Scheduler{
+		store:  store,
+		logger: logger,
+		config: c,
+		now:    time.Now,
+	}
+}
+
+// Run escalates due alerts every poll interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.Info("starting escalation scheduler...", zap.Duration("pollInterval", s.config.PollInterval))
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("escalation scheduler stopped")
+			return
+		case <-ticker.C:
+			for {
+				n, err := s.EscalateDue(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						s.logger.Error("error escalating alerts", zap.Error(err))
+					}
+					break
+				}
+				if n < int(s.config.BatchSize) {
+					break
+				}
+			}
+		}
+	}
+}
+
+// EscalateDue runs one batch of due escalation steps, returning how many
+// alerts were processed.
+func (s *Scheduler) EscalateDue(ctx context.Context) (int, error) {
+	n, err := s.store.EscalateDueAlertsTX(ctx, s.now(), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		s.logger.Info("escalated alerts.", zap.Int("count", n))
+	}
+	return n, nil
+}
@@ -0,0 +1,42 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestEscalateDue(t *testing.T) {
+	now := time.Date(2024, 7, 25, 9, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			EscalateDueAlertsTX(gomock.Any(), gomock.Eq(now), gomock.Eq(int32(10))).
+			Return(3, nil),
+		store.EXPECT().
+			EscalateDueAlertsTX(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(0, errors.New("connection refused")),
+	)
+
+	scheduler := NewScheduler(config.Config{Escalation: config.EscalationConfig{BatchSize: 10}}, zap.NewNop(), store)
+	scheduler.now = func() time.Time { return now }
+
+	n, err := scheduler.EscalateDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	_, err = scheduler.EscalateDue(context.Background())
+	require.Error(t, err)
+}
@@ -12,9 +12,13 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/gofrs/uuid/v5"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
+	db "github.com/josephlbailey/alert-service/internal/db"
 	domain "github.com/josephlbailey/alert-service/internal/db/domain"
+	labels "github.com/josephlbailey/alert-service/internal/pkg/labels"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +46,139 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeAlertByID mocks base method.
+func (m *MockStore) AcknowledgeAlertByID(ctx context.Context, arg domain.AcknowledgeAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeAlertByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcknowledgeAlertByID indicates an expected call of AcknowledgeAlertByID.
+func (mr *MockStoreMockRecorder) AcknowledgeAlertByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeAlertByID", reflect.TypeOf((*MockStore)(nil).AcknowledgeAlertByID), ctx, arg)
+}
+
+// AcknowledgeAlertByIDTX mocks base method.
+func (m *MockStore) AcknowledgeAlertByIDTX(ctx context.Context, arg domain.AcknowledgeAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeAlertByIDTX", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcknowledgeAlertByIDTX indicates an expected call of AcknowledgeAlertByIDTX.
+func (mr *MockStoreMockRecorder) AcknowledgeAlertByIDTX(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeAlertByIDTX", reflect.TypeOf((*MockStore)(nil).AcknowledgeAlertByIDTX), ctx, arg)
+}
+
+// AdvanceAlertEscalation mocks base method.
+func (m *MockStore) AdvanceAlertEscalation(ctx context.Context, arg domain.AdvanceAlertEscalationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceAlertEscalation", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdvanceAlertEscalation indicates an expected call of AdvanceAlertEscalation.
+func (mr *MockStoreMockRecorder) AdvanceAlertEscalation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceAlertEscalation", reflect.TypeOf((*MockStore)(nil).AdvanceAlertEscalation), ctx, arg)
+}
+
+// ApplyAlertBatchTX mocks base method.
+func (m *MockStore) ApplyAlertBatchTX(ctx context.Context, orgID int32, ops []db.AlertBatchOp, atomic bool, actor string) ([]db.AlertBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyAlertBatchTX", ctx, orgID, ops, atomic, actor)
+	ret0, _ := ret[0].([]db.AlertBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyAlertBatchTX indicates an expected call of ApplyAlertBatchTX.
+func (mr *MockStoreMockRecorder) ApplyAlertBatchTX(ctx, orgID, ops, atomic, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyAlertBatchTX", reflect.TypeOf((*MockStore)(nil).ApplyAlertBatchTX), ctx, orgID, ops, atomic, actor)
+}
+
+// ClaimDueAlertEscalations mocks base method.
+func (m *MockStore) ClaimDueAlertEscalations(ctx context.Context, arg domain.ClaimDueAlertEscalationsParams) ([]*domain.AlertEscalation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueAlertEscalations", ctx, arg)
+	ret0, _ := ret[0].([]*domain.AlertEscalation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueAlertEscalations indicates an expected call of ClaimDueAlertEscalations.
+func (mr *MockStoreMockRecorder) ClaimDueAlertEscalations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueAlertEscalations", reflect.TypeOf((*MockStore)(nil).ClaimDueAlertEscalations), ctx, arg)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(ctx context.Context, arg domain.ClaimDueWebhookDeliveriesParams) ([]*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), ctx, arg)
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockStore) CompleteIdempotencyKey(ctx context.Context, arg domain.CompleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) CompleteIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CompleteIdempotencyKey), ctx, arg)
+}
+
+// CopyAlerts mocks base method.
+func (m *MockStore) CopyAlerts(ctx context.Context, arg []domain.CopyAlertsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyAlerts", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyAlerts indicates an expected call of CopyAlerts.
+func (mr *MockStoreMockRecorder) CopyAlerts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyAlerts", reflect.TypeOf((*MockStore)(nil).CopyAlerts), ctx, arg)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockStore) CreateAPIKey(ctx context.Context, arg domain.CreateAPIKeyParams) (*domain.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(*domain.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStoreMockRecorder) CreateAPIKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStore)(nil).CreateAPIKey), ctx, arg)
+}
+
 // CreateAlert mocks base method.
 func (m *MockStore) CreateAlert(ctx context.Context, arg domain.CreateAlertParams) (*domain.Alert, error) {
 	m.ctrl.T.Helper()
@@ -57,90 +194,1336 @@ func (mr *MockStoreMockRecorder) CreateAlert(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlert", reflect.TypeOf((*MockStore)(nil).CreateAlert), ctx, arg)
 }
 
+// CreateAlertEscalation mocks base method.
+func (m *MockStore) CreateAlertEscalation(ctx context.Context, arg domain.CreateAlertEscalationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertEscalation", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlertEscalation indicates an expected call of CreateAlertEscalation.
+func (mr *MockStoreMockRecorder) CreateAlertEscalation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertEscalation", reflect.TypeOf((*MockStore)(nil).CreateAlertEscalation), ctx, arg)
+}
+
+// CreateAlertEvent mocks base method.
+func (m *MockStore) CreateAlertEvent(ctx context.Context, arg domain.CreateAlertEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlertEvent indicates an expected call of CreateAlertEvent.
+func (mr *MockStoreMockRecorder) CreateAlertEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertEvent", reflect.TypeOf((*MockStore)(nil).CreateAlertEvent), ctx, arg)
+}
+
+// CreateAlertHistory mocks base method.
+func (m *MockStore) CreateAlertHistory(ctx context.Context, arg domain.CreateAlertHistoryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertHistory", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlertHistory indicates an expected call of CreateAlertHistory.
+func (mr *MockStoreMockRecorder) CreateAlertHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertHistory", reflect.TypeOf((*MockStore)(nil).CreateAlertHistory), ctx, arg)
+}
+
 // CreateAlertTX mocks base method.
-func (m *MockStore) CreateAlertTX(ctx context.Context, arg domain.CreateAlertParams) (*domain.Alert, error) {
+func (m *MockStore) CreateAlertTX(ctx context.Context, arg domain.CreateAlertParams, actor string) (*domain.Alert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAlertTX", ctx, arg)
+	ret := m.ctrl.Call(m, "CreateAlertTX", ctx, arg, actor)
 	ret0, _ := ret[0].(*domain.Alert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAlertTX indicates an expected call of CreateAlertTX.
-func (mr *MockStoreMockRecorder) CreateAlertTX(ctx, arg any) *gomock.Call {
+func (mr *MockStoreMockRecorder) CreateAlertTX(ctx, arg, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertTX", reflect.TypeOf((*MockStore)(nil).CreateAlertTX), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertTX", reflect.TypeOf((*MockStore)(nil).CreateAlertTX), ctx, arg, actor)
 }
 
-// DeleteAlertByID mocks base method.
-func (m *MockStore) DeleteAlertByID(ctx context.Context, id int32) error {
+// CreateEscalationPolicy mocks base method.
+func (m *MockStore) CreateEscalationPolicy(ctx context.Context, arg domain.CreateEscalationPolicyParams) (*domain.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscalationPolicy", ctx, arg)
+	ret0, _ := ret[0].(*domain.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEscalationPolicy indicates an expected call of CreateEscalationPolicy.
+func (mr *MockStoreMockRecorder) CreateEscalationPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscalationPolicy", reflect.TypeOf((*MockStore)(nil).CreateEscalationPolicy), ctx, arg)
+}
+
+// CreateIncident mocks base method.
+func (m *MockStore) CreateIncident(ctx context.Context, arg domain.CreateIncidentParams) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncident", ctx, arg)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncident indicates an expected call of CreateIncident.
+func (mr *MockStoreMockRecorder) CreateIncident(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncident", reflect.TypeOf((*MockStore)(nil).CreateIncident), ctx, arg)
+}
+
+// CreateIncidentTimelineEntry mocks base method.
+func (m *MockStore) CreateIncidentTimelineEntry(ctx context.Context, arg domain.CreateIncidentTimelineEntryParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAlertByID", ctx, id)
+	ret := m.ctrl.Call(m, "CreateIncidentTimelineEntry", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CreateIncidentTimelineEntry indicates an expected call of CreateIncidentTimelineEntry.
+func (mr *MockStoreMockRecorder) CreateIncidentTimelineEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentTimelineEntry", reflect.TypeOf((*MockStore)(nil).CreateIncidentTimelineEntry), ctx, arg)
+}
+
+// CreateSchedule mocks base method.
+func (m *MockStore) CreateSchedule(ctx context.Context, arg domain.CreateScheduleParams) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, arg)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockStoreMockRecorder) CreateSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockStore)(nil).CreateSchedule), ctx, arg)
+}
+
+// CreateScheduleOverride mocks base method.
+func (m *MockStore) CreateScheduleOverride(ctx context.Context, arg domain.CreateScheduleOverrideParams) (*domain.ScheduleOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduleOverride", ctx, arg)
+	ret0, _ := ret[0].(*domain.ScheduleOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduleOverride indicates an expected call of CreateScheduleOverride.
+func (mr *MockStoreMockRecorder) CreateScheduleOverride(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduleOverride", reflect.TypeOf((*MockStore)(nil).CreateScheduleOverride), ctx, arg)
+}
+
+// CreateSilence mocks base method.
+func (m *MockStore) CreateSilence(ctx context.Context, arg domain.CreateSilenceParams) (*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSilence", ctx, arg)
+	ret0, _ := ret[0].(*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSilence indicates an expected call of CreateSilence.
+func (mr *MockStoreMockRecorder) CreateSilence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSilence", reflect.TypeOf((*MockStore)(nil).CreateSilence), ctx, arg)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(ctx context.Context, arg domain.CreateWebhookDeliveryParams) (*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), ctx, arg)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockStore) CreateWebhookSubscription(ctx context.Context, arg domain.CreateWebhookSubscriptionParams) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, arg)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockStoreMockRecorder) CreateWebhookSubscription(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockStore)(nil).CreateWebhookSubscription), ctx, arg)
+}
+
+// DeleteAlertByID mocks base method.
+func (m *MockStore) DeleteAlertByID(ctx context.Context, arg domain.DeleteAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlertByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteAlertByID indicates an expected call of DeleteAlertByID.
-func (mr *MockStoreMockRecorder) DeleteAlertByID(ctx, id any) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteAlertByID(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertByID", reflect.TypeOf((*MockStore)(nil).DeleteAlertByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertByID", reflect.TypeOf((*MockStore)(nil).DeleteAlertByID), ctx, arg)
 }
 
 // DeleteAlertByIDTX mocks base method.
-func (m *MockStore) DeleteAlertByIDTX(ctx context.Context, id int32) error {
+func (m *MockStore) DeleteAlertByIDTX(ctx context.Context, arg domain.DeleteAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlertByIDTX", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAlertByIDTX indicates an expected call of DeleteAlertByIDTX.
+func (mr *MockStoreMockRecorder) DeleteAlertByIDTX(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertByIDTX", reflect.TypeOf((*MockStore)(nil).DeleteAlertByIDTX), ctx, arg)
+}
+
+// DeleteEscalationPolicyByID mocks base method.
+func (m *MockStore) DeleteEscalationPolicyByID(ctx context.Context, arg domain.DeleteEscalationPolicyByIDParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAlertByIDTX", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteEscalationPolicyByID", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAlertByIDTX indicates an expected call of DeleteAlertByIDTX.
-func (mr *MockStoreMockRecorder) DeleteAlertByIDTX(ctx, id any) *gomock.Call {
+// DeleteEscalationPolicyByID indicates an expected call of DeleteEscalationPolicyByID.
+func (mr *MockStoreMockRecorder) DeleteEscalationPolicyByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEscalationPolicyByID", reflect.TypeOf((*MockStore)(nil).DeleteEscalationPolicyByID), ctx, arg)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockStore) DeleteIdempotencyKey(ctx context.Context, arg domain.DeleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) DeleteIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).DeleteIdempotencyKey), ctx, arg)
+}
+
+// DeleteScheduleByID mocks base method.
+func (m *MockStore) DeleteScheduleByID(ctx context.Context, arg domain.DeleteScheduleByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduleByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduleByID indicates an expected call of DeleteScheduleByID.
+func (mr *MockStoreMockRecorder) DeleteScheduleByID(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertByIDTX", reflect.TypeOf((*MockStore)(nil).DeleteAlertByIDTX), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduleByID", reflect.TypeOf((*MockStore)(nil).DeleteScheduleByID), ctx, arg)
+}
+
+// DeleteScheduleOverrideByID mocks base method.
+func (m *MockStore) DeleteScheduleOverrideByID(ctx context.Context, arg domain.DeleteScheduleOverrideByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduleOverrideByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduleOverrideByID indicates an expected call of DeleteScheduleOverrideByID.
+func (mr *MockStoreMockRecorder) DeleteScheduleOverrideByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduleOverrideByID", reflect.TypeOf((*MockStore)(nil).DeleteScheduleOverrideByID), ctx, arg)
+}
+
+// DeleteSilenceByID mocks base method.
+func (m *MockStore) DeleteSilenceByID(ctx context.Context, arg domain.DeleteSilenceByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSilenceByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSilenceByID indicates an expected call of DeleteSilenceByID.
+func (mr *MockStoreMockRecorder) DeleteSilenceByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSilenceByID", reflect.TypeOf((*MockStore)(nil).DeleteSilenceByID), ctx, arg)
+}
+
+// DeleteSilenceByIDTX mocks base method.
+func (m *MockStore) DeleteSilenceByIDTX(ctx context.Context, arg domain.DeleteSilenceByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSilenceByIDTX", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSilenceByIDTX indicates an expected call of DeleteSilenceByIDTX.
+func (mr *MockStoreMockRecorder) DeleteSilenceByIDTX(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSilenceByIDTX", reflect.TypeOf((*MockStore)(nil).DeleteSilenceByIDTX), ctx, arg)
+}
+
+// DeleteWebhookSubscriptionByID mocks base method.
+func (m *MockStore) DeleteWebhookSubscriptionByID(ctx context.Context, arg domain.DeleteWebhookSubscriptionByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscriptionByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscriptionByID indicates an expected call of DeleteWebhookSubscriptionByID.
+func (mr *MockStoreMockRecorder) DeleteWebhookSubscriptionByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscriptionByID", reflect.TypeOf((*MockStore)(nil).DeleteWebhookSubscriptionByID), ctx, arg)
+}
+
+// EscalateDueAlertsTX mocks base method.
+func (m *MockStore) EscalateDueAlertsTX(ctx context.Context, now time.Time, batchSize int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateDueAlertsTX", ctx, now, batchSize)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateDueAlertsTX indicates an expected call of EscalateDueAlertsTX.
+func (mr *MockStoreMockRecorder) EscalateDueAlertsTX(ctx, now, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateDueAlertsTX", reflect.TypeOf((*MockStore)(nil).EscalateDueAlertsTX), ctx, now, batchSize)
+}
+
+// GetAPIKeyByPrefix mocks base method.
+func (m *MockStore) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(*domain.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByPrefix indicates an expected call of GetAPIKeyByPrefix.
+func (mr *MockStoreMockRecorder) GetAPIKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByPrefix", reflect.TypeOf((*MockStore)(nil).GetAPIKeyByPrefix), ctx, prefix)
+}
+
+// GetAPIUserByUsername mocks base method.
+func (m *MockStore) GetAPIUserByUsername(ctx context.Context, username string) (*domain.ApiUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIUserByUsername", ctx, username)
+	ret0, _ := ret[0].(*domain.ApiUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIUserByUsername indicates an expected call of GetAPIUserByUsername.
+func (mr *MockStoreMockRecorder) GetAPIUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIUserByUsername", reflect.TypeOf((*MockStore)(nil).GetAPIUserByUsername), ctx, username)
 }
 
 // GetAlertByExternalID mocks base method.
-func (m *MockStore) GetAlertByExternalID(ctx context.Context, externalID uuid.UUID) (*domain.Alert, error) {
+func (m *MockStore) GetAlertByExternalID(ctx context.Context, arg domain.GetAlertByExternalIDParams) (*domain.Alert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAlertByExternalID", ctx, externalID)
+	ret := m.ctrl.Call(m, "GetAlertByExternalID", ctx, arg)
 	ret0, _ := ret[0].(*domain.Alert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAlertByExternalID indicates an expected call of GetAlertByExternalID.
-func (mr *MockStoreMockRecorder) GetAlertByExternalID(ctx, externalID any) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetAlertByExternalID(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertByExternalID", reflect.TypeOf((*MockStore)(nil).GetAlertByExternalID), ctx, externalID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertByExternalID", reflect.TypeOf((*MockStore)(nil).GetAlertByExternalID), ctx, arg)
 }
 
-// UpdateAlertByID mocks base method.
-func (m *MockStore) UpdateAlertByID(ctx context.Context, arg domain.UpdateAlertByIDParams) (*domain.Alert, error) {
+// GetAlertByExternalIDIncludeDeleted mocks base method.
+func (m *MockStore) GetAlertByExternalIDIncludeDeleted(ctx context.Context, arg domain.GetAlertByExternalIDIncludeDeletedParams) (*domain.Alert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAlertByID", ctx, arg)
+	ret := m.ctrl.Call(m, "GetAlertByExternalIDIncludeDeleted", ctx, arg)
 	ret0, _ := ret[0].(*domain.Alert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAlertByID indicates an expected call of UpdateAlertByID.
-func (mr *MockStoreMockRecorder) UpdateAlertByID(ctx, arg any) *gomock.Call {
+// GetAlertByExternalIDIncludeDeleted indicates an expected call of GetAlertByExternalIDIncludeDeleted.
+func (mr *MockStoreMockRecorder) GetAlertByExternalIDIncludeDeleted(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertByID", reflect.TypeOf((*MockStore)(nil).UpdateAlertByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertByExternalIDIncludeDeleted", reflect.TypeOf((*MockStore)(nil).GetAlertByExternalIDIncludeDeleted), ctx, arg)
 }
 
-// UpdateAlertByIDTX mocks base method.
-func (m *MockStore) UpdateAlertByIDTX(ctx context.Context, arg domain.UpdateAlertByIDParams) (*domain.Alert, error) {
+// GetAlertByIDForUpdate mocks base method.
+func (m *MockStore) GetAlertByIDForUpdate(ctx context.Context, id int32) (*domain.Alert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAlertByIDTX", ctx, arg)
+	ret := m.ctrl.Call(m, "GetAlertByIDForUpdate", ctx, id)
 	ret0, _ := ret[0].(*domain.Alert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAlertByIDTX indicates an expected call of UpdateAlertByIDTX.
-func (mr *MockStoreMockRecorder) UpdateAlertByIDTX(ctx, arg any) *gomock.Call {
+// GetAlertByIDForUpdate indicates an expected call of GetAlertByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetAlertByIDForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetAlertByIDForUpdate), ctx, id)
+}
+
+// GetEscalationPolicyByExternalID mocks base method.
+func (m *MockStore) GetEscalationPolicyByExternalID(ctx context.Context, arg domain.GetEscalationPolicyByExternalIDParams) (*domain.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscalationPolicyByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscalationPolicyByExternalID indicates an expected call of GetEscalationPolicyByExternalID.
+func (mr *MockStoreMockRecorder) GetEscalationPolicyByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscalationPolicyByExternalID", reflect.TypeOf((*MockStore)(nil).GetEscalationPolicyByExternalID), ctx, arg)
+}
+
+// GetEscalationPolicyByID mocks base method.
+func (m *MockStore) GetEscalationPolicyByID(ctx context.Context, id int32) (*domain.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscalationPolicyByID", ctx, id)
+	ret0, _ := ret[0].(*domain.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscalationPolicyByID indicates an expected call of GetEscalationPolicyByID.
+func (mr *MockStoreMockRecorder) GetEscalationPolicyByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscalationPolicyByID", reflect.TypeOf((*MockStore)(nil).GetEscalationPolicyByID), ctx, id)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(ctx context.Context, arg domain.GetIdempotencyKeyParams) (*domain.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(*domain.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), ctx, arg)
+}
+
+// GetIncidentByExternalID mocks base method.
+func (m *MockStore) GetIncidentByExternalID(ctx context.Context, arg domain.GetIncidentByExternalIDParams) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentByExternalID indicates an expected call of GetIncidentByExternalID.
+func (mr *MockStoreMockRecorder) GetIncidentByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentByExternalID", reflect.TypeOf((*MockStore)(nil).GetIncidentByExternalID), ctx, arg)
+}
+
+// GetIncidentByIDForUpdate mocks base method.
+func (m *MockStore) GetIncidentByIDForUpdate(ctx context.Context, arg domain.GetIncidentByIDForUpdateParams) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentByIDForUpdate", ctx, arg)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentByIDForUpdate indicates an expected call of GetIncidentByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetIncidentByIDForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetIncidentByIDForUpdate), ctx, arg)
+}
+
+// GetOpenIncidentByGroupKey mocks base method.
+func (m *MockStore) GetOpenIncidentByGroupKey(ctx context.Context, arg domain.GetOpenIncidentByGroupKeyParams) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenIncidentByGroupKey", ctx, arg)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenIncidentByGroupKey indicates an expected call of GetOpenIncidentByGroupKey.
+func (mr *MockStoreMockRecorder) GetOpenIncidentByGroupKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenIncidentByGroupKey", reflect.TypeOf((*MockStore)(nil).GetOpenIncidentByGroupKey), ctx, arg)
+}
+
+// GetOrganizationBySlug mocks base method.
+func (m *MockStore) GetOrganizationBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationBySlug", ctx, slug)
+	ret0, _ := ret[0].(*domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationBySlug indicates an expected call of GetOrganizationBySlug.
+func (mr *MockStoreMockRecorder) GetOrganizationBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationBySlug", reflect.TypeOf((*MockStore)(nil).GetOrganizationBySlug), ctx, slug)
+}
+
+// GetScheduleByExternalID mocks base method.
+func (m *MockStore) GetScheduleByExternalID(ctx context.Context, arg domain.GetScheduleByExternalIDParams) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleByExternalID indicates an expected call of GetScheduleByExternalID.
+func (mr *MockStoreMockRecorder) GetScheduleByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleByExternalID", reflect.TypeOf((*MockStore)(nil).GetScheduleByExternalID), ctx, arg)
+}
+
+// GetScheduleOverrideByExternalID mocks base method.
+func (m *MockStore) GetScheduleOverrideByExternalID(ctx context.Context, arg domain.GetScheduleOverrideByExternalIDParams) (*domain.ScheduleOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleOverrideByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.ScheduleOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleOverrideByExternalID indicates an expected call of GetScheduleOverrideByExternalID.
+func (mr *MockStoreMockRecorder) GetScheduleOverrideByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleOverrideByExternalID", reflect.TypeOf((*MockStore)(nil).GetScheduleOverrideByExternalID), ctx, arg)
+}
+
+// GetSilenceByExternalID mocks base method.
+func (m *MockStore) GetSilenceByExternalID(ctx context.Context, arg domain.GetSilenceByExternalIDParams) (*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSilenceByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSilenceByExternalID indicates an expected call of GetSilenceByExternalID.
+func (mr *MockStoreMockRecorder) GetSilenceByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSilenceByExternalID", reflect.TypeOf((*MockStore)(nil).GetSilenceByExternalID), ctx, arg)
+}
+
+// GetSilenceByIDForUpdate mocks base method.
+func (m *MockStore) GetSilenceByIDForUpdate(ctx context.Context, arg domain.GetSilenceByIDForUpdateParams) (*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSilenceByIDForUpdate", ctx, arg)
+	ret0, _ := ret[0].(*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSilenceByIDForUpdate indicates an expected call of GetSilenceByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetSilenceByIDForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSilenceByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetSilenceByIDForUpdate), ctx, arg)
+}
+
+// GetUnresolvedAlertByFingerprint mocks base method.
+func (m *MockStore) GetUnresolvedAlertByFingerprint(ctx context.Context, arg domain.GetUnresolvedAlertByFingerprintParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnresolvedAlertByFingerprint", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnresolvedAlertByFingerprint indicates an expected call of GetUnresolvedAlertByFingerprint.
+func (mr *MockStoreMockRecorder) GetUnresolvedAlertByFingerprint(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnresolvedAlertByFingerprint", reflect.TypeOf((*MockStore)(nil).GetUnresolvedAlertByFingerprint), ctx, arg)
+}
+
+// GetWebhookSubscriptionByExternalID mocks base method.
+func (m *MockStore) GetWebhookSubscriptionByExternalID(ctx context.Context, arg domain.GetWebhookSubscriptionByExternalIDParams) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptionByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptionByExternalID indicates an expected call of GetWebhookSubscriptionByExternalID.
+func (mr *MockStoreMockRecorder) GetWebhookSubscriptionByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptionByExternalID", reflect.TypeOf((*MockStore)(nil).GetWebhookSubscriptionByExternalID), ctx, arg)
+}
+
+// GetWebhookSubscriptionByID mocks base method.
+func (m *MockStore) GetWebhookSubscriptionByID(ctx context.Context, id int32) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptionByID", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptionByID indicates an expected call of GetWebhookSubscriptionByID.
+func (mr *MockStoreMockRecorder) GetWebhookSubscriptionByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptionByID", reflect.TypeOf((*MockStore)(nil).GetWebhookSubscriptionByID), ctx, id)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockStore) ListAPIKeys(ctx context.Context, orgID int32) ([]*domain.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, orgID)
+	ret0, _ := ret[0].([]*domain.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockStoreMockRecorder) ListAPIKeys(ctx, orgID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockStore)(nil).ListAPIKeys), ctx, orgID)
+}
+
+// ListActiveSilences mocks base method.
+func (m *MockStore) ListActiveSilences(ctx context.Context, arg domain.ListActiveSilencesParams) ([]*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSilences", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSilences indicates an expected call of ListActiveSilences.
+func (mr *MockStoreMockRecorder) ListActiveSilences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSilences", reflect.TypeOf((*MockStore)(nil).ListActiveSilences), ctx, arg)
+}
+
+// ListActiveWebhookSubscriptionsForEvent mocks base method.
+func (m *MockStore) ListActiveWebhookSubscriptionsForEvent(ctx context.Context, arg domain.ListActiveWebhookSubscriptionsForEventParams) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveWebhookSubscriptionsForEvent", ctx, arg)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveWebhookSubscriptionsForEvent indicates an expected call of ListActiveWebhookSubscriptionsForEvent.
+func (mr *MockStoreMockRecorder) ListActiveWebhookSubscriptionsForEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveWebhookSubscriptionsForEvent", reflect.TypeOf((*MockStore)(nil).ListActiveWebhookSubscriptionsForEvent), ctx, arg)
+}
+
+// ListAlertExternalIDsByIncidentID mocks base method.
+func (m *MockStore) ListAlertExternalIDsByIncidentID(ctx context.Context, incidentID pgtype.Int4) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertExternalIDsByIncidentID", ctx, incidentID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertExternalIDsByIncidentID indicates an expected call of ListAlertExternalIDsByIncidentID.
+func (mr *MockStoreMockRecorder) ListAlertExternalIDsByIncidentID(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertExternalIDsByIncidentID", reflect.TypeOf((*MockStore)(nil).ListAlertExternalIDsByIncidentID), ctx, incidentID)
+}
+
+// ListAlertHistoryByExternalID mocks base method.
+func (m *MockStore) ListAlertHistoryByExternalID(ctx context.Context, arg domain.ListAlertHistoryByExternalIDParams) ([]*domain.AlertHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertHistoryByExternalID", ctx, arg)
+	ret0, _ := ret[0].([]*domain.AlertHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertHistoryByExternalID indicates an expected call of ListAlertHistoryByExternalID.
+func (mr *MockStoreMockRecorder) ListAlertHistoryByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertHistoryByExternalID", reflect.TypeOf((*MockStore)(nil).ListAlertHistoryByExternalID), ctx, arg)
+}
+
+// ListAlerts mocks base method.
+func (m *MockStore) ListAlerts(ctx context.Context, arg domain.ListAlertsParams) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlerts", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlerts indicates an expected call of ListAlerts.
+func (mr *MockStoreMockRecorder) ListAlerts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlerts", reflect.TypeOf((*MockStore)(nil).ListAlerts), ctx, arg)
+}
+
+// ListAlertsByExternalIDs mocks base method.
+func (m *MockStore) ListAlertsByExternalIDs(ctx context.Context, arg domain.ListAlertsByExternalIDsParams) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertsByExternalIDs", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertsByExternalIDs indicates an expected call of ListAlertsByExternalIDs.
+func (mr *MockStoreMockRecorder) ListAlertsByExternalIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertsByExternalIDs", reflect.TypeOf((*MockStore)(nil).ListAlertsByExternalIDs), ctx, arg)
+}
+
+// ListAlertsInhibitedByForUpdate mocks base method.
+func (m *MockStore) ListAlertsInhibitedByForUpdate(ctx context.Context, inhibitedBy pgtype.UUID) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertsInhibitedByForUpdate", ctx, inhibitedBy)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertsInhibitedByForUpdate indicates an expected call of ListAlertsInhibitedByForUpdate.
+func (mr *MockStoreMockRecorder) ListAlertsInhibitedByForUpdate(ctx, inhibitedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertsInhibitedByForUpdate", reflect.TypeOf((*MockStore)(nil).ListAlertsInhibitedByForUpdate), ctx, inhibitedBy)
+}
+
+// ListAlertsMatching mocks base method.
+func (m *MockStore) ListAlertsMatching(ctx context.Context, arg domain.ListAlertsParams, ms labels.Matchers) ([]*domain.Alert, *domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertsMatching", ctx, arg, ms)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(*domain.Alert)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAlertsMatching indicates an expected call of ListAlertsMatching.
+func (mr *MockStoreMockRecorder) ListAlertsMatching(ctx, arg, ms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertsMatching", reflect.TypeOf((*MockStore)(nil).ListAlertsMatching), ctx, arg, ms)
+}
+
+// ListEscalationPolicies mocks base method.
+func (m *MockStore) ListEscalationPolicies(ctx context.Context, orgID int32) ([]*domain.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscalationPolicies", ctx, orgID)
+	ret0, _ := ret[0].([]*domain.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscalationPolicies indicates an expected call of ListEscalationPolicies.
+func (mr *MockStoreMockRecorder) ListEscalationPolicies(ctx, orgID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscalationPolicies", reflect.TypeOf((*MockStore)(nil).ListEscalationPolicies), ctx, orgID)
+}
+
+// ListIncidentTimeline mocks base method.
+func (m *MockStore) ListIncidentTimeline(ctx context.Context, incidentID int32) ([]*domain.ListIncidentTimelineRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentTimeline", ctx, incidentID)
+	ret0, _ := ret[0].([]*domain.ListIncidentTimelineRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentTimeline indicates an expected call of ListIncidentTimeline.
+func (mr *MockStoreMockRecorder) ListIncidentTimeline(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentTimeline", reflect.TypeOf((*MockStore)(nil).ListIncidentTimeline), ctx, incidentID)
+}
+
+// ListIncidents mocks base method.
+func (m *MockStore) ListIncidents(ctx context.Context, arg domain.ListIncidentsParams) ([]*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidents", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidents indicates an expected call of ListIncidents.
+func (mr *MockStoreMockRecorder) ListIncidents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidents", reflect.TypeOf((*MockStore)(nil).ListIncidents), ctx, arg)
+}
+
+// ListPublishedAlertEventsAfterSequence mocks base method.
+func (m *MockStore) ListPublishedAlertEventsAfterSequence(ctx context.Context, arg domain.ListPublishedAlertEventsAfterSequenceParams) ([]*domain.AlertEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublishedAlertEventsAfterSequence", ctx, arg)
+	ret0, _ := ret[0].([]*domain.AlertEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublishedAlertEventsAfterSequence indicates an expected call of ListPublishedAlertEventsAfterSequence.
+func (mr *MockStoreMockRecorder) ListPublishedAlertEventsAfterSequence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublishedAlertEventsAfterSequence", reflect.TypeOf((*MockStore)(nil).ListPublishedAlertEventsAfterSequence), ctx, arg)
+}
+
+// ListScheduleOverrides mocks base method.
+func (m *MockStore) ListScheduleOverrides(ctx context.Context, arg domain.ListScheduleOverridesParams) ([]*domain.ScheduleOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduleOverrides", ctx, arg)
+	ret0, _ := ret[0].([]*domain.ScheduleOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduleOverrides indicates an expected call of ListScheduleOverrides.
+func (mr *MockStoreMockRecorder) ListScheduleOverrides(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduleOverrides", reflect.TypeOf((*MockStore)(nil).ListScheduleOverrides), ctx, arg)
+}
+
+// ListScheduleOverridesAt mocks base method.
+func (m *MockStore) ListScheduleOverridesAt(ctx context.Context, arg domain.ListScheduleOverridesAtParams) ([]*domain.ScheduleOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduleOverridesAt", ctx, arg)
+	ret0, _ := ret[0].([]*domain.ScheduleOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduleOverridesAt indicates an expected call of ListScheduleOverridesAt.
+func (mr *MockStoreMockRecorder) ListScheduleOverridesAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduleOverridesAt", reflect.TypeOf((*MockStore)(nil).ListScheduleOverridesAt), ctx, arg)
+}
+
+// ListSchedules mocks base method.
+func (m *MockStore) ListSchedules(ctx context.Context, orgID int32) ([]*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, orgID)
+	ret0, _ := ret[0].([]*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockStoreMockRecorder) ListSchedules(ctx, orgID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockStore)(nil).ListSchedules), ctx, orgID)
+}
+
+// ListSilences mocks base method.
+func (m *MockStore) ListSilences(ctx context.Context, arg domain.ListSilencesParams) ([]*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSilences", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSilences indicates an expected call of ListSilences.
+func (mr *MockStoreMockRecorder) ListSilences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSilences", reflect.TypeOf((*MockStore)(nil).ListSilences), ctx, arg)
+}
+
+// ListUnpublishedAlertEventsForUpdate mocks base method.
+func (m *MockStore) ListUnpublishedAlertEventsForUpdate(ctx context.Context, batchSize int32) ([]*domain.AlertEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpublishedAlertEventsForUpdate", ctx, batchSize)
+	ret0, _ := ret[0].([]*domain.AlertEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpublishedAlertEventsForUpdate indicates an expected call of ListUnpublishedAlertEventsForUpdate.
+func (mr *MockStoreMockRecorder) ListUnpublishedAlertEventsForUpdate(ctx, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpublishedAlertEventsForUpdate", reflect.TypeOf((*MockStore)(nil).ListUnpublishedAlertEventsForUpdate), ctx, batchSize)
+}
+
+// ListUnresolvedAlertFingerprints mocks base method.
+func (m *MockStore) ListUnresolvedAlertFingerprints(ctx context.Context, arg domain.ListUnresolvedAlertFingerprintsParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnresolvedAlertFingerprints", ctx, arg)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnresolvedAlertFingerprints indicates an expected call of ListUnresolvedAlertFingerprints.
+func (mr *MockStoreMockRecorder) ListUnresolvedAlertFingerprints(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnresolvedAlertFingerprints", reflect.TypeOf((*MockStore)(nil).ListUnresolvedAlertFingerprints), ctx, arg)
+}
+
+// ListUnresolvedAlertsByIncidentIDForUpdate mocks base method.
+func (m *MockStore) ListUnresolvedAlertsByIncidentIDForUpdate(ctx context.Context, incidentID pgtype.Int4) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnresolvedAlertsByIncidentIDForUpdate", ctx, incidentID)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnresolvedAlertsByIncidentIDForUpdate indicates an expected call of ListUnresolvedAlertsByIncidentIDForUpdate.
+func (mr *MockStoreMockRecorder) ListUnresolvedAlertsByIncidentIDForUpdate(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnresolvedAlertsByIncidentIDForUpdate", reflect.TypeOf((*MockStore)(nil).ListUnresolvedAlertsByIncidentIDForUpdate), ctx, incidentID)
+}
+
+// ListUnresolvedAlertsMatching mocks base method.
+func (m *MockStore) ListUnresolvedAlertsMatching(ctx context.Context, arg domain.ListUnresolvedAlertsMatchingParams) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnresolvedAlertsMatching", ctx, arg)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnresolvedAlertsMatching indicates an expected call of ListUnresolvedAlertsMatching.
+func (mr *MockStoreMockRecorder) ListUnresolvedAlertsMatching(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnresolvedAlertsMatching", reflect.TypeOf((*MockStore)(nil).ListUnresolvedAlertsMatching), ctx, arg)
+}
+
+// ListWebhookDeliveriesBySubscriptionID mocks base method.
+func (m *MockStore) ListWebhookDeliveriesBySubscriptionID(ctx context.Context, arg domain.ListWebhookDeliveriesBySubscriptionIDParams) ([]*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveriesBySubscriptionID", ctx, arg)
+	ret0, _ := ret[0].([]*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveriesBySubscriptionID indicates an expected call of ListWebhookDeliveriesBySubscriptionID.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveriesBySubscriptionID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveriesBySubscriptionID", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveriesBySubscriptionID), ctx, arg)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockStore) ListWebhookSubscriptions(ctx context.Context, orgID int32) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx, orgID)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptions(ctx, orgID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptions), ctx, orgID)
+}
+
+// LockIncidentGroup mocks base method.
+func (m *MockStore) LockIncidentGroup(ctx context.Context, arg domain.LockIncidentGroupParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockIncidentGroup", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockIncidentGroup indicates an expected call of LockIncidentGroup.
+func (mr *MockStoreMockRecorder) LockIncidentGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockIncidentGroup", reflect.TypeOf((*MockStore)(nil).LockIncidentGroup), ctx, arg)
+}
+
+// MarkAlertEventPublished mocks base method.
+func (m *MockStore) MarkAlertEventPublished(ctx context.Context, arg domain.MarkAlertEventPublishedParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAlertEventPublished", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAlertEventPublished indicates an expected call of MarkAlertEventPublished.
+func (mr *MockStoreMockRecorder) MarkAlertEventPublished(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAlertEventPublished", reflect.TypeOf((*MockStore)(nil).MarkAlertEventPublished), ctx, arg)
+}
+
+// PublishAlertEventsTX mocks base method.
+func (m *MockStore) PublishAlertEventsTX(ctx context.Context, batchSize int32, publish func(context.Context, *domain.AlertEvent) error) ([]*domain.AlertEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAlertEventsTX", ctx, batchSize, publish)
+	ret0, _ := ret[0].([]*domain.AlertEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishAlertEventsTX indicates an expected call of PublishAlertEventsTX.
+func (mr *MockStoreMockRecorder) PublishAlertEventsTX(ctx, batchSize, publish any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAlertEventsTX", reflect.TypeOf((*MockStore)(nil).PublishAlertEventsTX), ctx, batchSize, publish)
+}
+
+// PurgeDeletedAlerts mocks base method.
+func (m *MockStore) PurgeDeletedAlerts(ctx context.Context, arg domain.PurgeDeletedAlertsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedAlerts", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedAlerts indicates an expected call of PurgeDeletedAlerts.
+func (mr *MockStoreMockRecorder) PurgeDeletedAlerts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedAlerts", reflect.TypeOf((*MockStore)(nil).PurgeDeletedAlerts), ctx, arg)
+}
+
+// PurgeExpiredIdempotencyKeys mocks base method.
+func (m *MockStore) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredIdempotencyKeys", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredIdempotencyKeys indicates an expected call of PurgeExpiredIdempotencyKeys.
+func (mr *MockStoreMockRecorder) PurgeExpiredIdempotencyKeys(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).PurgeExpiredIdempotencyKeys), ctx, now)
+}
+
+// RecordWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) RecordWebhookDeliveryAttempt(ctx context.Context, arg domain.RecordWebhookDeliveryAttemptParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryAttempt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookDeliveryAttempt indicates an expected call of RecordWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookDeliveryAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookDeliveryAttempt), ctx, arg)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockStore) ReserveIdempotencyKey(ctx context.Context, arg domain.ReserveIdempotencyKeyParams) (*domain.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(*domain.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockStoreMockRecorder) ReserveIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockStore)(nil).ReserveIdempotencyKey), ctx, arg)
+}
+
+// ResolveAlertByID mocks base method.
+func (m *MockStore) ResolveAlertByID(ctx context.Context, arg domain.ResolveAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAlertByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAlertByID indicates an expected call of ResolveAlertByID.
+func (mr *MockStoreMockRecorder) ResolveAlertByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAlertByID", reflect.TypeOf((*MockStore)(nil).ResolveAlertByID), ctx, arg)
+}
+
+// ResolveAlertByIDTX mocks base method.
+func (m *MockStore) ResolveAlertByIDTX(ctx context.Context, arg domain.ResolveAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAlertByIDTX", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAlertByIDTX indicates an expected call of ResolveAlertByIDTX.
+func (mr *MockStoreMockRecorder) ResolveAlertByIDTX(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAlertByIDTX", reflect.TypeOf((*MockStore)(nil).ResolveAlertByIDTX), ctx, arg)
+}
+
+// ResolveIncidentByID mocks base method.
+func (m *MockStore) ResolveIncidentByID(ctx context.Context, arg domain.ResolveIncidentByIDParams) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveIncidentByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveIncidentByID indicates an expected call of ResolveIncidentByID.
+func (mr *MockStoreMockRecorder) ResolveIncidentByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveIncidentByID", reflect.TypeOf((*MockStore)(nil).ResolveIncidentByID), ctx, arg)
+}
+
+// ResolveIncidentByIDTX mocks base method.
+func (m *MockStore) ResolveIncidentByIDTX(ctx context.Context, arg domain.ResolveIncidentByIDParams, note pgtype.Text) (*domain.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveIncidentByIDTX", ctx, arg, note)
+	ret0, _ := ret[0].(*domain.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveIncidentByIDTX indicates an expected call of ResolveIncidentByIDTX.
+func (mr *MockStoreMockRecorder) ResolveIncidentByIDTX(ctx, arg, note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveIncidentByIDTX", reflect.TypeOf((*MockStore)(nil).ResolveIncidentByIDTX), ctx, arg, note)
+}
+
+// RestoreAlertByID mocks base method.
+func (m *MockStore) RestoreAlertByID(ctx context.Context, id int32) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAlertByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAlertByID indicates an expected call of RestoreAlertByID.
+func (mr *MockStoreMockRecorder) RestoreAlertByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAlertByID", reflect.TypeOf((*MockStore)(nil).RestoreAlertByID), ctx, id)
+}
+
+// RestoreAlertByIDTX mocks base method.
+func (m *MockStore) RestoreAlertByIDTX(ctx context.Context, id int32, actor string) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAlertByIDTX", ctx, id, actor)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAlertByIDTX indicates an expected call of RestoreAlertByIDTX.
+func (mr *MockStoreMockRecorder) RestoreAlertByIDTX(ctx, id, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAlertByIDTX", reflect.TypeOf((*MockStore)(nil).RestoreAlertByIDTX), ctx, id, actor)
+}
+
+// RevokeAPIKeyByExternalID mocks base method.
+func (m *MockStore) RevokeAPIKeyByExternalID(ctx context.Context, arg domain.RevokeAPIKeyByExternalIDParams) (*domain.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKeyByExternalID", ctx, arg)
+	ret0, _ := ret[0].(*domain.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKeyByExternalID indicates an expected call of RevokeAPIKeyByExternalID.
+func (mr *MockStoreMockRecorder) RevokeAPIKeyByExternalID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKeyByExternalID", reflect.TypeOf((*MockStore)(nil).RevokeAPIKeyByExternalID), ctx, arg)
+}
+
+// SetAlertIncidentByID mocks base method.
+func (m *MockStore) SetAlertIncidentByID(ctx context.Context, arg domain.SetAlertIncidentByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlertIncidentByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAlertIncidentByID indicates an expected call of SetAlertIncidentByID.
+func (mr *MockStoreMockRecorder) SetAlertIncidentByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlertIncidentByID", reflect.TypeOf((*MockStore)(nil).SetAlertIncidentByID), ctx, arg)
+}
+
+// SetAlertInhibitedByID mocks base method.
+func (m *MockStore) SetAlertInhibitedByID(ctx context.Context, arg domain.SetAlertInhibitedByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlertInhibitedByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAlertInhibitedByID indicates an expected call of SetAlertInhibitedByID.
+func (mr *MockStoreMockRecorder) SetAlertInhibitedByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlertInhibitedByID", reflect.TypeOf((*MockStore)(nil).SetAlertInhibitedByID), ctx, arg)
+}
+
+// SetOrgContext mocks base method.
+func (m *MockStore) SetOrgContext(ctx context.Context, arg domain.SetOrgContextParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrgContext", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrgContext indicates an expected call of SetOrgContext.
+func (mr *MockStoreMockRecorder) SetOrgContext(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrgContext", reflect.TypeOf((*MockStore)(nil).SetOrgContext), ctx, arg)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockStore) TouchAPIKey(ctx context.Context, arg domain.TouchAPIKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockStoreMockRecorder) TouchAPIKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockStore)(nil).TouchAPIKey), ctx, arg)
+}
+
+// TouchIncident mocks base method.
+func (m *MockStore) TouchIncident(ctx context.Context, arg domain.TouchIncidentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchIncident", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchIncident indicates an expected call of TouchIncident.
+func (mr *MockStoreMockRecorder) TouchIncident(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchIncident", reflect.TypeOf((*MockStore)(nil).TouchIncident), ctx, arg)
+}
+
+// UpdateAlertByID mocks base method.
+func (m *MockStore) UpdateAlertByID(ctx context.Context, arg domain.UpdateAlertByIDParams) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAlertByID indicates an expected call of UpdateAlertByID.
+func (mr *MockStoreMockRecorder) UpdateAlertByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertByID", reflect.TypeOf((*MockStore)(nil).UpdateAlertByID), ctx, arg)
+}
+
+// UpdateAlertByIDTX mocks base method.
+func (m *MockStore) UpdateAlertByIDTX(ctx context.Context, arg domain.UpdateAlertByIDParams, actor string) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertByIDTX", ctx, arg, actor)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAlertByIDTX indicates an expected call of UpdateAlertByIDTX.
+func (mr *MockStoreMockRecorder) UpdateAlertByIDTX(ctx, arg, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertByIDTX", reflect.TypeOf((*MockStore)(nil).UpdateAlertByIDTX), ctx, arg, actor)
+}
+
+// UpdateAlertSilenceBySilenceID mocks base method.
+func (m *MockStore) UpdateAlertSilenceBySilenceID(ctx context.Context, arg domain.UpdateAlertSilenceBySilenceIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertSilenceBySilenceID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlertSilenceBySilenceID indicates an expected call of UpdateAlertSilenceBySilenceID.
+func (mr *MockStoreMockRecorder) UpdateAlertSilenceBySilenceID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertSilenceBySilenceID", reflect.TypeOf((*MockStore)(nil).UpdateAlertSilenceBySilenceID), ctx, arg)
+}
+
+// UpdateEscalationPolicyByID mocks base method.
+func (m *MockStore) UpdateEscalationPolicyByID(ctx context.Context, arg domain.UpdateEscalationPolicyByIDParams) (*domain.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEscalationPolicyByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEscalationPolicyByID indicates an expected call of UpdateEscalationPolicyByID.
+func (mr *MockStoreMockRecorder) UpdateEscalationPolicyByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEscalationPolicyByID", reflect.TypeOf((*MockStore)(nil).UpdateEscalationPolicyByID), ctx, arg)
+}
+
+// UpdateScheduleByID mocks base method.
+func (m *MockStore) UpdateScheduleByID(ctx context.Context, arg domain.UpdateScheduleByIDParams) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduleByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduleByID indicates an expected call of UpdateScheduleByID.
+func (mr *MockStoreMockRecorder) UpdateScheduleByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateScheduleByID), ctx, arg)
+}
+
+// UpdateSilenceByID mocks base method.
+func (m *MockStore) UpdateSilenceByID(ctx context.Context, arg domain.UpdateSilenceByIDParams) (*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSilenceByID", ctx, arg)
+	ret0, _ := ret[0].(*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSilenceByID indicates an expected call of UpdateSilenceByID.
+func (mr *MockStoreMockRecorder) UpdateSilenceByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSilenceByID", reflect.TypeOf((*MockStore)(nil).UpdateSilenceByID), ctx, arg)
+}
+
+// UpdateSilenceByIDTX mocks base method.
+func (m *MockStore) UpdateSilenceByIDTX(ctx context.Context, arg domain.UpdateSilenceByIDParams) (*domain.Silence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSilenceByIDTX", ctx, arg)
+	ret0, _ := ret[0].(*domain.Silence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSilenceByIDTX indicates an expected call of UpdateSilenceByIDTX.
+func (mr *MockStoreMockRecorder) UpdateSilenceByIDTX(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertByIDTX", reflect.TypeOf((*MockStore)(nil).UpdateAlertByIDTX), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSilenceByIDTX", reflect.TypeOf((*MockStore)(nil).UpdateSilenceByIDTX), ctx, arg)
 }
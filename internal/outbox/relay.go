@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+// Publisher receives alert events from the outbox in the order they were
+// recorded. Delivery is at least once: an event is handed over again if the
+// relay stops before the event is marked published, so Publish should be
+// idempotent on the event's ExternalID.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.AlertEvent) error
+}
+
+// Publishers fans an event out to several publishers in turn, stopping at the
+// first error.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event *domain.AlertEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Relay moves events recorded in the alert_event outbox to a Publisher.
+//
+// The publisher is called while the event is being marked published, so a
+// failure leaves the event to be retried. Listeners are only told about events
+// once that has been committed, so they never see a sequence number that a
+// rolled back batch would give to another event. A listener cannot hold an
+// event back: its errors are logged and the event is not retried.
+type Relay struct {
+	store     db.Store
+	logger    *zap.Logger
+	publisher Publisher
+	listeners []Publisher
+	config    config.OutboxConfig
+}
+
+func NewRelay(config config.Config, logger *zap.Logger, store db.Store, publisher Publisher, listeners ...Publisher) *Relay {
+	c := config.Outbox
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+
+	return &Relay{
+		store:     store,
+		logger:    logger,
+		publisher: publisher,
+		listeners: listeners,
+		config:    c,
+	}
+}
+
+// Run relays pending events every poll interval until ctx is cancelled. A full
+// batch is followed immediately by another so that a backlog drains quickly.
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("starting outbox relay...", zap.Duration("pollInterval", r.config.PollInterval))
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+			for {
+				n, err := r.RelayPending(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						r.logger.Error("error relaying alert events", zap.Error(err))
+					}
+					break
+				}
+				if n < int(r.config.BatchSize) {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayPending publishes one batch of pending events, returning how many were
+// published.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	events, err := r.store.PublishAlertEventsTX(ctx, r.config.BatchSize, r.publisher.Publish)
+
+	for _, event := range events {
+		for _, listener := range r.listeners {
+			if lerr := listener.Publish(ctx, event); lerr != nil {
+				r.logger.Warn("error notifying listener of alert event",
+					zap.Int64("sequence", event.Sequence.Int64),
+					zap.Error(lerr),
+				)
+			}
+		}
+	}
+
+	return len(events), err
+}
@@ -0,0 +1,167 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+type recordingPublisher struct {
+	published []int64
+	failOn    int64
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event *domain.AlertEvent) error {
+	if event.ID == p.failOn {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestRelayPending(t *testing.T) {
+	events := []*domain.AlertEvent{
+		randomAlertEvent(1, db.EventAlertCreated),
+		randomAlertEvent(2, db.EventAlertAcknowledged),
+		randomAlertEvent(3, db.EventAlertResolved),
+	}
+
+	testCases := []struct {
+		name      string
+		failOn    int64
+		published []int64
+		wantErr   bool
+	}{
+		{
+			name:      "events are published in order",
+			published: []int64{1, 2, 3},
+		},
+		{
+			name:      "publishing stops at the first failure",
+			failOn:    2,
+			published: []int64{1},
+			wantErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				PublishAlertEventsTX(gomock.Any(), gomock.Eq(int32(50)), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, _ int32, publish func(context.Context, *domain.AlertEvent) error) ([]*domain.AlertEvent, error) {
+					// mirrors the store: stop at the first failure
+					for i, event := range events {
+						if err := publish(ctx, event); err != nil {
+							return events[:i], err
+						}
+					}
+					return events, nil
+				})
+
+			publisher := &recordingPublisher{failOn: testCase.failOn}
+			listener := &recordingPublisher{}
+			relay := NewRelay(config.Config{Outbox: config.OutboxConfig{BatchSize: 50}}, zap.NewNop(), store, publisher, listener)
+
+			n, err := relay.RelayPending(context.Background())
+			if testCase.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, len(testCase.published), n)
+			require.Equal(t, testCase.published, publisher.published)
+			// listeners only hear of the events that were committed
+			require.Equal(t, testCase.published, listener.published)
+		})
+	}
+}
+
+func TestRelayPendingNotifiesAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event := randomAlertEvent(1, db.EventAlertCreated)
+	listener := &recordingPublisher{}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PublishAlertEventsTX(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ int32, publish func(context.Context, *domain.AlertEvent) error) ([]*domain.AlertEvent, error) {
+			require.NoError(t, publish(ctx, event))
+			require.Empty(t, listener.published)
+			// the commit fails, so nothing was published
+			return nil, errors.New("commit failed")
+		})
+
+	relay := NewRelay(config.Config{}, zap.NewNop(), store, &recordingPublisher{}, listener)
+
+	n, err := relay.RelayPending(context.Background())
+	require.Error(t, err)
+	require.Zero(t, n)
+	require.Empty(t, listener.published)
+}
+
+func TestRelayPendingIgnoresListenerErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []*domain.AlertEvent{
+		randomAlertEvent(1, db.EventAlertCreated),
+		randomAlertEvent(2, db.EventAlertUpdated),
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PublishAlertEventsTX(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(events, nil)
+
+	failing, listener := &recordingPublisher{failOn: 1}, &recordingPublisher{}
+	relay := NewRelay(config.Config{}, zap.NewNop(), store, &recordingPublisher{}, failing, listener)
+
+	n, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []int64{2}, failing.published)
+	require.Equal(t, []int64{1, 2}, listener.published)
+}
+
+func TestPublishers(t *testing.T) {
+	event := randomAlertEvent(1, db.EventAlertCreated)
+
+	first, second := &recordingPublisher{}, &recordingPublisher{}
+	require.NoError(t, Publishers{first, second}.Publish(context.Background(), event))
+	require.Equal(t, []int64{1}, first.published)
+	require.Equal(t, []int64{1}, second.published)
+
+	failing, skipped := &recordingPublisher{failOn: 1}, &recordingPublisher{}
+	require.Error(t, Publishers{failing, skipped}.Publish(context.Background(), event))
+	require.Empty(t, skipped.published)
+}
+
+func randomAlertEvent(id int64, eventType string) *domain.AlertEvent {
+	return &domain.AlertEvent{
+		ID:              id,
+		ExternalID:      uuid.Must(uuid.NewV4()),
+		AlertID:         1,
+		AlertExternalID: uuid.Must(uuid.NewV4()),
+		EventType:       eventType,
+		Payload:         []byte(`{}`),
+	}
+}
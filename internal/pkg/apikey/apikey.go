@@ -0,0 +1,69 @@
+// Package apikey generates and recognizes API keys.
+//
+// A key reads "ask_<prefix>_<secret>". The prefix is stored as is to find the
+// key, while only a SHA-256 hash of the whole key is kept: the secret is long
+// and random, so a slow password hash would add nothing.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	scheme = "ask"
+
+	prefixLen = 6
+	secretLen = 32
+)
+
+var ErrMalformed = errors.New("malformed api key")
+
+// Key is a newly generated API key. Token is shown to its owner once; Prefix
+// and Hash are what is stored.
+type Key struct {
+	Token  string
+	Prefix string
+	Hash   string
+}
+
+// Generate creates a random key.
+func Generate() (Key, error) {
+	buf := make([]byte, prefixLen+secretLen)
+	if _, err := rand.Read(buf); err != nil {
+		return Key{}, err
+	}
+
+	prefix := hex.EncodeToString(buf[:prefixLen])
+	token := scheme + "_" + prefix + "_" + hex.EncodeToString(buf[prefixLen:])
+	return Key{Token: token, Prefix: prefix, Hash: Hash(token)}, nil
+}
+
+// Prefix returns the prefix of token, failing with ErrMalformed if token is
+// not shaped like a key.
+func Prefix(token string) (string, error) {
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != scheme ||
+		len(parts[1]) != 2*prefixLen || len(parts[2]) != 2*secretLen {
+		return "", ErrMalformed
+	}
+	if _, err := hex.DecodeString(parts[1] + parts[2]); err != nil {
+		return "", ErrMalformed
+	}
+	return parts[1], nil
+}
+
+// Hash returns the stored form of token.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether token hashes to hash.
+func Matches(token, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(token)), []byte(hash)) == 1
+}
@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := Generate()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key.Token, "ask_"+key.Prefix+"_"))
+	require.NotContains(t, key.Hash, key.Token)
+
+	prefix, err := Prefix(key.Token)
+	require.NoError(t, err)
+	require.Equal(t, key.Prefix, prefix)
+
+	require.True(t, Matches(key.Token, key.Hash))
+	require.False(t, Matches(key.Token+"0", key.Hash))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, key.Prefix, other.Prefix)
+	require.False(t, Matches(other.Token, key.Hash))
+}
+
+func TestPrefix(t *testing.T) {
+	key, err := Generate()
+	require.NoError(t, err)
+	secret := key.Token[len("ask_"+key.Prefix+"_"):]
+
+	for _, token := range []string{
+		"",
+		"ask_",
+		"ask_" + key.Prefix,
+		"sk_" + key.Prefix + "_" + secret,
+		"ask_" + key.Prefix + "_" + secret[1:],
+		"ask_" + key.Prefix + "_" + secret + "_x",
+		"ask_zzzzzzzzzzzz_" + secret,
+	} {
+		_, err := Prefix(token)
+		require.ErrorIs(t, err, ErrMalformed, token)
+	}
+}
@@ -0,0 +1,7 @@
+package jwt
+
+import "time"
+
+func SetNow(s *KeySet, now func() time.Time) {
+	s.now = now
+}
@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	minRSABits = 2048
+
+	maxJWKSSize = 1 << 20
+
+	// DefaultRefreshInterval is how long a key set is used before it is
+	// loaded again.
+	DefaultRefreshInterval = time.Hour
+	// minReloadInterval limits how often an unknown key id, or a source that
+	// is down, causes a reload.
+	minReloadInterval = time.Minute
+)
+
+// JWK is a JSON Web Key. Only the members of RSA and EC public keys are read.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKS reads the signing keys of a JWKS by key id. Keys of a type this
+// package cannot use are skipped, so that a key set may hold others.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch {
+		case jwk.Kty == "RSA":
+			key, err = jwk.rsaKey()
+		case jwk.Kty == "EC" && jwk.Crv == "P-256":
+			key, err = jwk.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk JWK) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n)}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("bad exponent")
+	}
+	key.E = int(exponent.Int64())
+
+	if key.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("rsa keys must have at least %d bits", minRSABits)
+	}
+	return key, nil
+}
+
+func (jwk JWK) ecKey() (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("bad coordinates")
+	}
+
+	// ecdh rejects points that are not on the curve
+	if _, err = ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+// KeySet is a JWKS loaded from a file or URL and cached for the refresh
+// interval. A token signed with a key the set does not have causes an early
+// reload, so rotated keys are picked up without waiting; while the source is
+// unavailable, the keys already loaded keep being used. Only one load runs at
+// a time, and it runs without holding the lock, so a slow source never holds
+// up tokens whose keys are already loaded.
+type KeySet struct {
+	fetch           func(ctx context.Context) ([]byte, error)
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	loading     *keySetLoad
+}
+
+// keySetLoad is a load of a KeySet in progress. err is set before done is
+// closed.
+type keySetLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewFileKeySet reads the key set from the file at path.
+func NewFileKeySet(path string, refreshInterval time.Duration) *KeySet {
+	return newKeySet(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}, refreshInterval)
+}
+
+// NewURLKeySet fetches the key set from url.
+func NewURLKeySet(url string, client *http.Client, refreshInterval time.Duration) *KeySet {
+	return newKeySet(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching jwks: unexpected status %d", res.StatusCode)
+		}
+		return io.ReadAll(io.LimitReader(res.Body, maxJWKSSize))
+	}, refreshInterval)
+}
+
+func newKeySet(fetch func(ctx context.Context) ([]byte, error), refreshInterval time.Duration) *KeySet {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &KeySet{fetch: fetch, refreshInterval: refreshInterval, now: time.Now}
+}
+
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	now := s.now()
+	key, ok := s.keys[kid]
+	fresh := ok && now.Sub(s.loadedAt) < s.refreshInterval
+	throttled := s.loading == nil && !s.attemptedAt.IsZero() && now.Sub(s.attemptedAt) < minReloadInterval
+	s.mu.Unlock()
+
+	switch {
+	case fresh:
+		return key, nil
+	case throttled:
+		if ok {
+			return key, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	if err := s.Load(ctx); err != nil {
+		if ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("%w: %w: %v", ErrUnknownKey, ErrKeySetUnavailable, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok = s.keys[kid]; !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// Load reads the key set now, reporting whether it can be loaded at all. If a
+// load is already in progress it waits for that one instead.
+func (s *KeySet) Load(ctx context.Context) error {
+	s.mu.Lock()
+	if load := s.loading; load != nil {
+		s.mu.Unlock()
+		select {
+		case <-load.done:
+			return load.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	load := &keySetLoad{done: make(chan struct{})}
+	s.loading = load
+	now := s.now()
+	s.attemptedAt = now
+	s.mu.Unlock()
+
+	// the load is shared, so it must not end with the request that started it
+	keys, err := s.load(context.WithoutCancel(ctx))
+
+	s.mu.Lock()
+	if err == nil {
+		s.keys = keys
+		s.loadedAt = now
+	}
+	s.loading = nil
+	s.mu.Unlock()
+
+	load.err = err
+	close(load.done)
+	return err
+}
+
+func (s *KeySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
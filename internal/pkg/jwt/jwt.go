@@ -0,0 +1,203 @@
+// Package jwt verifies JSON Web Tokens (RFC 7519) signed with RS256 or ES256
+// against the keys of a JWKS (RFC 7517).
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+var (
+	// ErrInvalidToken is returned for a token that is malformed, badly signed
+	// or whose claims do not hold.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrUnknownKey is returned by Keys for a key id it does not have.
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrKeySetUnavailable is returned along with ErrUnknownKey by a KeySet
+	// that could not be loaded to look for the key.
+	ErrKeySetUnavailable = errors.New("key set unavailable")
+)
+
+// Keys finds the public key a token names in its kid header.
+type Keys interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// Header is the JOSE header of a token.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Claims are the claims of a verified token.
+type Claims map[string]any
+
+// Parse verifies the signature of token with the key keys returns for it and
+// returns its claims. It does not check them; see Validator.
+func Parse(ctx context.Context, token string, keys Keys) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a signed JWT", ErrInvalidToken)
+	}
+
+	var header Header
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if header.Alg != RS256 && header.Alg != ES256 {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+
+	key, err := keys.Key(ctx, header.Kid)
+	if err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+		return nil, err
+	}
+
+	if err = verify(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+func verify(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch alg {
+	case RS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: key does not fit %s", ErrInvalidToken, alg)
+		}
+		if rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	case ES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().BitSize != 256 {
+			return fmt.Errorf("%w: key does not fit %s", ErrInvalidToken, alg)
+		}
+		if len(signature) != 64 {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Validator checks the registered claims of a token. Issuer and Audience are
+// only checked when set; exp is always required. Leeway allows for clock skew.
+type Validator struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+func (v Validator) Validate(claims Claims, now time.Time) error {
+	exp, ok := claims.time("exp")
+	if !ok {
+		return fmt.Errorf("%w: exp is required", ErrInvalidToken)
+	}
+	if !now.Before(exp.Add(v.Leeway)) {
+		return fmt.Errorf("%w: token has expired", ErrInvalidToken)
+	}
+	if nbf, ok := claims.time("nbf"); ok && now.Add(v.Leeway).Before(nbf) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+
+	if v.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.Issuer {
+			return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+		}
+	}
+	if v.Audience != "" && !slices.Contains(claims.Strings("aud"), v.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	seconds, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true
+}
+
+// String returns the claim at path, a dot separated list of names for claims
+// nested in objects, if it is a string.
+func (c Claims) String(path string) string {
+	s, _ := c.lookup(path).(string)
+	return s
+}
+
+// Strings returns the claim at path as a list. A string claim is split on
+// spaces, as the scope claim is, and a single value becomes a list of one.
+func (c Claims) Strings(path string) []string {
+	switch v := c.lookup(path).(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, element := range v {
+			if s, ok := element.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func (c Claims) lookup(path string) any {
+	var node any = map[string]any(c)
+	for _, name := range strings.Split(path, ".") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = object[name]
+	}
+	return node
+}
@@ -0,0 +1,305 @@
+package jwt_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt"
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt/jwttest"
+)
+
+func TestParse(t *testing.T) {
+	rsaKey, err := jwttest.NewRSAKey("rsa")
+	require.NoError(t, err)
+	ecKey, err := jwttest.NewECKey("ec")
+	require.NoError(t, err)
+	otherKey, err := jwttest.NewECKey("ec")
+	require.NoError(t, err)
+
+	keys := staticKeys(t, jwttest.JWKS(rsaKey, ecKey))
+	claims := map[string]any{"sub": "jdoe", "exp": time.Now().Add(time.Hour).Unix()}
+
+	for _, key := range []*jwttest.Key{rsaKey, ecKey} {
+		token, err := key.Sign(claims)
+		require.NoError(t, err)
+
+		got, err := jwt.Parse(context.Background(), token, keys)
+		require.NoError(t, err, key.Alg)
+		require.Equal(t, "jdoe", got.String("sub"))
+	}
+
+	forged, err := otherKey.Sign(claims)
+	require.NoError(t, err)
+	_, err = jwt.Parse(context.Background(), forged, keys)
+	require.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	token, err := rsaKey.Sign(claims)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	for name, tampered := range map[string]string{
+		"payload":     parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"root"}`)) + "." + parts[2],
+		"alg none":    base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa"}`)) + "." + parts[1] + ".",
+		"alg HS256":   base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"rsa"}`)) + "." + parts[1] + "." + parts[2],
+		"alg swap":    base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"rsa"}`)) + "." + parts[1] + "." + parts[2],
+		"unknown kid": base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"gone"}`)) + "." + parts[1] + "." + parts[2],
+		"two parts":   parts[0] + "." + parts[1],
+	} {
+		_, err = jwt.Parse(context.Background(), tampered, keys)
+		require.ErrorIs(t, err, jwt.ErrInvalidToken, name)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	now := time.Now()
+	validator := jwt.Validator{Issuer: "https://sso.example.com", Audience: "alert-service", Leeway: time.Minute}
+
+	valid := jwt.Claims{
+		"iss": "https://sso.example.com",
+		"aud": []any{"other", "alert-service"},
+		"exp": float64(now.Add(time.Hour).Unix()),
+		"nbf": float64(now.Add(-time.Hour).Unix()),
+	}
+	require.NoError(t, validator.Validate(valid, now))
+
+	with := func(name string, value any) jwt.Claims {
+		claims := jwt.Claims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	require.NoError(t, validator.Validate(with("aud", "alert-service"), now))
+	require.NoError(t, validator.Validate(with("exp", float64(now.Add(-30*time.Second).Unix())), now), "within leeway")
+
+	for name, claims := range map[string]jwt.Claims{
+		"expired":        with("exp", float64(now.Add(-time.Hour).Unix())),
+		"no exp":         with("exp", nil),
+		"not yet valid":  with("nbf", float64(now.Add(time.Hour).Unix())),
+		"wrong issuer":   with("iss", "https://evil.example.com"),
+		"wrong audience": with("aud", "other"),
+		"no audience":    with("aud", nil),
+	} {
+		require.ErrorIs(t, validator.Validate(claims, now), jwt.ErrInvalidToken, name)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	claims := jwt.Claims{
+		"sub":          "jdoe",
+		"scope":        "alerts:read alerts:write",
+		"roles":        []any{"responder", 1, "viewer"},
+		"realm_access": map[string]any{"roles": []any{"admin"}},
+	}
+
+	require.Equal(t, "jdoe", claims.String("sub"))
+	require.Equal(t, "", claims.String("roles"))
+	require.Equal(t, []string{"alerts:read", "alerts:write"}, claims.Strings("scope"))
+	require.Equal(t, []string{"responder", "viewer"}, claims.Strings("roles"))
+	require.Equal(t, []string{"admin"}, claims.Strings("realm_access.roles"))
+	require.Nil(t, claims.Strings("realm_access.groups"))
+	require.Nil(t, claims.Strings("sub.roles"))
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := jwttest.NewRSAKey("rsa")
+	require.NoError(t, err)
+
+	keys, err := jwt.ParseJWKS([]byte(`{"keys":[{"kty":"oct","kid":"hmac","k":"c2VjcmV0"},{"kty":"RSA","kid":"enc","use":"enc"}]}`))
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	keys, err = jwt.ParseJWKS(jwttest.JWKS(rsaKey))
+	require.NoError(t, err)
+	require.Contains(t, keys, "rsa")
+
+	small := rsaKey.JWK()
+	small.N = base64.RawURLEncoding.EncodeToString([]byte{0xc5, 0x01})
+	_, err = jwt.ParseJWKS([]byte(`{"keys":[{"kty":"RSA","kid":"small","n":"` + small.N + `","e":"AQAB"}]}`))
+	require.Error(t, err)
+
+	_, err = jwt.ParseJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-256","kid":"off","x":"` +
+		base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `","y":"` +
+		base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `"}]}`))
+	require.Error(t, err, "point not on the curve")
+}
+
+func TestURLKeySetRotation(t *testing.T) {
+	oldKey, err := jwttest.NewECKey("2024")
+	require.NoError(t, err)
+	newKey, err := jwttest.NewECKey("2025")
+	require.NoError(t, err)
+
+	var (
+		published atomic.Value
+		down      atomic.Bool
+		fetches   atomic.Int32
+	)
+	published.Store(jwttest.JWKS(oldKey))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(published.Load().([]byte))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keys := jwt.NewURLKeySet(server.URL, server.Client(), time.Hour)
+	jwt.SetNow(keys, func() time.Time { return now })
+	ctx := context.Background()
+
+	_, err = keys.Key(ctx, "2024")
+	require.NoError(t, err)
+	_, err = keys.Key(ctx, "2024")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, fetches.Load(), "expected the key set to be cached")
+
+	// the issuer rotates in a new key, but reloads are spaced out
+	published.Store(jwttest.JWKS(oldKey, newKey))
+	_, err = keys.Key(ctx, "2025")
+	require.ErrorIs(t, err, jwt.ErrUnknownKey)
+	require.EqualValues(t, 1, fetches.Load())
+
+	now = now.Add(2 * time.Minute)
+	_, err = keys.Key(ctx, "2025")
+	require.NoError(t, err, "expected an unknown key id to reload the set")
+	require.EqualValues(t, 2, fetches.Load())
+
+	// the old key is retired, and dropped at the next refresh
+	published.Store(jwttest.JWKS(newKey))
+	_, err = keys.Key(ctx, "2024")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	_, err = keys.Key(ctx, "2024")
+	require.ErrorIs(t, err, jwt.ErrUnknownKey)
+	require.EqualValues(t, 3, fetches.Load())
+
+	// keys already loaded outlive an outage of the source
+	down.Store(true)
+	now = now.Add(2 * time.Hour)
+	_, err = keys.Key(ctx, "2025")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, fetches.Load())
+}
+
+func TestURLKeySetUnavailable(t *testing.T) {
+	key, err := jwttest.NewRSAKey("2024")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	keys := jwt.NewURLKeySet(server.URL, server.Client(), time.Hour)
+
+	// a source that is down from the start turns tokens away as invalid
+	_, err = keys.Key(context.Background(), "2024")
+	require.ErrorIs(t, err, jwt.ErrUnknownKey)
+	require.ErrorIs(t, err, jwt.ErrKeySetUnavailable)
+
+	token, err := key.Sign(map[string]any{"sub": "jdoe"})
+	require.NoError(t, err)
+	_, err = jwt.Parse(context.Background(), token, jwt.NewURLKeySet(server.URL, server.Client(), time.Hour))
+	require.ErrorIs(t, err, jwt.ErrInvalidToken)
+	require.ErrorIs(t, err, jwt.ErrKeySetUnavailable)
+}
+
+func TestURLKeySetConcurrentLoad(t *testing.T) {
+	oldKey, err := jwttest.NewRSAKey("2024")
+	require.NoError(t, err)
+	newKey, err := jwttest.NewRSAKey("2025")
+	require.NoError(t, err)
+
+	var (
+		published atomic.Value
+		fetches   atomic.Int32
+		started   = make(chan struct{}, 1)
+		release   = make(chan struct{})
+	)
+	published.Store(jwttest.JWKS(oldKey))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			started <- struct{}{}
+			<-release
+		}
+		_, _ = w.Write(published.Load().([]byte))
+	}))
+	defer server.Close()
+
+	var elapsed atomic.Int64
+	now := time.Now()
+	keys := jwt.NewURLKeySet(server.URL, server.Client(), time.Hour)
+	jwt.SetNow(keys, func() time.Time { return now.Add(time.Duration(elapsed.Load())) })
+	ctx := context.Background()
+
+	require.NoError(t, keys.Load(ctx))
+	published.Store(jwttest.JWKS(oldKey, newKey))
+	elapsed.Store(int64(2 * time.Minute))
+
+	errs := make(chan error, 2)
+	lookup := func() {
+		_, err := keys.Key(ctx, "2025")
+		errs <- err
+	}
+	go lookup()
+	<-started
+
+	// a key already loaded is served while the reload is stuck
+	_, err = keys.Key(ctx, "2024")
+	require.NoError(t, err)
+
+	// a second lookup of the new key waits for the same reload
+	go lookup()
+	require.Never(t, func() bool { return len(errs) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+	close(release)
+
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	require.EqualValues(t, 2, fetches.Load())
+}
+
+func TestFileKeySet(t *testing.T) {
+	key, err := jwttest.NewRSAKey("file")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwttest.JWKS(key), 0o600))
+
+	keys := jwt.NewFileKeySet(path, time.Hour)
+	require.NoError(t, keys.Load(context.Background()))
+
+	_, err = keys.Key(context.Background(), "file")
+	require.NoError(t, err)
+
+	missing := jwt.NewFileKeySet(filepath.Join(t.TempDir(), "missing.json"), time.Hour)
+	require.Error(t, missing.Load(context.Background()))
+}
+
+func staticKeys(t *testing.T, jwks []byte) jwt.Keys {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+	return jwt.NewFileKeySet(path, time.Hour)
+}
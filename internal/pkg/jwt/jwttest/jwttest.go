@@ -0,0 +1,114 @@
+// Package jwttest generates signing keys and tokens for testing code that
+// verifies JWTs.
+package jwttest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/josephlbailey/alert-service/internal/pkg/jwt"
+)
+
+// Key is a private signing key with its key id.
+type Key struct {
+	Kid    string
+	Alg    string
+	Signer crypto.Signer
+}
+
+// NewRSAKey generates an RS256 key.
+func NewRSAKey(kid string) (*Key, error) {
+	signer, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{Kid: kid, Alg: jwt.RS256, Signer: signer}, nil
+}
+
+// NewECKey generates an ES256 key.
+func NewECKey(kid string) (*Key, error) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{Kid: kid, Alg: jwt.ES256, Signer: signer}, nil
+}
+
+// Sign issues a token with claims.
+func (k *Key) Sign(claims map[string]any) (string, error) {
+	header, err := json.Marshal(jwt.Header{Alg: k.Alg, Kid: k.Kid, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch signer := k.Signer.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, signer, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, signer, digest[:])
+		signature = make([]byte, 64)
+		if err == nil {
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// JWK returns the public half of the key.
+func (k *Key) JWK() jwt.JWK {
+	switch public := k.Signer.Public().(type) {
+	case *rsa.PublicKey:
+		return jwt.JWK{
+			Kty: "RSA",
+			Kid: k.Kid,
+			Use: "sig",
+			Alg: k.Alg,
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		x, y := make([]byte, 32), make([]byte, 32)
+		public.X.FillBytes(x)
+		public.Y.FillBytes(y)
+		return jwt.JWK{
+			Kty: "EC",
+			Kid: k.Kid,
+			Use: "sig",
+			Alg: k.Alg,
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(x),
+			Y:   base64.RawURLEncoding.EncodeToString(y),
+		}
+	}
+	return jwt.JWK{}
+}
+
+// JWKS returns the key set publishing keys.
+func JWKS(keys ...*Key) []byte {
+	set := jwt.JWKS{Keys: make([]jwt.JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	data, _ := json.Marshal(set)
+	return data
+}
@@ -0,0 +1,40 @@
+package labels
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var nameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// valueEscaper escapes the separator in label values, and the escape character
+// itself so that an escaped separator cannot be forged.
+var valueEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`)
+
+// ValidName reports whether name is a valid Prometheus label name. Names
+// beginning with a double underscore are reserved.
+func ValidName(name string) bool {
+	return nameRe.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+// Canonical renders a label set in a stable form, sorted by name, suitable for
+// hashing or use as a grouping key.
+func Canonical(ls map[string]string) string {
+	names := make([]string, 0, len(ls))
+	for name := range ls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		valueEscaper.WriteString(&b, ls[name])
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package labels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+var ErrInvalidMatcher = errors.New("invalid matcher")
+
+// Matcher selects alerts by the value of one label, following Prometheus
+// semantics: a missing label matches as the empty string and regular
+// expressions are fully anchored.
+type Matcher struct {
+	Name  string    `json:"name"`
+	Type  MatchType `json:"type"`
+	Value string    `json:"value"`
+
+	re *regexp.Regexp
+}
+
+func NewMatcher(t MatchType, name, value string) (*Matcher, error) {
+	m := &Matcher{Name: name, Type: t, Value: value}
+	if err := m.compile(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Matcher) compile() (err error) {
+	if !ValidName(m.Name) {
+		return fmt.Errorf("%w: invalid label name %q", ErrInvalidMatcher, m.Name)
+	}
+	switch m.Type {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		m.re, err = regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMatcher, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown match type %q", ErrInvalidMatcher, m.Type)
+	}
+	return nil
+}
+
+// ParseMatcher parses a matcher such as env="prod" or service=~"api-.*". The
+// value may be left unquoted.
+func ParseMatcher(s string) (*Matcher, error) {
+	i := strings.IndexAny(s, "=!")
+	if i <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMatcher, s)
+	}
+	name, rest := strings.TrimSpace(s[:i]), s[i:]
+
+	var t MatchType
+	for _, candidate := range []MatchType{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+		if strings.HasPrefix(rest, string(candidate)) {
+			t = candidate
+			break
+		}
+	}
+	if t == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMatcher, s)
+	}
+
+	value := strings.TrimSpace(rest[len(t):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMatcher, s)
+		}
+		value = unquoted
+	}
+	return NewMatcher(t, name, value)
+}
+
+func (m *Matcher) Matches(value string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	case MatchNotRegexp:
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// IsRegexp reports whether the matcher matches by regular expression. These
+// follow Go's RE2 syntax, so they cannot be handed to the database.
+func (m *Matcher) IsRegexp() bool {
+	return m.Type == MatchRegexp || m.Type == MatchNotRegexp
+}
+
+func (m *Matcher) String() string {
+	return m.Name + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// UnmarshalJSON compiles the matcher as it is decoded so that stored matchers
+// are ready to use.
+func (m *Matcher) UnmarshalJSON(data []byte) error {
+	type plain Matcher
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+	return m.compile()
+}
+
+type Matchers []*Matcher
+
+// Matches reports whether every matcher matches the label set.
+func (ms Matchers) Matches(ls map[string]string) bool {
+	for _, m := range ms {
+		if !m.Matches(ls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func ParseMatchers(ss []string) (Matchers, error) {
+	ms := make(Matchers, 0, len(ss))
+	for _, s := range ss {
+		m, err := ParseMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
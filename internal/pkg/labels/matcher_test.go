@@ -0,0 +1,92 @@
+package labels
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMatcher(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    Matcher
+		wantErr bool
+	}{
+		{input: "env=prod", want: Matcher{Name: "env", Type: MatchEqual, Value: "prod"}},
+		{input: `env="prod"`, want: Matcher{Name: "env", Type: MatchEqual, Value: "prod"}},
+		{input: `env!=prod`, want: Matcher{Name: "env", Type: MatchNotEqual, Value: "prod"}},
+		{input: `service=~"api-.*"`, want: Matcher{Name: "service", Type: MatchRegexp, Value: "api-.*"}},
+		{input: `service!~api-.*`, want: Matcher{Name: "service", Type: MatchNotRegexp, Value: "api-.*"}},
+		{input: `url="a=b"`, want: Matcher{Name: "url", Type: MatchEqual, Value: "a=b"}},
+		{input: "=prod", wantErr: true},
+		{input: "env", wantErr: true},
+		{input: "1env=prod", wantErr: true},
+		{input: "__name__=up", wantErr: true},
+		{input: `service=~"("`, wantErr: true},
+		{input: `env="prod`, wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			m, err := ParseMatcher(testCase.input)
+			if testCase.wantErr {
+				require.ErrorIs(t, err, ErrInvalidMatcher)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.want.Name, m.Name)
+			require.Equal(t, testCase.want.Type, m.Type)
+			require.Equal(t, testCase.want.Value, m.Value)
+		})
+	}
+}
+
+func TestMatchersMatches(t *testing.T) {
+	ls := map[string]string{"env": "prod", "service": "api-gateway"}
+
+	testCases := []struct {
+		name     string
+		matchers []string
+		want     bool
+	}{
+		{name: "no matchers", want: true},
+		{name: "equal", matchers: []string{"env=prod"}, want: true},
+		{name: "not equal", matchers: []string{"env!=prod"}, want: false},
+		{name: "anchored regexp", matchers: []string{"service=~api"}, want: false},
+		{name: "full regexp", matchers: []string{"service=~api-.*", "env=prod"}, want: true},
+		{name: "negative regexp", matchers: []string{"service!~db-.*"}, want: true},
+		{name: "missing label is empty", matchers: []string{"team="}, want: true},
+		{name: "missing label does not equal value", matchers: []string{"team=sre"}, want: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ms, err := ParseMatchers(testCase.matchers)
+			require.NoError(t, err)
+			require.Equal(t, testCase.want, ms.Matches(ls))
+		})
+	}
+}
+
+func TestMatcherJSON(t *testing.T) {
+	m, err := ParseMatcher("service=~api-.*")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(Matchers{m})
+	require.NoError(t, err)
+
+	var got Matchers
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.True(t, got.Matches(map[string]string{"service": "api-gateway"}))
+
+	require.Error(t, json.Unmarshal([]byte(`[{"name":"service","type":"=~","value":"("}]`), &got))
+}
+
+func TestCanonical(t *testing.T) {
+	require.Equal(t, "a=1,b=2", Canonical(map[string]string{"b": "2", "a": "1"}))
+	require.NotEqual(t, Canonical(map[string]string{"a": "1,b=2"}), Canonical(map[string]string{"a": "1", "b": "2"}))
+	require.Equal(t, `a=x\\,b=1`, Canonical(map[string]string{"a": `x\`, "b": "1"}))
+	require.NotEqual(t, Canonical(map[string]string{"a": "x,b=1"}), Canonical(map[string]string{"a": `x\`, "b": "1"}))
+	require.Equal(t, "", Canonical(nil))
+}
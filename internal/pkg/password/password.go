@@ -0,0 +1,141 @@
+// Package password hashes and verifies passwords with bcrypt or argon2id.
+//
+// Hashes are kept in their usual text form: the modular crypt format for
+// bcrypt ("$2a$...") and the PHC string format for argon2id
+// ("$argon2id$v=19$m=65536,t=3,p=2$salt$key"), so the algorithm and its
+// parameters travel with the hash.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	Bcrypt   = "bcrypt"
+	Argon2id = "argon2id"
+)
+
+var (
+	ErrMismatch    = errors.New("password does not match")
+	ErrInvalidHash = errors.New("invalid password hash")
+)
+
+// Argon2Params are the argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultArgon2Params follow the second recommended option of RFC 9106.
+var DefaultArgon2Params = Argon2Params{
+	Memory:  64 * 1024,
+	Time:    3,
+	Threads: 4,
+	SaltLen: 16,
+	KeyLen:  32,
+}
+
+// HashBcrypt hashes password with bcrypt at the given cost.
+func HashBcrypt(password string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// HashArgon2id hashes password with argon2id and a random salt.
+func HashArgon2id(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory,
+		params.Time,
+		params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against hash, returning ErrMismatch if it is wrong
+// and ErrInvalidHash if hash is not a bcrypt or argon2id hash.
+func Verify(hash, password string) error {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatch
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+		}
+		return nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return err
+		}
+		got := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return ErrMismatch
+		}
+		return nil
+	}
+	return ErrInvalidHash
+}
+
+// Check reports whether hash is a well formed bcrypt or argon2id hash.
+func Check(hash string) error {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+		}
+		return nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		_, _, _, err := decodeArgon2id(hash)
+		return err
+	}
+	return ErrInvalidHash
+}
+
+func decodeArgon2id(hash string) (params Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported argon2 version", ErrInvalidHash)
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if params.Memory == 0 || params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, fmt.Errorf("%w: argon2 parameters must be positive", ErrInvalidHash)
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("%w: bad key", ErrInvalidHash)
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,48 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2Params = Argon2Params{Memory: 64, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32}
+
+func TestBcrypt(t *testing.T) {
+	hash, err := HashBcrypt("s3cret", bcrypt.MinCost)
+	require.NoError(t, err)
+	require.NoError(t, Check(hash))
+
+	require.NoError(t, Verify(hash, "s3cret"))
+	require.ErrorIs(t, Verify(hash, "wrong"), ErrMismatch)
+}
+
+func TestArgon2id(t *testing.T) {
+	hash, err := HashArgon2id("s3cret", testArgon2Params)
+	require.NoError(t, err)
+	require.Regexp(t, `^\$argon2id\$v=19\$m=64,t=1,p=1\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, hash)
+	require.NoError(t, Check(hash))
+
+	require.NoError(t, Verify(hash, "s3cret"))
+	require.ErrorIs(t, Verify(hash, "wrong"), ErrMismatch)
+
+	other, err := HashArgon2id("s3cret", testArgon2Params)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "expected a random salt")
+}
+
+func TestInvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"s3cret",
+		"$2a$04$short",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$!!",
+	} {
+		require.ErrorIs(t, Check(hash), ErrInvalidHash, hash)
+		require.ErrorIs(t, Verify(hash, "s3cret"), ErrInvalidHash, hash)
+	}
+}
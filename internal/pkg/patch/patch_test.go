@@ -0,0 +1,191 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "remove member", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "replace array", doc: `{"a":["b"]}`, patch: `{"a":["c"]}`, want: `{"a":["c"]}`},
+		{name: "merge nested", doc: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"b":"f","d":null}}`, want: `{"a":{"b":"f"}}`},
+		{name: "object over scalar", doc: `{"a":"b"}`, patch: `{"a":{"c":"d"}}`, want: `{"a":{"c":"d"}}`},
+		{name: "object over null", doc: `{"a":null}`, patch: `{"a":{"b":"c","d":null}}`, want: `{"a":{"b":"c"}}`},
+		{name: "replace document", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Merge([]byte(testCase.doc), []byte(testCase.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.want, string(got))
+		})
+	}
+
+	_, err := Merge([]byte(`{}`), []byte(`{`))
+	require.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "add member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:  "add array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "append array element",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc"]}]`,
+			want:  `{"foo":["bar",["abc"]]}`,
+		},
+		{
+			name:  "remove member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "remove array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "replace member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "move member",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "move array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:  "copy member",
+			doc:   `{"foo":{"bar":"baz"}}`,
+			patch: `[{"op":"copy","from":"/foo","path":"/qux"},{"op":"add","path":"/qux/bar","value":"x"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"bar":"x"}}`,
+		},
+		{
+			name:  "test passes",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:  "escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`,
+			want:  `{"m~n":3}`,
+		},
+		{
+			name:  "add null value",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/foo","value":null}]`,
+			want:  `{"foo":null}`,
+		},
+		{
+			name:    "test fails",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "remove missing member",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"/baz"}]`,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "add to missing parent",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "array index out of bounds",
+			doc:     `{"foo":["bar"]}`,
+			patch:   `[{"op":"add","path":"/foo/2","value":"qux"}]`,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "array index with leading zero",
+			doc:     `{"foo":["bar","baz"]}`,
+			patch:   `[{"op":"remove","path":"/foo/01"}]`,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"replace","path":"/foo"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"upsert","path":"/foo","value":"baz"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "pointer without slash",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"foo"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "move into itself",
+			doc:     `{"foo":{"bar":"baz"}}`,
+			patch:   `[{"op":"move","from":"/foo","path":"/foo/bar"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "patch is not an array",
+			doc:     `{"foo":"bar"}`,
+			patch:   `{"op":"remove","path":"/foo"}`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Apply([]byte(testCase.doc), []byte(testCase.patch))
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.want, string(got))
+		})
+	}
+}
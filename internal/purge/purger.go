@@ -0,0 +1,113 @@
+package purge
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	defaultRetention    = 30 * 24 * time.Hour
+	defaultPollInterval = time.Hour
+	defaultBatchSize    = 500
+)
+
+// Purger permanently removes alerts that have been deleted for longer than
+// the retention period, keeping their history, along with expired
+// idempotency keys.
+type Purger struct {
+	store  db.Store
+	logger *zap.Logger
+	config config.PurgeConfig
+	now    func() time.Time
+}
+
+func NewPurger(config config.Config, logger *zap.Logger, store db.Store) *Purger {
+	c := config.Purge
+	if c.Retention <= 0 {
+		c.Retention = defaultRetention
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+
+	return &Purger{
+		store:  store,
+		logger: logger,
+		config: c,
+		now:    time.Now,
+	}
+}
+
+// Run purges expired alerts every poll interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	p.logger.Info("starting alert purger...",
+		zap.Duration("retention", p.config.Retention),
+		zap.Duration("pollInterval", p.config.PollInterval),
+	)
+
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("alert purger stopped")
+			return
+		case <-ticker.C:
+			if _, err := p.PurgeIdempotencyKeys(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Error("error purging idempotency keys", zap.Error(err))
+			}
+
+			for {
+				n, err := p.PurgeExpired(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						p.logger.Error("error purging deleted alerts", zap.Error(err))
+					}
+					break
+				}
+				if n < int64(p.config.BatchSize) {
+					break
+				}
+			}
+		}
+	}
+}
+
+// PurgeExpired removes one batch of alerts deleted before the retention
+// period, returning how many were removed.
+func (p *Purger) PurgeExpired(ctx context.Context) (int64, error) {
+	n, err := p.store.PurgeDeletedAlerts(ctx, domain.PurgeDeletedAlertsParams{
+		DeletedBefore: p.now().Add(-p.config.Retention),
+		BatchSize:     p.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		p.logger.Info("purged deleted alerts.", zap.Int64("count", n))
+	}
+	return n, nil
+}
+
+// PurgeIdempotencyKeys removes the idempotency keys that have expired,
+// returning how many were removed.
+func (p *Purger) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	n, err := p.store.PurgeExpiredIdempotencyKeys(ctx, p.now())
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		p.logger.Info("purged idempotency keys.", zap.Int64("count", n))
+	}
+	return n, nil
+}
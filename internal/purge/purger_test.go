@@ -0,0 +1,74 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestPurgeExpired(t *testing.T) {
+	now := time.Date(2024, 8, 24, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			PurgeDeletedAlerts(gomock.Any(), gomock.Eq(domain.PurgeDeletedAlertsParams{
+				DeletedBefore: now.Add(-48 * time.Hour),
+				BatchSize:     100,
+			})).
+			Return(int64(7), nil),
+		store.EXPECT().
+			PurgeDeletedAlerts(gomock.Any(), gomock.Any()).
+			Return(int64(0), errors.New("connection refused")),
+	)
+
+	purger := NewPurger(config.Config{Purge: config.PurgeConfig{Retention: 48 * time.Hour, BatchSize: 100}}, zap.NewNop(), store)
+	purger.now = func() time.Time { return now }
+
+	n, err := purger.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+
+	_, err = purger.PurgeExpired(context.Background())
+	require.Error(t, err)
+}
+
+func TestNewPurgerDefaults(t *testing.T) {
+	purger := NewPurger(config.Config{}, zap.NewNop(), nil)
+
+	require.Equal(t, defaultRetention, purger.config.Retention)
+	require.Equal(t, defaultPollInterval, purger.config.PollInterval)
+	require.Equal(t, int32(defaultBatchSize), purger.config.BatchSize)
+}
+
+func TestPurgeIdempotencyKeys(t *testing.T) {
+	now := time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PurgeExpiredIdempotencyKeys(gomock.Any(), gomock.Eq(now)).
+		Times(1).
+		Return(int64(12), nil)
+
+	purger := NewPurger(config.Config{}, zap.NewNop(), store)
+	purger.now = func() time.Time { return now }
+
+	n, err := purger.PurgeIdempotencyKeys(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(12), n)
+}
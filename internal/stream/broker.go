@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// subscriptionBuffer is how many events a subscriber may fall behind by
+// before it is dropped.
+const subscriptionBuffer = 64
+
+// Broker fans published alert events out to live subscribers. It is an
+// outbox.Relay listener, so subscribers only ever see events whose publication
+// has been committed and whose sequence numbers are final.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives events on C. C is closed when the subscription is
+// dropped for falling behind, after which the subscriber should resume from
+// the persisted events.
+type Subscription struct {
+	C <-chan *domain.AlertEvent
+	c chan *domain.AlertEvent
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[*Subscription]struct{})}
+}
+
+func (b *Broker) Subscribe() *Subscription {
+	c := make(chan *domain.AlertEvent, subscriptionBuffer)
+	sub := &Subscription{C: c, c: c}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// Subscribers returns the number of live subscriptions.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Publish never blocks on a subscriber: one whose buffer is full is dropped
+// instead, so a slow client cannot hold up the outbox relay.
+func (b *Broker) Publish(_ context.Context, event *domain.AlertEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.c <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+	return nil
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.c)
+	}
+}
@@ -0,0 +1,47 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+
+	first := broker.Subscribe()
+	second := broker.Subscribe()
+	require.Equal(t, 2, broker.Subscribers())
+
+	event := &domain.AlertEvent{ID: 1}
+	require.NoError(t, broker.Publish(context.Background(), event))
+	require.Same(t, event, <-first.C)
+	require.Same(t, event, <-second.C)
+
+	broker.Unsubscribe(first)
+	require.Equal(t, 1, broker.Subscribers())
+	_, open := <-first.C
+	require.False(t, open)
+
+	// unsubscribing twice is harmless
+	broker.Unsubscribe(first)
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	slow := broker.Subscribe()
+
+	for i := 0; i <= subscriptionBuffer; i++ {
+		require.NoError(t, broker.Publish(context.Background(), &domain.AlertEvent{ID: int64(i)}))
+	}
+	require.Zero(t, broker.Subscribers())
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	require.Equal(t, subscriptionBuffer, received)
+}
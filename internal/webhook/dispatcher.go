@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+const (
+	defaultPollInterval   = 2 * time.Second
+	defaultBatchSize      = 20
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 10 * time.Second
+	defaultMaxBackoff     = time.Hour
+	defaultTimeout        = 10 * time.Second
+
+	// maxErrorLength bounds the response excerpt kept in the delivery log.
+	maxErrorLength = 512
+)
+
+// Dispatcher sends queued webhook deliveries. Deliveries are claimed with a
+// lease so that several instances can run side by side, and failed attempts
+// are retried with exponential backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	store  db.Store
+	logger *zap.Logger
+	client *http.Client
+	config config.WebhookConfig
+	now    func() time.Time
+}
+
+func NewDispatcher(config config.Config, logger *zap.Logger, store db.Store) *Dispatcher {
+	c := config.Webhooks
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaultInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	return &Dispatcher{
+		store:  store,
+		logger: logger,
+		client: &http.Client{Timeout: c.Timeout},
+		config: c,
+		now:    time.Now,
+	}
+}
+
+// Run dispatches due deliveries every poll interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("starting webhook dispatcher...", zap.Duration("pollInterval", d.config.PollInterval))
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+				d.logger.Error("error dispatching webhook deliveries", zap.Error(err))
+			}
+		}
+	}
+}
+
+// DispatchDue claims and sends one batch of due deliveries, returning how many
+// were attempted.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := d.now()
+
+	// the lease outlives the request timeout so a delivery is not picked up
+	// again while it is still in flight
+	deliveries, err := d.store.ClaimDueWebhookDeliveries(ctx, domain.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: now.Add(2 * d.config.Timeout),
+		Now:        now,
+		BatchSize:  d.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[int32]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.store.GetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return 0, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if err = d.deliver(ctx, subscription, delivery); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) error {
+	attempt := domain.RecordWebhookDeliveryAttemptParams{
+		ID:          delivery.ID,
+		AttemptedAt: d.now(),
+	}
+
+	if !subscription.Active {
+		attempt.Status = db.DeliveryFailed
+		attempt.NextAttemptAt = attempt.AttemptedAt
+		attempt.LastError = pgtype.Text{String: "subscription is inactive", Valid: true}
+		return d.store.RecordWebhookDeliveryAttempt(ctx, attempt)
+	}
+
+	status, err := d.send(ctx, subscription, delivery)
+	if status != 0 {
+		attempt.ResponseStatus = pgtype.Int4{Int32: int32(status), Valid: true}
+	}
+
+	if err == nil {
+		attempt.Status = db.DeliverySucceeded
+		attempt.NextAttemptAt = attempt.AttemptedAt
+		d.logger.Info("delivered webhook.",
+			zap.String("delivery", delivery.ExternalID.String()),
+			zap.String("eventType", delivery.EventType),
+			zap.Int("status", status),
+		)
+		return d.store.RecordWebhookDeliveryAttempt(ctx, attempt)
+	}
+
+	attempt.LastError = pgtype.Text{String: err.Error(), Valid: true}
+	if delivery.Attempts+1 >= d.config.MaxAttempts {
+		attempt.Status = db.DeliveryFailed
+		attempt.NextAttemptAt = attempt.AttemptedAt
+		d.logger.Warn("giving up on webhook delivery",
+			zap.String("delivery", delivery.ExternalID.String()),
+			zap.Int32("attempts", delivery.Attempts+1),
+			zap.Error(err),
+		)
+	} else {
+		attempt.Status = db.DeliveryPending
+		attempt.NextAttemptAt = attempt.AttemptedAt.Add(d.backoff(delivery.Attempts + 1))
+		d.logger.Warn("webhook delivery failed, retrying",
+			zap.String("delivery", delivery.ExternalID.String()),
+			zap.Int32("attempts", delivery.Attempts+1),
+			zap.Time("nextAttemptAt", attempt.NextAttemptAt),
+			zap.Error(err),
+		)
+	}
+	return d.store.RecordWebhookDeliveryAttempt(ctx, attempt)
+}
+
+// send POSTs the delivery payload. Any response outside 2xx is an error; the
+// returned status is zero if no response was received.
+func (d *Dispatcher) send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "alert-service-webhook")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ExternalID.String())
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, excerpt)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the given attempt is retried: the initial
+// backoff doubled for every previous attempt, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/josephlbailey/alert-service/config"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestDispatchDue(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"type":"alert.created"}`)
+
+	testCases := []struct {
+		name     string
+		status   int
+		attempts int32
+		active   bool
+		check    func(t *testing.T, p domain.RecordWebhookDeliveryAttemptParams)
+	}{
+		{
+			name:   "successful delivery is recorded",
+			status: http.StatusNoContent,
+			active: true,
+			check: func(t *testing.T, p domain.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, db.DeliverySucceeded, p.Status)
+				require.Equal(t, int32(http.StatusNoContent), p.ResponseStatus.Int32)
+				require.False(t, p.LastError.Valid)
+			},
+		},
+		{
+			name:     "failed delivery is retried with backoff",
+			status:   http.StatusInternalServerError,
+			attempts: 2,
+			active:   true,
+			check: func(t *testing.T, p domain.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, db.DeliveryPending, p.Status)
+				require.Equal(t, int32(http.StatusInternalServerError), p.ResponseStatus.Int32)
+				require.True(t, p.LastError.Valid)
+				// third attempt: 1s doubled twice
+				require.Equal(t, now.Add(4*time.Second), p.NextAttemptAt)
+			},
+		},
+		{
+			name:     "delivery fails after max attempts",
+			status:   http.StatusBadGateway,
+			attempts: 4,
+			active:   true,
+			check: func(t *testing.T, p domain.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, db.DeliveryFailed, p.Status)
+				require.Contains(t, p.LastError.String, "502")
+			},
+		},
+		{
+			name:   "delivery to inactive subscription fails without sending",
+			status: http.StatusOK,
+			active: false,
+			check: func(t *testing.T, p domain.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, db.DeliveryFailed, p.Status)
+				require.False(t, p.ResponseStatus.Valid)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			delivery := &domain.WebhookDelivery{
+				ID:             7,
+				ExternalID:     uuid.Must(uuid.NewV4()),
+				SubscriptionID: 3,
+				EventType:      db.EventAlertCreated,
+				Payload:        payload,
+				Status:         db.DeliveryPending,
+				Attempts:       testCase.attempts,
+			}
+
+			received := 0
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received++
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, payload, body)
+				require.Equal(t, db.EventAlertCreated, r.Header.Get(HeaderEvent))
+				require.Equal(t, delivery.ExternalID.String(), r.Header.Get(HeaderDelivery))
+
+				timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+				require.NoError(t, err)
+				require.Equal(t, now.Unix(), timestamp)
+				require.True(t, Verify(testSecret, timestamp, body, r.Header.Get(HeaderSignature)))
+
+				w.WriteHeader(testCase.status)
+			}))
+			defer receiver.Close()
+
+			subscription := &domain.WebhookSubscription{
+				ID:     3,
+				Url:    receiver.URL,
+				Secret: testSecret,
+				Active: testCase.active,
+			}
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]*domain.WebhookDelivery{delivery}, nil)
+			store.EXPECT().
+				GetWebhookSubscriptionByID(gomock.Any(), gomock.Eq(int32(3))).
+				Times(1).
+				Return(subscription, nil)
+			store.EXPECT().
+				RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, p domain.RecordWebhookDeliveryAttemptParams) error {
+					require.Equal(t, delivery.ID, p.ID)
+					testCase.check(t, p)
+					return nil
+				})
+
+			dispatcher := newTestDispatcher(store, now)
+			n, err := dispatcher.DispatchDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+
+			if testCase.active {
+				require.Equal(t, 1, received)
+			} else {
+				require.Zero(t, received)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := newTestDispatcher(nil, time.Now())
+
+	require.Equal(t, time.Second, dispatcher.backoff(1))
+	require.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	require.Equal(t, 8*time.Second, dispatcher.backoff(4))
+	require.Equal(t, 10*time.Second, dispatcher.backoff(5))
+	require.Equal(t, 10*time.Second, dispatcher.backoff(30))
+}
+
+func newTestDispatcher(store db.Store, now time.Time) *Dispatcher {
+	dispatcher := NewDispatcher(config.Config{
+		Webhooks: config.WebhookConfig{
+			MaxAttempts:    5,
+			InitialBackoff: time.Second,
+			MaxBackoff:     10 * time.Second,
+			Timeout:        time.Second,
+		},
+	}, zap.NewNop(), store)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+
+	"github.com/josephlbailey/alert-service/internal/api/models"
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+)
+
+// Event is the JSON body POSTed to subscribers, the same shape as the alert
+// stream's events. ID is the outbox event's external id, so receivers can use
+// it to discard duplicates.
+type Event = models.AlertEventRes
+
+// Publisher queues a delivery for every active subscription to an alert
+// event's type made by the organization of the alert. The deliveries are sent
+// by the Dispatcher.
+type Publisher struct {
+	store db.Store
+}
+
+func NewPublisher(store db.Store) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event *domain.AlertEvent) error {
+	alert, err := db.EventAlert(event)
+	if err != nil {
+		return err
+	}
+
+	orgID := event.OrgID
+
+	subscriptions, err := p.store.ListActiveWebhookSubscriptionsForEvent(ctx, domain.ListActiveWebhookSubscriptionsForEventParams{
+		OrgID:     orgID,
+		EventType: event.EventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	// silenced and inhibited alerts still reach the stream, but nobody is
+	// paged for them
+	if db.AlertSilenced(alert, event.CreatedAt) || db.AlertInhibited(alert) {
+		return nil
+	}
+
+	payload, err := json.Marshal(models.NewAlertEventResponse(event, alert))
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		// the query filters on the organization too, this keeps a slip there
+		// from sending alerts to another organization's endpoint
+		if subscription.OrgID != orgID {
+			continue
+		}
+
+		_, err = p.store.CreateWebhookDelivery(ctx, domain.CreateWebhookDeliveryParams{
+			ExternalID:     uuid.Must(uuid.NewV4()),
+			SubscriptionID: subscription.ID,
+			EventType:      event.EventType,
+			Payload:        payload,
+			NextAttemptAt:  time.Now(),
+			OrgID:          orgID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
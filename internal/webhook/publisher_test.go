@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/db/domain"
+	mockdb "github.com/josephlbailey/alert-service/internal/mock"
+)
+
+func TestPublish(t *testing.T) {
+	alert := &domain.Alert{
+		ID:          1,
+		ExternalID:  uuid.Must(uuid.NewV4()),
+		CreatedAt:   time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+		Message:     "disk is full",
+		Severity:    db.SeverityCritical,
+		Status:      db.StatusOpen,
+		Occurrences: 1,
+		Labels:      []byte(`{"host":"db-1"}`),
+		Annotations: []byte(`{}`),
+		OrgID:       db.DefaultOrgID,
+	}
+	snapshot, err := json.Marshal(alert)
+	require.NoError(t, err)
+
+	event := &domain.AlertEvent{
+		ID:              42,
+		ExternalID:      uuid.Must(uuid.NewV4()),
+		AlertID:         alert.ID,
+		AlertExternalID: alert.ExternalID,
+		EventType:       db.EventAlertCreated,
+		Payload:         snapshot,
+		CreatedAt:       time.Date(2024, 7, 1, 12, 0, 1, 0, time.UTC),
+		OrgID:           alert.OrgID,
+	}
+
+	t.Run("queues a delivery per subscription", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListActiveWebhookSubscriptionsForEvent(gomock.Any(), gomock.Eq(domain.ListActiveWebhookSubscriptionsForEventParams{
+				OrgID:     db.DefaultOrgID,
+				EventType: db.EventAlertCreated,
+			})).
+			Times(1).
+			Return([]*domain.WebhookSubscription{{ID: 1, OrgID: db.DefaultOrgID}, {ID: 2, OrgID: db.DefaultOrgID}}, nil)
+		store.EXPECT().
+			CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+			Times(2).
+			DoAndReturn(func(_ context.Context, p domain.CreateWebhookDeliveryParams) (*domain.WebhookDelivery, error) {
+				var body Event
+				require.NoError(t, json.Unmarshal(p.Payload, &body))
+				require.Equal(t, event.ExternalID, body.ID)
+				require.Equal(t, db.EventAlertCreated, body.Type)
+				require.True(t, event.CreatedAt.Equal(body.OccurredAt))
+				require.Equal(t, alert.ExternalID, body.Alert.ExternalID)
+				require.Equal(t, map[string]string{"host": "db-1"}, body.Alert.Labels)
+				require.Equal(t, db.DefaultOrgID, p.OrgID)
+				return &domain.WebhookDelivery{}, nil
+			})
+
+		require.NoError(t, NewPublisher(store).Publish(context.Background(), event))
+	})
+
+	t.Run("subscriptions of other organizations are skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		other := *alert
+		other.OrgID = 2
+		payload, err := json.Marshal(&other)
+		require.NoError(t, err)
+
+		otherEvent := *event
+		otherEvent.Payload = payload
+		otherEvent.OrgID = other.OrgID
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListActiveWebhookSubscriptionsForEvent(gomock.Any(), gomock.Eq(domain.ListActiveWebhookSubscriptionsForEventParams{
+				OrgID:     2,
+				EventType: db.EventAlertCreated,
+			})).
+			Times(1).
+			Return([]*domain.WebhookSubscription{{ID: 1, OrgID: db.DefaultOrgID}, {ID: 2, OrgID: 2}}, nil)
+		store.EXPECT().
+			CreateWebhookDelivery(gomock.Any(), gomock.Cond(func(x any) bool {
+				p := x.(domain.CreateWebhookDeliveryParams)
+				return p.SubscriptionID == 2 && p.OrgID == 2
+			})).
+			Times(1).
+			Return(&domain.WebhookDelivery{}, nil)
+
+		require.NoError(t, NewPublisher(store).Publish(context.Background(), &otherEvent))
+	})
+
+	t.Run("silenced alerts queue nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		silenced := *alert
+		silenced.SilencedUntil = pgtype.Timestamptz{Time: event.CreatedAt.Add(time.Hour), Valid: true}
+		payload, err := json.Marshal(&silenced)
+		require.NoError(t, err)
+
+		silencedEvent := *event
+		silencedEvent.Payload = payload
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListActiveWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]*domain.WebhookSubscription{{ID: 1, OrgID: db.DefaultOrgID}}, nil)
+		store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+		require.NoError(t, NewPublisher(store).Publish(context.Background(), &silencedEvent))
+	})
+
+	t.Run("inhibited alerts queue nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		inhibited := *alert
+		inhibited.InhibitedBy = pgtype.UUID{Bytes: uuid.Must(uuid.NewV4()), Valid: true}
+		payload, err := json.Marshal(&inhibited)
+		require.NoError(t, err)
+
+		inhibitedEvent := *event
+		inhibitedEvent.Payload = payload
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListActiveWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]*domain.WebhookSubscription{{ID: 1, OrgID: db.DefaultOrgID}}, nil)
+		store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+		require.NoError(t, NewPublisher(store).Publish(context.Background(), &inhibitedEvent))
+	})
+
+	t.Run("no subscriptions queues nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListActiveWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]*domain.WebhookSubscription{}, nil)
+		store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+		require.NoError(t, NewPublisher(store).Publish(context.Background(), event))
+	})
+}
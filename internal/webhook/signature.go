@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	HeaderEvent     = "X-Alert-Service-Event"
+	HeaderDelivery  = "X-Alert-Service-Delivery"
+	HeaderTimestamp = "X-Alert-Service-Timestamp"
+	HeaderSignature = "X-Alert-Service-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign computes the signature header value for a delivery. The HMAC-SHA256 is
+// taken over the unix timestamp, a dot and the raw body, so receivers can
+// reject replayed requests by checking the timestamp header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for the timestamp and body.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
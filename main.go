@@ -15,7 +15,11 @@ import (
 	cfg "github.com/josephlbailey/alert-service/config"
 	"github.com/josephlbailey/alert-service/internal/api"
 	"github.com/josephlbailey/alert-service/internal/db"
+	"github.com/josephlbailey/alert-service/internal/escalation"
+	"github.com/josephlbailey/alert-service/internal/outbox"
 	l "github.com/josephlbailey/alert-service/internal/pkg/config"
+	"github.com/josephlbailey/alert-service/internal/purge"
+	"github.com/josephlbailey/alert-service/internal/webhook"
 )
 
 func main() {
@@ -52,7 +56,7 @@ func main() {
 
 	db.AutoMigrate(config, logger)
 
-	store := db.NewAlertServiceStore(dbConn)
+	store := db.NewAlertServiceStore(dbConn, config)
 
 	server := api.NewServer(
 		config,
@@ -68,6 +72,24 @@ func main() {
 
 	addr := fmt.Sprintf(":%s", config.Port)
 
+	// the workers look after the alerts of every organization
+	workerCtx, stopWorkers := context.WithCancel(db.WithAllOrgs(context.Background()))
+	defer stopWorkers()
+
+	// stream clients hear of events only after they are committed: an event
+	// that fails to queue is renumbered when it is retried
+	relay := outbox.NewRelay(config, logger, store, webhook.NewPublisher(store), server.Broker())
+	go relay.Run(workerCtx)
+
+	dispatcher := webhook.NewDispatcher(config, logger, store)
+	go dispatcher.Run(workerCtx)
+
+	scheduler := escalation.NewScheduler(config, logger, store)
+	go scheduler.Run(workerCtx)
+
+	purger := purge.NewPurger(config, logger, store)
+	go purger.Run(workerCtx)
+
 	// add graceful shutdown
 	srv := &http.Server{
 		Addr:    addr,
@@ -89,6 +111,8 @@ func main() {
 	<-quit
 	logger.Info("Shutdown Server...")
 
+	stopWorkers()
+
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)